@@ -1,10 +1,18 @@
 package main
 
 import (
+	"context"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"wardscore-api/internal/config"
 	"wardscore-api/internal/database"
 	"wardscore-api/internal/routes"
+	"wardscore-api/internal/services"
+	"wardscore-api/internal/storage"
+	"wardscore-api/internal/worker"
 
 	"github.com/gin-gonic/gin"
 )
@@ -24,6 +32,9 @@ func main() {
 	// 4. Executar migrations
 	database.Migrate()
 
+	// 4.1 Inicializar storage de replays
+	storage.Connect()
+
 	// 5. Configurar Gin
 	if !config.AppConfig.Debug {
 		gin.SetMode(gin.ReleaseMode)
@@ -34,13 +45,46 @@ func main() {
 	// 6. Configurar todas as rotas
 	routes.SetupRoutes(r)
 
-	// 7. Iniciar servidor
+	// 7. Iniciar workers da fila de processamento (ou rodar cmd/worker separado)
+	var pool *worker.Pool
+	if config.AppConfig.WorkerEnabled {
+		pool = worker.NewPool(services.NewJobService(), services.NewAnalysisService(), services.NewReplayService())
+		pool.Start()
+	}
+
+	// 8. Iniciar servidor
 	log.Printf("🌐 Servidor rodando em http://localhost:%s", config.AppConfig.Port)
 	log.Printf("📊 Health check: http://localhost:%s/health", config.AppConfig.Port)
 	log.Printf("📖 API Docs: http://localhost:%s/api/v1", config.AppConfig.Port)
 	
-	// Iniciar servidor HTTP
-	if err := r.Run(":" + config.AppConfig.Port); err != nil {
-		log.Fatal("❌ Falha ao iniciar servidor:", err)
+	srv := &http.Server{
+		Addr:    ":" + config.AppConfig.Port,
+		Handler: r,
+	}
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal("❌ Falha ao iniciar servidor:", err)
+		}
+	}()
+
+	// 9. Desligamento gracioso: para de aceitar requisições e espera os jobs em andamento
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("🛑 Encerrando servidor...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.AppConfig.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Println("⚠️ Falha ao encerrar servidor HTTP:", err)
 	}
-}
\ No newline at end of file
+	if pool != nil {
+		if err := pool.Shutdown(ctx); err != nil {
+			log.Println("⚠️ Falha ao encerrar workers:", err)
+		}
+	}
+
+	log.Println("👋 Servidor encerrado")
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"wardscore-api/internal/config"
+	"wardscore-api/internal/database"
+	"wardscore-api/internal/services"
+	"wardscore-api/internal/storage"
+	"wardscore-api/internal/worker"
+)
+
+// Worker dedicado ao processamento de replays, para rodar separado da API
+// (nesse caso use WORKER_ENABLED=false na API)
+func main() {
+	log.Println("🚀 Iniciando WardScore worker...")
+
+	config.LoadConfig()
+	database.Connect()
+	database.ConnectRedis()
+	storage.Connect()
+
+	pool := worker.NewPool(services.NewJobService(), services.NewAnalysisService(), services.NewReplayService())
+	pool.Start()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("🛑 Encerrando worker, aguardando jobs em andamento...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.AppConfig.ShutdownTimeout)
+	defer cancel()
+
+	if err := pool.Shutdown(ctx); err != nil {
+		log.Println("⚠️ Falha ao encerrar workers:", err)
+		os.Exit(1)
+	}
+
+	log.Println("👋 Worker encerrado")
+}
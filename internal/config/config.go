@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -15,12 +16,72 @@ type Config struct {
     DatabaseURL string
     RedisURL    string
     JWTSecret   string  // Corrigido para JWTSecret
+    JWTExpiresIn time.Duration
+    RefreshTokenExpiresIn time.Duration
     Debug       bool
 
     // Adicionar campos Riot que estão sendo usados
     RiotClientID     string
     RiotClientSecret string
     RiotAPIKey       string
+
+    // Riot Sign-On (OAuth). As URLs são configuráveis para testes com servidor fake
+    RiotRedirectURI  string
+    RiotAuthorizeURL string
+    RiotTokenURL     string
+    RiotAccountURL   string
+
+    // Timeline de partidas (match-v5), usada para os objetivos épicos.
+    // {region} e {match_id} são substituídos na URL
+    RiotMatchTimelineURL string
+
+    // Upload de replays
+    ReplayStorageDir string
+    MaxReplaySize    int64 // bytes
+
+    // Upload resumível em chunks
+    UploadChunkSize  int64 // bytes
+    UploadSessionTTL time.Duration
+    UploadGCInterval time.Duration
+
+    // Importação em lote (ZIP)
+    MaxImportSize    int64 // bytes do arquivo ZIP
+    MaxImportEntries int
+    ImportTimeout    time.Duration
+
+    // Validade das URLs pré-assinadas de download (backend s3)
+    DownloadURLTTL time.Duration
+
+    // Fila de processamento de replays
+    WorkerEnabled     bool // roda o pool de workers dentro do processo da API
+    WorkerConcurrency int
+    JobLease          time.Duration // job sem heartbeat nesse prazo volta para a fila
+    JobPollInterval   time.Duration
+    JobSweepInterval  time.Duration // reenfileira leases expirados e replays pendentes
+    ShutdownTimeout   time.Duration
+
+    // Política de novas tentativas: backoff exponencial com jitter até JobMaxAttempts
+    JobMaxAttempts    int
+    JobRetryBaseDelay time.Duration
+    JobRetryMaxDelay  time.Duration
+
+    // Baselines do WardScore recalculados a partir das análises
+    ScoringBaselinePercentile float64 // percentil que vira a meta de cada fator
+    ScoringBaselineMinSamples int     // mínimo de análises por role/fila
+
+    // Webhooks
+    WebhookMaxAttempts      int
+    WebhookTimeout          time.Duration
+    WebhookAllowPrivateURLs bool // permite URLs em localhost/rede interna (desenvolvimento)
+
+    // Storage de replays: "local" (ReplayStorageDir) ou "s3" (qualquer serviço compatível)
+    StorageBackend string
+    S3Endpoint     string
+    S3AccessKey    string
+    S3SecretKey    string
+    S3Bucket       string
+    S3Region       string
+    S3UseSSL       bool
 }
 
 var AppConfig Config
@@ -37,10 +98,47 @@ func LoadConfig() {
         DatabaseURL: getEnv("DATABASE_URL", ""),
         RedisURL:    getEnv("REDIS_URL", "redis://localhost:6379"),
         JWTSecret:   getEnv("JWT_SECRET", ""),
+        JWTExpiresIn: getEnvAsDuration("JWT_EXPIRES_IN", 15*time.Minute),
+        RefreshTokenExpiresIn: getEnvAsDuration("REFRESH_TOKEN_EXPIRES_IN", 30*24*time.Hour),
         Debug:       getEnvAsBool("DEBUG", true),
         RiotClientID:     getEnv("RIOT_CLIENT_ID", ""),
         RiotClientSecret: getEnv("RIOT_CLIENT_SECRET", ""),
         RiotAPIKey:       getEnv("RIOT_API_KEY", ""),
+        RiotRedirectURI:  getEnv("RIOT_REDIRECT_URI", "http://localhost:8080/api/v1/auth/riot/callback"),
+        RiotAuthorizeURL: getEnv("RIOT_AUTHORIZE_URL", "https://auth.riotgames.com/authorize"),
+        RiotTokenURL:     getEnv("RIOT_TOKEN_URL", "https://auth.riotgames.com/token"),
+        RiotAccountURL:   getEnv("RIOT_ACCOUNT_URL", "https://americas.api.riotgames.com/riot/account/v1/accounts/me"),
+        RiotMatchTimelineURL: getEnv("RIOT_MATCH_TIMELINE_URL", "https://{region}.api.riotgames.com/lol/match/v5/matches/{match_id}/timeline"),
+        ReplayStorageDir: getEnv("REPLAY_STORAGE_DIR", "./data/replays"),
+        MaxReplaySize:    getEnvAsInt64("MAX_REPLAY_SIZE_MB", 50) * 1024 * 1024,
+        UploadChunkSize:  getEnvAsInt64("UPLOAD_CHUNK_SIZE_MB", 5) * 1024 * 1024,
+        UploadSessionTTL: getEnvAsDuration("UPLOAD_SESSION_TTL", 24*time.Hour),
+        UploadGCInterval: getEnvAsDuration("UPLOAD_GC_INTERVAL", 10*time.Minute),
+        MaxImportSize:    getEnvAsInt64("MAX_IMPORT_SIZE_MB", 500) * 1024 * 1024,
+        MaxImportEntries: int(getEnvAsInt64("MAX_IMPORT_ENTRIES", 100)),
+        ImportTimeout:    getEnvAsDuration("IMPORT_TIMEOUT", 5*time.Minute),
+        DownloadURLTTL:   getEnvAsDuration("DOWNLOAD_URL_TTL", 5*time.Minute),
+        WorkerEnabled:     getEnvAsBool("WORKER_ENABLED", true),
+        WorkerConcurrency: int(getEnvAsInt64("WORKER_CONCURRENCY", 2)),
+        JobLease:          getEnvAsDuration("JOB_LEASE", 2*time.Minute),
+        JobPollInterval:   getEnvAsDuration("JOB_POLL_INTERVAL", time.Second),
+        JobSweepInterval:  getEnvAsDuration("JOB_SWEEP_INTERVAL", time.Minute),
+        ShutdownTimeout:   getEnvAsDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+        JobMaxAttempts:    int(getEnvAsInt64("JOB_MAX_ATTEMPTS", 5)),
+        JobRetryBaseDelay: getEnvAsDuration("JOB_RETRY_BASE_DELAY", 30*time.Second),
+        JobRetryMaxDelay:  getEnvAsDuration("JOB_RETRY_MAX_DELAY", 30*time.Minute),
+        ScoringBaselinePercentile: float64(getEnvAsInt64("SCORING_BASELINE_PERCENTILE", 75)),
+        ScoringBaselineMinSamples: int(getEnvAsInt64("SCORING_BASELINE_MIN_SAMPLES", 30)),
+        WebhookMaxAttempts:      int(getEnvAsInt64("WEBHOOK_MAX_ATTEMPTS", 8)),
+        WebhookTimeout:          getEnvAsDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+        WebhookAllowPrivateURLs: getEnvAsBool("WEBHOOK_ALLOW_PRIVATE_URLS", false),
+        StorageBackend:   getEnv("STORAGE_BACKEND", "local"),
+        S3Endpoint:       getEnv("S3_ENDPOINT", ""),
+        S3AccessKey:      getEnv("S3_ACCESS_KEY", ""),
+        S3SecretKey:      getEnv("S3_SECRET_KEY", ""),
+        S3Bucket:         getEnv("S3_BUCKET", "wardscore-replays"),
+        S3Region:         getEnv("S3_REGION", "us-east-1"),
+        S3UseSSL:         getEnvAsBool("S3_USE_SSL", true),
     }
 
 
@@ -71,4 +169,20 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 
-}
\ No newline at end of file
+}
+
+func getEnvAsInt64(key string, defaultValue int64) int64 {
+	valStr := getEnv(key, "")
+	if val, err := strconv.ParseInt(valStr, 10, 64); err == nil {
+		return val
+	}
+	return defaultValue
+}
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	valStr := getEnv(key, "")
+	if val, err := time.ParseDuration(valStr); err == nil {
+		return val
+	}
+	return defaultValue
+}
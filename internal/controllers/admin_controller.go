@@ -0,0 +1,306 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"wardscore-api/internal/models"
+	"wardscore-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminController gerencia operações administrativas
+type AdminController struct {
+	adminService    *services.AdminService
+	userService     *services.UserService
+	sessionService  *services.SessionService
+	analysisService *services.AnalysisService
+	replayService   *services.ReplayService
+	jobService      *services.JobService
+}
+
+// NewAdminController cria nova instância do controller
+func NewAdminController(adminService *services.AdminService, userService *services.UserService, sessionService *services.SessionService, analysisService *services.AnalysisService, replayService *services.ReplayService, jobService *services.JobService) *AdminController {
+	return &AdminController{
+		adminService:    adminService,
+		userService:     userService,
+		sessionService:  sessionService,
+		analysisService: analysisService,
+		replayService:   replayService,
+		jobService:      jobService,
+	}
+}
+
+// BanUser bane o usuário e revoga todas as suas sessões
+// POST /api/v1/admin/users/:id/ban
+func (ac *AdminController) BanUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "ID inválido",
+		})
+		return
+	}
+
+	var req struct {
+		Reason string `json:"reason" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	user, err := ac.userService.Ban(uint(id), req.Reason)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Usuário não encontrado",
+		})
+		return
+	}
+
+	ac.sessionService.RevokeAllForUser(user.ID, "")
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    user,
+		"message": "Usuário banido com sucesso",
+	})
+}
+
+// UnbanUser remove o banimento do usuário
+// DELETE /api/v1/admin/users/:id/ban
+func (ac *AdminController) UnbanUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "ID inválido",
+		})
+		return
+	}
+
+	user, err := ac.userService.Unban(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Usuário não encontrado",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    user,
+		"message": "Banimento removido com sucesso",
+	})
+}
+
+// SetUserRole altera o papel do usuário
+// PUT /api/v1/admin/users/:id/role
+func (ac *AdminController) SetUserRole(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "ID inválido",
+		})
+		return
+	}
+
+	var req struct {
+		Role string `json:"role" binding:"required,oneof=user pro coach admin"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	user, err := ac.userService.SetRole(uint(id), models.UserRole(req.Role))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Falha ao alterar papel: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    user,
+		"message": "Papel atualizado com sucesso",
+	})
+}
+
+// ReprocessReplay descarta a análise atual e enfileira o replay de novo (202 com o ID do job)
+// POST /api/v1/admin/replays/:id/reprocess
+func (ac *AdminController) ReprocessReplay(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "ID inválido",
+		})
+		return
+	}
+
+	replay, err := ac.analysisService.ResetForReprocess(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Falha ao reprocessar replay: " + err.Error(),
+		})
+		return
+	}
+
+	job, err := ac.jobService.EnqueueReplay(replay)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"success": false,
+			"error":   "Falha ao enfileirar replay: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"data":    job,
+		"message": "Replay enfileirado para reprocessamento",
+	})
+}
+
+// GetDeadLetters lista replays cujo processamento esgotou as tentativas
+// GET /api/v1/admin/dead-letter?page=1&limit=10
+func (ac *AdminController) GetDeadLetters(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	replays, total, err := ac.replayService.GetDeadLetters(page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Falha ao buscar replays: " + err.Error(),
+		})
+		return
+	}
+
+	totalPages := (total + int64(limit) - 1) / int64(limit)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    replays,
+		"meta": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": totalPages,
+			"has_next":    page < int(totalPages),
+			"has_prev":    page > 1,
+		},
+	})
+}
+
+// GetDeadLetter detalha um replay em dead letter (tentativas e último erro)
+// GET /api/v1/admin/dead-letter/:id
+func (ac *AdminController) GetDeadLetter(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "ID inválido",
+		})
+		return
+	}
+
+	replay, err := ac.replayService.GetByID(uint(id))
+	if err != nil || !replay.IsDeadLetter() {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Replay não encontrado em dead letter",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    replay,
+	})
+}
+
+// RequeueDeadLetter zera as tentativas do replay e o enfileira de novo (202 com o job)
+// POST /api/v1/admin/dead-letter/:id/requeue
+func (ac *AdminController) RequeueDeadLetter(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "ID inválido",
+		})
+		return
+	}
+
+	replay, err := ac.replayService.ResetForRetry(uint(id))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrReplayNotFound) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, services.ErrReplayNotDeadLetter) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	job, err := ac.jobService.EnqueueReplay(replay)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"success": false,
+			"error":   "Falha ao enfileirar replay: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"data":    job,
+		"message": "Replay reenfileirado",
+	})
+}
+
+// GetStats retorna estatísticas do sistema
+// GET /api/v1/admin/stats
+func (ac *AdminController) GetStats(c *gin.Context) {
+	stats, err := ac.adminService.GetSystemStats()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Falha ao buscar estatísticas: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    stats,
+	})
+}
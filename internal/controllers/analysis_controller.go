@@ -1,10 +1,15 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"wardscore-api/internal/heatmap"
+	"wardscore-api/internal/middleware"
 	"wardscore-api/internal/models"
 	"wardscore-api/internal/services"
+	"wardscore-api/internal/timeline"
+	"wardscore-api/internal/zones"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,12 +17,16 @@ import (
 // AnalysisController gerencia operações relacionadas às análises
 type AnalysisController struct {
     analysisService *services.AnalysisService
+    replayService   *services.ReplayService
+    jobService      *services.JobService
 }
 
 // NewAnalysisController cria nova instância do controller
-func NewAnalysisController(analysisService *services.AnalysisService) *AnalysisController {
+func NewAnalysisController(analysisService *services.AnalysisService, replayService *services.ReplayService, jobService *services.JobService) *AnalysisController {
     return &AnalysisController{
         analysisService: analysisService,
+        replayService:   replayService,
+        jobService:      jobService,
     }
 }
 
@@ -34,8 +43,9 @@ func (ac *AnalysisController) GetAnalysis(c *gin.Context) {
         return
     }
 
+    // Análises de outros usuários respondem como inexistentes
     analysis, err := ac.analysisService.GetByID(uint(id))
-    if err != nil {
+    if err != nil || !middleware.CanAccess(c, analysis.UserID) {
         c.JSON(http.StatusNotFound, gin.H{
             "success": false,
             "error":   "Análise não encontrada",
@@ -49,7 +59,233 @@ func (ac *AnalysisController) GetAnalysis(c *gin.Context) {
     })
 }
 
-// ProcessReplay processa um replay e gera análise
+// GetAnalysisBreakdown detalha o WardScore da análise: valor, meta, peso e
+// pontos de cada fator, pontos perdidos e faixas de rank
+// GET /api/v1/analysis/:id/breakdown
+func (ac *AnalysisController) GetAnalysisBreakdown(c *gin.Context) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error":   "ID inválido",
+        })
+        return
+    }
+
+    analysis, err := ac.analysisService.GetByID(uint(id))
+    if err != nil || !middleware.CanAccess(c, analysis.UserID) {
+        c.JSON(http.StatusNotFound, gin.H{
+            "success": false,
+            "error":   "Análise não encontrada",
+        })
+        return
+    }
+
+    breakdown, err := ac.analysisService.GetBreakdown(analysis)
+    if err != nil {
+        status := http.StatusInternalServerError
+        if errors.Is(err, services.ErrBreakdownUnavailable) {
+            status = http.StatusNotFound
+        }
+        c.JSON(status, gin.H{
+            "success": false,
+            "error":   err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data":    breakdown,
+    })
+}
+
+// GetAnalysisHeatmap retorna o heatmap das wards da análise em JSON (grades
+// por lado e fase) ou desenhado como overlay do minimapa
+// GET /api/v1/analysis/:id/heatmap?format=json|svg|png&phase=early&size=512
+func (ac *AnalysisController) GetAnalysisHeatmap(c *gin.Context) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error":   "ID inválido",
+        })
+        return
+    }
+
+    analysis, err := ac.analysisService.GetByID(uint(id))
+    if err != nil || !middleware.CanAccess(c, analysis.UserID) {
+        c.JSON(http.StatusNotFound, gin.H{
+            "success": false,
+            "error":   "Análise não encontrada",
+        })
+        return
+    }
+
+    h, err := ac.analysisService.GetHeatmap(analysis)
+    if err != nil {
+        status := http.StatusInternalServerError
+        if errors.Is(err, services.ErrHeatmapUnavailable) {
+            status = http.StatusNotFound
+        }
+        c.JSON(status, gin.H{
+            "success":            false,
+            "error":              err.Error(),
+            "timeline_available": analysis.TimelineAvailable,
+        })
+        return
+    }
+
+    respondHeatmap(c, h)
+}
+
+// GetUserHeatmap soma os heatmaps de todas as partidas do usuário, em JSON
+// ou desenhado como overlay do minimapa
+// GET /api/v1/analysis/user/:user_id/heatmap?format=json|svg|png&side=blue&phase=early&size=512
+func (ac *AnalysisController) GetUserHeatmap(c *gin.Context) {
+    userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error":   "User ID inválido",
+        })
+        return
+    }
+
+    h, err := ac.analysisService.GetUserHeatmap(uint(userID))
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "success": false,
+            "error":   "Falha ao gerar heatmap: " + err.Error(),
+        })
+        return
+    }
+
+    respondHeatmap(c, h)
+}
+
+// respondHeatmap responde o heatmap no formato pedido. SVG e PNG desenham a
+// soma das grades do lado e da fase informados (vazios valem todos).
+func respondHeatmap(c *gin.Context, h *heatmap.Heatmap) {
+    side := c.Query("side")
+    phase := c.Query("phase")
+    if (side != "" && side != heatmap.SideBlue && side != heatmap.SideRed) ||
+        (phase != "" && phase != heatmap.PhaseEarly && phase != heatmap.PhaseMid && phase != heatmap.PhaseLate) {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error":   "Lado ou fase inválidos",
+        })
+        return
+    }
+
+    size, _ := strconv.Atoi(c.DefaultQuery("size", strconv.Itoa(heatmap.DefaultImageSize)))
+    if size < heatmap.MinImageSize || size > heatmap.MaxImageSize {
+        size = heatmap.DefaultImageSize
+    }
+
+    switch c.DefaultQuery("format", "json") {
+    case "json":
+        c.JSON(http.StatusOK, gin.H{
+            "success":            true,
+            "data":               h,
+            "timeline_available": h.Games > 0,
+        })
+    case "svg":
+        c.Data(http.StatusOK, "image/svg+xml", heatmap.RenderSVG(h.Filter(side, phase), size))
+    case "png":
+        image, err := heatmap.RenderPNG(h.Filter(side, phase), size)
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{
+                "success": false,
+                "error":   "Falha ao gerar imagem: " + err.Error(),
+            })
+            return
+        }
+        c.Data(http.StatusOK, "image/png", image)
+    default:
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error":   "Formato inválido (use json, svg ou png)",
+        })
+    }
+}
+
+// GetZoneCatalog retorna as zonas do mapa usadas na visão por zona das
+// análises (catálogo mais recente ou ?version=)
+// GET /api/v1/map/zones?version=1
+func (ac *AnalysisController) GetZoneCatalog(c *gin.Context) {
+    catalog := zones.Current()
+    if param := c.Query("version"); param != "" {
+        version, err := strconv.Atoi(param)
+        found, ok := zones.Get(version)
+        if err != nil || !ok {
+            c.JSON(http.StatusNotFound, gin.H{
+                "success": false,
+                "error":   "Versão do catálogo de zonas não encontrada",
+            })
+            return
+        }
+        catalog = found
+    }
+
+    versions := make([]int, len(zones.Catalogs))
+    for i, item := range zones.Catalogs {
+        versions[i] = item.Version
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success":  true,
+        "data":     catalog,
+        "versions": versions,
+    })
+}
+
+// GetReplayAnalysis busca a análise do replay na versão mais recente do modelo
+// de score ou na versão informada
+// GET /api/v1/analysis/replay/:replay_id?version=2
+func (ac *AnalysisController) GetReplayAnalysis(c *gin.Context) {
+    replayID, err := strconv.ParseUint(c.Param("replay_id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error":   "Replay ID inválido",
+        })
+        return
+    }
+
+    version, ok := parseScoringVersion(c)
+    if !ok {
+        return
+    }
+
+    replay, err := ac.replayService.GetByID(uint(replayID))
+    if err != nil || !middleware.CanAccess(c, replay.UserID) {
+        c.JSON(http.StatusNotFound, gin.H{
+            "success": false,
+            "error":   "Replay não encontrado",
+        })
+        return
+    }
+
+    analysis, err := ac.analysisService.GetByReplayID(replay.ID, version)
+    if err != nil {
+        c.JSON(http.StatusNotFound, gin.H{
+            "success": false,
+            "error":   "Análise não encontrada",
+        })
+        return
+    }
+
+    versions, _ := ac.analysisService.GetVersionsByReplayID(replay.ID)
+
+    c.JSON(http.StatusOK, gin.H{
+        "success":  true,
+        "data":     analysis,
+        "versions": versions,
+    })
+}
+
+// ProcessReplay enfileira o processamento do replay (202 com o job)
 // POST /api/v1/analysis/process/:replay_id
 func (ac *AnalysisController) ProcessReplay(c *gin.Context) {
     replayIDParam := c.Param("replay_id")
@@ -62,43 +298,107 @@ func (ac *AnalysisController) ProcessReplay(c *gin.Context) {
         return
     }
 
-    userID := c.GetHeader("X-User-ID")
-    if userID == "" {
-        c.JSON(http.StatusBadRequest, gin.H{
+    replay, err := ac.replayService.GetByID(uint(replayID))
+    if err != nil || !middleware.CanAccess(c, replay.UserID) {
+        c.JSON(http.StatusNotFound, gin.H{
+            "success": false,
+            "error":   "Replay não encontrado",
+        })
+        return
+    }
+
+    if replay.IsProcessed() {
+        c.JSON(http.StatusConflict, gin.H{
             "success": false,
-            "error":   "User ID é obrigatório no header X-User-ID",
+            "error":   "Replay já foi processado",
         })
         return
     }
 
-    _, err = strconv.ParseUint(userID, 10, 32)
+    if replay.IsDeadLetter() {
+        c.JSON(http.StatusConflict, gin.H{
+            "success": false,
+            "error":   "Processamento do replay falhou definitivamente: " + replay.LastError,
+        })
+        return
+    }
+
+    // Processamento roda nos workers; acompanhe pelo job
+    job, err := ac.jobService.EnqueueReplay(replay)
+    if err != nil {
+        c.JSON(http.StatusServiceUnavailable, gin.H{
+            "success": false,
+            "error":   "Falha ao enfileirar replay: " + err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusAccepted, gin.H{
+        "success": true,
+        "data":    job,
+        "message": "Replay enfileirado para processamento",
+    })
+}
+
+// PutReplayTimeline recebe a timeline de wards e objetivos do replay (JSON no
+// formato do pacote timeline, gerado por um extrator de replays) e enfileira
+// o reprocessamento com ela (202 com o job)
+// PUT /api/v1/replays/:id/timeline
+func (ac *AnalysisController) PutReplayTimeline(c *gin.Context) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
     if err != nil {
         c.JSON(http.StatusBadRequest, gin.H{
             "success": false,
-            "error":   "User ID inválido",
+            "error":   "ID inválido",
         })
         return
     }
 
-    // Processar replay e criar análise
-    analysis, err := ac.analysisService.ProcessReplay(uint(replayID))
+    replay, err := ac.replayService.GetByID(uint(id))
+    if err != nil || !middleware.CanAccess(c, replay.UserID) {
+        c.JSON(http.StatusNotFound, gin.H{
+            "success": false,
+            "error":   "Replay não encontrado",
+        })
+        return
+    }
+
+    replay, err = ac.analysisService.AttachTimeline(replay, c.Request.Body)
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{
+        var invalidErr *timeline.InvalidError
+        status := http.StatusInternalServerError
+        switch {
+        case errors.As(err, &invalidErr):
+            status = http.StatusBadRequest
+        case errors.Is(err, services.ErrReplayProcessing), errors.Is(err, services.ErrReplayWithoutFile):
+            status = http.StatusConflict
+        }
+        c.JSON(status, gin.H{
             "success": false,
-            "error":   "Falha ao processar replay: " + err.Error(),
+            "error":   err.Error(),
         })
         return
     }
 
-    c.JSON(http.StatusCreated, gin.H{
+    job, err := ac.jobService.EnqueueReplay(replay)
+    if err != nil {
+        c.JSON(http.StatusServiceUnavailable, gin.H{
+            "success": false,
+            "error":   "Falha ao enfileirar replay: " + err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusAccepted, gin.H{
         "success": true,
-        "data":    analysis,
-        "message": "Replay processado com sucesso! Análise gerada.",
+        "data":    job,
+        "message": "Timeline recebida; replay enfileirado para reprocessamento",
     })
 }
 
-// GetUserAnalyses busca todas as análises de um usuário
-// GET /api/v1/analysis/user/:user_id?page=1&limit=10
+// GetUserAnalyses busca todas as análises de um usuário (uma por replay, na
+// versão mais recente do modelo de score ou na versão informada)
+// GET /api/v1/analysis/user/:user_id?page=1&limit=10&version=2
 func (ac *AnalysisController) GetUserAnalyses(c *gin.Context) {
     userIDParam := c.Param("user_id")
     userID, err := strconv.ParseUint(userIDParam, 10, 32)
@@ -120,7 +420,12 @@ func (ac *AnalysisController) GetUserAnalyses(c *gin.Context) {
         limit = 10
     }
 
-    analyses, err := ac.analysisService.GetByUserID(uint(userID))
+    version, ok := parseScoringVersion(c)
+    if !ok {
+        return
+    }
+
+    analyses, err := ac.analysisService.GetByUserID(uint(userID), version)
     if err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{
             "success": false,
@@ -159,4 +464,22 @@ func (ac *AnalysisController) GetUserAnalyses(c *gin.Context) {
             "has_prev":    hasPrev,
         },
     })
-} 
\ No newline at end of file
+} 
+
+// parseScoringVersion lê ?version= (vazio ou "latest" para a versão mais recente)
+func parseScoringVersion(c *gin.Context) (int, bool) {
+    param := c.Query("version")
+    if param == "" || param == "latest" {
+        return services.ScoringVersionLatest, true
+    }
+
+    version, err := strconv.Atoi(param)
+    if err != nil || version < 0 {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error":   "Versão inválida",
+        })
+        return 0, false
+    }
+    return version, true
+}
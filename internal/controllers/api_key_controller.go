@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"wardscore-api/internal/middleware"
+	"wardscore-api/internal/models"
+	"wardscore-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyController gerencia as API keys pessoais do usuário
+type APIKeyController struct {
+	apiKeyService *services.APIKeyService
+}
+
+// NewAPIKeyController cria nova instância do controller
+func NewAPIKeyController(apiKeyService *services.APIKeyService) *APIKeyController {
+	return &APIKeyController{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// CreateAPIKey cria uma API key; a chave só é exibida nesta resposta
+// POST /api/v1/api-keys
+func (akc *APIKeyController) CreateAPIKey(c *gin.Context) {
+	user, ok := middleware.GetUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "Usuário não autenticado",
+		})
+		return
+	}
+
+	var req struct {
+		Name      string              `json:"name" binding:"required,max=100"`
+		Scopes    []models.Permission `json:"scopes" binding:"required"`
+		ExpiresAt *time.Time          `json:"expires_at"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	apiKey, rawKey, err := akc.apiKeyService.Create(user, req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Falha ao criar API key: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    apiKey,
+		"key":     rawKey,
+		"message": "API key criada. Guarde a chave agora, ela não será exibida novamente.",
+	})
+}
+
+// GetAPIKeys lista as API keys do usuário com dados de uso
+// GET /api/v1/api-keys
+func (akc *APIKeyController) GetAPIKeys(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	keys, err := akc.apiKeyService.GetByUserID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Falha ao buscar API keys: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    keys,
+	})
+}
+
+// RevokeAPIKey revoga uma API key do usuário
+// DELETE /api/v1/api-keys/:id
+func (akc *APIKeyController) RevokeAPIKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "ID inválido",
+		})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	if err := akc.apiKeyService.Revoke(userID, uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "API key não encontrada",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "API key revogada com sucesso",
+	})
+}
@@ -0,0 +1,341 @@
+package controllers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"wardscore-api/internal/config"
+	"wardscore-api/internal/middleware"
+	"wardscore-api/internal/models"
+	"wardscore-api/internal/services"
+	"wardscore-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthController gerencia login via Riot Sign-On e emissão de tokens
+type AuthController struct {
+	riotAuthService *services.RiotAuthService
+	userService     *services.UserService
+	sessionService  *services.SessionService
+}
+
+// NewAuthController cria nova instância do controller
+func NewAuthController(riotAuthService *services.RiotAuthService, userService *services.UserService, sessionService *services.SessionService) *AuthController {
+	return &AuthController{
+		riotAuthService: riotAuthService,
+		userService:     userService,
+		sessionService:  sessionService,
+	}
+}
+
+// riotStateCookie amarra o state do OAuth ao navegador que iniciou o fluxo,
+// para que um callback forjado com o state de outra pessoa seja recusado
+const riotStateCookie = "riot_oauth_state"
+
+// RiotLogin inicia o fluxo OAuth redirecionando para o Riot Sign-On
+// GET /api/v1/auth/riot
+func (ac *AuthController) RiotLogin(c *gin.Context) {
+	authorizeURL, state, err := ac.riotAuthService.AuthorizeURL(0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Falha ao iniciar login Riot: " + err.Error(),
+		})
+		return
+	}
+
+	setRiotStateCookie(c, state, int(services.OAuthStateTTL.Seconds()))
+	c.Redirect(http.StatusFound, authorizeURL)
+}
+
+// RiotLink gera URL de autorização para vincular a conta Riot ao usuário autenticado
+// POST /api/v1/auth/riot/link
+func (ac *AuthController) RiotLink(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "Usuário não autenticado",
+		})
+		return
+	}
+
+	authorizeURL, state, err := ac.riotAuthService.AuthorizeURL(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Falha ao iniciar vínculo Riot: " + err.Error(),
+		})
+		return
+	}
+
+	setRiotStateCookie(c, state, int(services.OAuthStateTTL.Seconds()))
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"authorize_url": authorizeURL,
+		},
+	})
+}
+
+// RiotCallback troca o authorization code, resolve a conta Riot e cria/vincula o usuário
+// GET /api/v1/auth/riot/callback?code=...&state=...
+func (ac *AuthController) RiotCallback(c *gin.Context) {
+	if oauthErr := c.Query("error"); oauthErr != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Login Riot negado: " + oauthErr,
+		})
+		return
+	}
+
+	code := c.Query("code")
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Parâmetro code é obrigatório",
+		})
+		return
+	}
+
+	// O state precisa ser o mesmo gravado no cookie deste navegador
+	state := c.Query("state")
+	cookieState, _ := c.Cookie(riotStateCookie)
+	setRiotStateCookie(c, "", -1)
+	if cookieState == "" || subtle.ConstantTimeCompare([]byte(cookieState), []byte(state)) != 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "state não confere com o navegador que iniciou o login",
+		})
+		return
+	}
+
+	linkUserID, err := ac.riotAuthService.ConsumeState(state)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	riotToken, err := ac.riotAuthService.ExchangeCode(code)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{
+			"success": false,
+			"error":   "Falha na troca do código: " + err.Error(),
+		})
+		return
+	}
+
+	account, err := ac.riotAuthService.GetAccount(riotToken.AccessToken)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{
+			"success": false,
+			"error":   "Falha ao buscar conta Riot: " + err.Error(),
+		})
+		return
+	}
+
+	user, created, err := ac.userService.LoginWithRiotAccount(account, linkUserID)
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   "Falha ao vincular conta Riot: " + err.Error(),
+		})
+		return
+	}
+
+	ac.respondWithTokens(c, user, created)
+}
+
+// Refresh troca um refresh token por um novo par de tokens (rotação)
+// POST /api/v1/auth/refresh
+func (ac *AuthController) Refresh(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	session, refreshToken, err := ac.sessionService.Rotate(req.RefreshToken, c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		if errors.Is(err, services.ErrRefreshTokenInvalid) || errors.Is(err, services.ErrRefreshTokenReused) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"error":   err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Falha ao renovar sessão: " + err.Error(),
+		})
+		return
+	}
+
+	user, err := ac.userService.GetByID(session.UserID)
+	if err != nil {
+		ac.sessionService.Revoke(session.ID)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"error":   "Usuário não encontrado",
+		})
+		return
+	}
+
+	ac.respondWithSession(c, http.StatusOK, user, session, refreshToken)
+}
+
+// Logout revoga a sessão atual
+// POST /api/v1/auth/logout
+func (ac *AuthController) Logout(c *gin.Context) {
+	if err := ac.sessionService.Revoke(middleware.GetSessionID(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Falha ao encerrar sessão: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Sessão encerrada com sucesso",
+	})
+}
+
+// GetSessions lista os dispositivos com sessão ativa
+// GET /api/v1/auth/sessions
+func (ac *AuthController) GetSessions(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+	currentSessionID := middleware.GetSessionID(c)
+
+	sessions, err := ac.sessionService.ListByUser(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Falha ao buscar sessões: " + err.Error(),
+		})
+		return
+	}
+
+	data := make([]gin.H, 0, len(sessions))
+	for _, session := range sessions {
+		data = append(data, gin.H{
+			"id":           session.ID,
+			"user_agent":   session.UserAgent,
+			"ip":           session.IP,
+			"created_at":   session.CreatedAt,
+			"last_used_at": session.LastUsedAt,
+			"expires_at":   session.ExpiresAt,
+			"current":      session.ID == currentSessionID,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    data,
+	})
+}
+
+// RevokeSession revoga uma sessão específica do usuário
+// DELETE /api/v1/auth/sessions/:id
+func (ac *AuthController) RevokeSession(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	if err := ac.sessionService.RevokeForUser(userID, c.Param("id")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Sessão não encontrada",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Sessão revogada com sucesso",
+	})
+}
+
+// RevokeOtherSessions revoga todas as sessões do usuário exceto a atual
+// DELETE /api/v1/auth/sessions
+func (ac *AuthController) RevokeOtherSessions(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	revoked, err := ac.sessionService.RevokeAllForUser(userID, middleware.GetSessionID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Falha ao revogar sessões: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"revoked": revoked,
+		},
+		"message": "Outras sessões revogadas com sucesso",
+	})
+}
+
+// respondWithTokens abre uma nova sessão para o usuário e retorna os tokens
+func (ac *AuthController) respondWithTokens(c *gin.Context, user *models.User, created bool) {
+	session, refreshToken, err := ac.sessionService.Create(user.ID, c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Falha ao criar sessão: " + err.Error(),
+		})
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+
+	ac.respondWithSession(c, status, user, session, refreshToken)
+}
+
+func (ac *AuthController) respondWithSession(c *gin.Context, status int, user *models.User, session *services.Session, refreshToken string) {
+	accessToken, expiresAt, err := utils.GenerateJWT(user.ID, session.ID, config.AppConfig.JWTSecret, config.AppConfig.JWTExpiresIn)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Falha ao gerar token: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(status, gin.H{
+		"success": true,
+		"data":    user,
+		"auth": gin.H{
+			"access_token":       accessToken,
+			"token_type":         "Bearer",
+			"expires_at":         expiresAt,
+			"refresh_token":      refreshToken,
+			"refresh_expires_at": session.ExpiresAt,
+			"session_id":         session.ID,
+		},
+	})
+}
+
+// setRiotStateCookie grava (ou, com maxAge negativo, remove) o cookie do state.
+// O cookie vale só para as rotas do login Riot e é Secure quando a requisição
+// chegou por HTTPS.
+func setRiotStateCookie(c *gin.Context, state string, maxAge int) {
+	secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(riotStateCookie, state, maxAge, "/api/v1/auth/riot", "", secure, true)
+}
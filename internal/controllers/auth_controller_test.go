@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"wardscore-api/internal/config"
+	"wardscore-api/internal/database"
+	"wardscore-api/internal/services"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// newRiotAuthTestRouter monta as rotas do login Riot com Redis em memória e
+// um endpoint de token que recusa qualquer code
+func newRiotAuthTestRouter(t *testing.T) (*gin.Engine, *miniredis.Miniredis) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	redisServer := miniredis.RunT(t)
+	previousRedis := database.RedisClient
+	database.RedisClient = redis.NewClient(&redis.Options{Addr: redisServer.Addr()})
+
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant"}`))
+	}))
+
+	previousConfig := config.AppConfig
+	config.AppConfig.RiotClientID = "wardscore"
+	config.AppConfig.RiotAuthorizeURL = "https://auth.example/authorize"
+	config.AppConfig.RiotTokenURL = tokenServer.URL
+
+	t.Cleanup(func() {
+		tokenServer.Close()
+		database.RedisClient.Close()
+		database.RedisClient = previousRedis
+		config.AppConfig = previousConfig
+	})
+
+	ac := NewAuthController(services.NewRiotAuthService(), nil, nil)
+	router := gin.New()
+	router.GET("/api/v1/auth/riot", ac.RiotLogin)
+	router.GET("/api/v1/auth/riot/callback", ac.RiotCallback)
+	return router, redisServer
+}
+
+func callback(router *gin.Engine, state string, cookie *http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/riot/callback?code=abc&state="+url.QueryEscape(state), nil)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRiotLoginSetsStateCookie(t *testing.T) {
+	router, redisServer := newRiotAuthTestRouter(t)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/auth/riot", nil))
+	if rec.Code != http.StatusFound {
+		t.Fatalf("status = %d, esperado 302", rec.Code)
+	}
+
+	location, _ := url.Parse(rec.Header().Get("Location"))
+	state := location.Query().Get("state")
+	if state == "" || !redisServer.Exists("oauth_state:"+state) {
+		t.Fatalf("state %q não gravado no Redis", state)
+	}
+
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("cookies = %v, esperado só o do state", cookies)
+	}
+	cookie := cookies[0]
+	if cookie.Name != riotStateCookie || cookie.Value != state {
+		t.Errorf("cookie = %s=%s, esperado %s=%s", cookie.Name, cookie.Value, riotStateCookie, state)
+	}
+	if !cookie.HttpOnly || cookie.SameSite != http.SameSiteLaxMode || cookie.Path != "/api/v1/auth/riot" || cookie.Secure {
+		t.Errorf("atributos do cookie = %+v", cookie)
+	}
+	if cookie.MaxAge != int(services.OAuthStateTTL.Seconds()) {
+		t.Errorf("Max-Age = %d, esperado %d", cookie.MaxAge, int(services.OAuthStateTTL.Seconds()))
+	}
+}
+
+func TestRiotCallbackChecksStateCookie(t *testing.T) {
+	router, redisServer := newRiotAuthTestRouter(t)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/auth/riot", nil))
+	cookie := rec.Result().Cookies()[0]
+	state := cookie.Value
+
+	// Callback sem o cookie, ou com o de outro login, é recusado sem gastar o state
+	if rec := callback(router, state, nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("sem cookie: status = %d, esperado 400", rec.Code)
+	}
+	if rec := callback(router, state, &http.Cookie{Name: riotStateCookie, Value: "outro-state"}); rec.Code != http.StatusBadRequest {
+		t.Errorf("cookie de outro login: status = %d, esperado 400", rec.Code)
+	}
+	if !redisServer.Exists("oauth_state:" + state) {
+		t.Fatalf("state consumido por callback recusado")
+	}
+
+	// Com o cookie certo o state é consumido e o code vai para o endpoint de token
+	rec = callback(router, state, cookie)
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("com cookie: status = %d, esperado 502 da troca do code", rec.Code)
+	}
+	if redisServer.Exists("oauth_state:" + state) {
+		t.Errorf("state não consumido")
+	}
+	cleared := rec.Result().Cookies()
+	if len(cleared) != 1 || cleared[0].Name != riotStateCookie || cleared[0].MaxAge >= 0 {
+		t.Errorf("cookie do state não removido: %v", cleared)
+	}
+
+	// Reusar o mesmo state falha mesmo com o cookie
+	if rec := callback(router, state, cookie); rec.Code != http.StatusBadRequest {
+		t.Errorf("state reutilizado: status = %d, esperado 400", rec.Code)
+	}
+}
@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"wardscore-api/internal/insights"
+	"wardscore-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InsightController gerencia o catálogo de regras de insight
+type InsightController struct {
+	insightService *services.InsightService
+}
+
+// NewInsightController cria nova instância do controller
+func NewInsightController(insightService *services.InsightService) *InsightController {
+	return &InsightController{
+		insightService: insightService,
+	}
+}
+
+// GetRules lista o catálogo de regras em uso e as métricas disponíveis
+// GET /api/v1/admin/insights/rules
+func (ic *InsightController) GetRules(c *gin.Context) {
+	rules, err := ic.insightService.Rules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Falha ao buscar regras: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rules,
+		"metrics": insights.Metrics,
+	})
+}
+
+// SetRule cria ou substitui uma regra (inclusive as embutidas)
+// PUT /api/v1/admin/insights/rules/:key
+func (ic *InsightController) SetRule(c *gin.Context) {
+	var rule insights.Rule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Dados inválidos: " + err.Error(),
+		})
+		return
+	}
+	rule.Key = c.Param("key")
+
+	saved, err := ic.insightService.Set(rule)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Falha ao salvar regra: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    saved,
+		"message": "Regra salva. Vale para as próximas análises.",
+	})
+}
+
+// DeleteRule remove uma regra salva; a embutida de mesma key volta a valer
+// DELETE /api/v1/admin/insights/rules/:key
+func (ic *InsightController) DeleteRule(c *gin.Context) {
+	if err := ic.insightService.Delete(c.Param("key")); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInsightRuleNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Regra removida com sucesso",
+	})
+}
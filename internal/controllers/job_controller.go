@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"wardscore-api/internal/middleware"
+	"wardscore-api/internal/models"
+	"wardscore-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JobController expõe o andamento dos jobs da fila de processamento
+type JobController struct {
+	jobService *services.JobService
+}
+
+// NewJobController cria nova instância do controller
+func NewJobController(jobService *services.JobService) *JobController {
+	return &JobController{
+		jobService: jobService,
+	}
+}
+
+// GetJob retorna o status de um job de processamento
+// GET /api/v1/jobs/:id
+func (jc *JobController) GetJob(c *gin.Context) {
+	job, err := jc.jobService.Get(c.Param("id"))
+	if err != nil || !middleware.CanAccess(c, job.UserID) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Job não encontrado",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    job,
+	})
+}
+
+// enqueueProcessing enfileira o processamento do replay e retorna o ID do job.
+// Se a fila estiver indisponível o replay continua como uploaded e é
+// enfileirado depois pela varredura dos workers.
+func enqueueProcessing(jobService *services.JobService, replay *models.Replay) string {
+	job, err := jobService.EnqueueReplay(replay)
+	if err != nil {
+		log.Printf("⚠️ Falha ao enfileirar replay %d: %v", replay.ID, err)
+		return ""
+	}
+	return job.ID
+}
@@ -1,79 +1,149 @@
 package controllers
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
 	"net/http"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
+	"wardscore-api/internal/config"
+	"wardscore-api/internal/middleware"
 	"wardscore-api/internal/models"
+	"wardscore-api/internal/rofl"
 	"wardscore-api/internal/services"
+	"wardscore-api/internal/storage"
 
 	"github.com/gin-gonic/gin"
 )
 
 // ReplayController gerencia operações relacionadas aos replays
 type ReplayController struct {
-    replayService *services.ReplayService
+    replayService   *services.ReplayService
+    importService   *services.ImportService
+    jobService      *services.JobService
+    progressService *services.ProgressService
 }
 
 // NewReplayController cria nova instância do controller
-func NewReplayController(replayService *services.ReplayService) *ReplayController {
+func NewReplayController(replayService *services.ReplayService, importService *services.ImportService, jobService *services.JobService, progressService *services.ProgressService) *ReplayController {
     return &ReplayController{
-        replayService: replayService,
+        replayService:   replayService,
+        importService:   importService,
+        jobService:      jobService,
+        progressService: progressService,
     }
 }
 
-// UploadReplay simula upload de replay
+// UploadReplay recebe o arquivo .rofl (multipart/form-data), grava no storage
+// e enfileira o processamento (202 com o ID do job)
 // POST /api/v1/replays/upload
+// Campos: file (.rofl), game_id (opcional), champion, queue
 func (rc *ReplayController) UploadReplay(c *gin.Context) {
-    userID := c.GetHeader("X-User-ID")
-    
-    if userID == "" {
-        c.JSON(http.StatusBadRequest, gin.H{
+    user, ok := middleware.GetUser(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
             "success": false,
-            "error":   "User ID é obrigatório no header X-User-ID",
+            "error":   "Usuário não autenticado",
         })
         return
     }
 
-    id, err := strconv.ParseUint(userID, 10, 32)
+    // Limite do corpo inteiro: arquivo + campos do formulário
+    c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.AppConfig.MaxReplaySize+maxFormOverhead)
+
+    // MultipartReader processa o corpo em streaming, sem bufferizar o arquivo
+    reader, err := c.Request.MultipartReader()
     if err != nil {
         c.JSON(http.StatusBadRequest, gin.H{
             "success": false,
-            "error":   "User ID inválido",
+            "error":   "Envie o replay como multipart/form-data",
         })
         return
     }
 
-    var req struct {
-        FileName string `json:"file_name" binding:"required"`
-        GameID   string `json:"game_id" binding:"required"`
-        Champion string `json:"champion"`
-        Queue    string `json:"queue"`
-        Duration int    `json:"duration"`
+    var staged *services.StagedReplayFile
+    defer func() { staged.Remove() }()
+    var originalName string
+    fields := map[string]string{}
+
+    for {
+        part, err := reader.NextPart()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            rc.respondUploadError(c, err)
+            return
+        }
+
+        if part.FormName() == "file" {
+            if staged != nil {
+                part.Close()
+                c.JSON(http.StatusBadRequest, gin.H{
+                    "success": false,
+                    "error":   "Envie apenas um arquivo por upload",
+                })
+                return
+            }
+
+            originalName = filepath.Base(part.FileName())
+            if !strings.EqualFold(filepath.Ext(originalName), ".rofl") {
+                part.Close()
+                c.JSON(http.StatusBadRequest, gin.H{
+                    "success": false,
+                    "error":   "Arquivo deve ter extensão .rofl",
+                })
+                return
+            }
+
+            staged, err = rc.replayService.StageFile(part)
+            part.Close()
+            if err != nil {
+                rc.respondUploadError(c, err)
+                return
+            }
+            continue
+        }
+
+        value, err := io.ReadAll(io.LimitReader(part, 1024))
+        part.Close()
+        if err != nil {
+            rc.respondUploadError(c, err)
+            return
+        }
+        fields[part.FormName()] = strings.TrimSpace(string(value))
     }
 
-    if err := c.ShouldBindJSON(&req); err != nil {
+    if staged == nil {
         c.JSON(http.StatusBadRequest, gin.H{
             "success": false,
-            "error":   "Dados inválidos: " + err.Error(),
+            "error":   "Campo file é obrigatório",
         })
         return
     }
 
-    replay := &models.Replay{
-        UserID:       uint(id),
-        FileName:     req.FileName,
-        OriginalName: req.FileName,
-        MatchID:      req.GameID, // GameID vira MatchID
-        Champion:     req.Champion,
-        Queue:        req.Queue,
-        Duration:     req.Duration,
-        Status:       models.StatusUploaded,
-        FileSize:     1024000, // Mock: 1MB
-        FilePath:     "/replays/" + req.FileName,
-    }
-
-    createdReplay, err := rc.replayService.Create(replay)
+    createdReplay, err := rc.replayService.CreateFromUpload(user, originalName, staged, services.UploadMetadata{
+        GameID:   fields["game_id"],
+        Champion: fields["champion"],
+        Queue:    fields["queue"],
+    })
     if err != nil {
+        var duplicateErr *services.DuplicateReplayError
+        if errors.As(err, &duplicateErr) {
+            c.JSON(http.StatusConflict, gin.H{
+                "success":   false,
+                "error":     "Você já enviou este replay",
+                "replay_id": duplicateErr.Existing.ID,
+            })
+            return
+        }
         c.JSON(http.StatusInternalServerError, gin.H{
             "success": false,
             "error":   "Falha ao salvar replay: " + err.Error(),
@@ -81,15 +151,148 @@ func (rc *ReplayController) UploadReplay(c *gin.Context) {
         return
     }
 
-    c.JSON(http.StatusCreated, gin.H{
+    c.JSON(http.StatusAccepted, gin.H{
         "success": true,
         "data":    createdReplay,
+        "job_id":  enqueueProcessing(rc.jobService, createdReplay),
         "message": "Replay uploaded com sucesso! Será processado em breve.",
     })
 }
 
+// ImportReplays importa vários replays de um arquivo ZIP (multipart/form-data)
+// POST /api/v1/replays/import
+// Campos: file (.zip com arquivos .rofl)
+func (rc *ReplayController) ImportReplays(c *gin.Context) {
+    user, ok := middleware.GetUser(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
+            "success": false,
+            "error":   "Usuário não autenticado",
+        })
+        return
+    }
+
+    c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.AppConfig.MaxImportSize+maxFormOverhead)
+
+    reader, err := c.Request.MultipartReader()
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error":   "Envie o arquivo ZIP como multipart/form-data",
+        })
+        return
+    }
+
+    var archive *services.StagedArchive
+    defer func() { archive.Remove() }()
+
+    for archive == nil {
+        part, err := reader.NextPart()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            rc.respondImportError(c, err)
+            return
+        }
+
+        if part.FormName() != "file" {
+            part.Close()
+            continue
+        }
+        if !strings.EqualFold(filepath.Ext(part.FileName()), ".zip") {
+            part.Close()
+            c.JSON(http.StatusBadRequest, gin.H{
+                "success": false,
+                "error":   "Arquivo deve ter extensão .zip",
+            })
+            return
+        }
+
+        archive, err = rc.importService.StageArchive(part)
+        part.Close()
+        if err != nil {
+            rc.respondImportError(c, err)
+            return
+        }
+    }
+
+    if archive == nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error":   "Campo file é obrigatório",
+        })
+        return
+    }
+
+    ctx, cancel := context.WithTimeout(c.Request.Context(), config.AppConfig.ImportTimeout)
+    defer cancel()
+
+    report, err := rc.importService.Import(ctx, user, archive)
+    if err != nil {
+        rc.respondImportError(c, err)
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data":    report,
+        "message": fmt.Sprintf("%d de %d replays importados", report.Summary[services.ImportCreated], report.Total),
+    })
+}
+
+func (rc *ReplayController) respondImportError(c *gin.Context, err error) {
+    var maxBytesErr *http.MaxBytesError
+    switch {
+    case errors.Is(err, services.ErrImportTooLarge) || errors.As(err, &maxBytesErr):
+        c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+            "success": false,
+            "error":   fmt.Sprintf("Arquivo excede o tamanho máximo de %d MB", config.AppConfig.MaxImportSize/(1024*1024)),
+        })
+    case errors.Is(err, services.ErrImportInvalid), errors.Is(err, services.ErrImportTooMany), errors.Is(err, services.ErrImportNoReplays):
+        c.JSON(http.StatusUnprocessableEntity, gin.H{
+            "success": false,
+            "error":   err.Error(),
+        })
+    default:
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error":   "Falha na importação: " + err.Error(),
+        })
+    }
+}
+
+// maxFormOverhead é a folga para os campos e cabeçalhos do multipart além do arquivo
+const maxFormOverhead = 64 * 1024
+
+func (rc *ReplayController) respondUploadError(c *gin.Context, err error) {
+    var maxBytesErr *http.MaxBytesError
+    if errors.Is(err, services.ErrReplayTooLarge) || errors.As(err, &maxBytesErr) {
+        c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+            "success": false,
+            "error":   fmt.Sprintf("Arquivo excede o tamanho máximo de %d MB", config.AppConfig.MaxReplaySize/(1024*1024)),
+        })
+        return
+    }
+
+    var truncatedErr *rofl.TruncatedError
+    var corruptErr *rofl.CorruptError
+    if errors.Is(err, rofl.ErrInvalidMagic) || errors.As(err, &truncatedErr) || errors.As(err, &corruptErr) {
+        c.JSON(http.StatusUnprocessableEntity, gin.H{
+            "success": false,
+            "error":   "Arquivo .rofl inválido: " + err.Error(),
+        })
+        return
+    }
+
+    c.JSON(http.StatusBadRequest, gin.H{
+        "success": false,
+        "error":   "Falha no upload: " + err.Error(),
+    })
+}
+
 // GetReplays lista replays do usuário
-// GET /api/v1/replays?user_id=1&page=1&limit=10
+// GET /api/v1/replays?user_id=1&page=1&limit=10 (user_id padrão: usuário autenticado)
 func (rc *ReplayController) GetReplays(c *gin.Context) {
     userID := c.Query("user_id")
     page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
@@ -102,9 +305,8 @@ func (rc *ReplayController) GetReplays(c *gin.Context) {
         limit = 10
     }
 
-    var replays []models.Replay
-    var err error
-
+    // Sem user_id, lista os replays do próprio usuário
+    ownerID, _ := middleware.GetUserID(c)
     if userID != "" {
         id, parseErr := strconv.ParseUint(userID, 10, 32)
         if parseErr != nil {
@@ -114,16 +316,18 @@ func (rc *ReplayController) GetReplays(c *gin.Context) {
             })
             return
         }
-        replays, err = rc.replayService.GetByUserID(uint(id))
-    } else {
-        // Para listar todos, vamos retornar mensagem de que precisa especificar usuário
-        c.JSON(http.StatusBadRequest, gin.H{
+        ownerID = uint(id)
+    }
+
+    if !middleware.CanAccess(c, ownerID) {
+        c.JSON(http.StatusForbidden, gin.H{
             "success": false,
-            "error":   "Parâmetro user_id é obrigatório",
+            "error":   "Acesso negado",
         })
         return
     }
 
+    replays, err := rc.replayService.GetByUserID(ownerID)
     if err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{
             "success": false,
@@ -177,8 +381,9 @@ func (rc *ReplayController) GetReplay(c *gin.Context) {
         return
     }
 
+    // Replays de outros usuários respondem como inexistentes
     replay, err := rc.replayService.GetByID(uint(id))
-    if err != nil {
+    if err != nil || !middleware.CanAccess(c, replay.UserID) {
         c.JSON(http.StatusNotFound, gin.H{
             "success": false,
             "error":   "Replay não encontrado",
@@ -192,6 +397,209 @@ func (rc *ReplayController) GetReplay(c *gin.Context) {
     })
 }
 
+// DownloadReplay envia o arquivo .rofl do replay, com suporte a Range e ETag.
+// No backend s3 redireciona para uma URL pré-assinada (use ?redirect=false para
+// receber o arquivo pela própria API).
+// GET /api/v1/replays/:id/file
+func (rc *ReplayController) DownloadReplay(c *gin.Context) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error":   "ID inválido",
+        })
+        return
+    }
+
+    replay, err := rc.replayService.GetByID(uint(id))
+    if err != nil || !middleware.CanAccess(c, replay.UserID) {
+        c.JSON(http.StatusNotFound, gin.H{
+            "success": false,
+            "error":   "Replay não encontrado",
+        })
+        return
+    }
+
+    // O arquivo nunca muda: o hash do conteúdo serve de ETag
+    etag := ""
+    if replay.ContentHash != "" {
+        etag = `"` + replay.ContentHash + `"`
+        if match := c.GetHeader("If-None-Match"); match != "" && etagMatches(match, etag) {
+            c.Header("ETag", etag)
+            c.Status(http.StatusNotModified)
+            return
+        }
+    }
+
+    fileName := replay.OriginalName
+    if fileName == "" {
+        fileName = replay.FileName
+    }
+    disposition := contentDisposition(fileName)
+
+    if c.Query("redirect") != "false" {
+        url, err := rc.replayService.DownloadURL(replay, disposition)
+        if err == nil {
+            c.Header("Cache-Control", "private, no-store")
+            c.Redirect(http.StatusFound, url)
+            return
+        }
+        if !errors.Is(err, storage.ErrPresignNotSupported) {
+            log.Printf("⚠️ Falha ao gerar URL de download do replay %d: %v", replay.ID, err)
+        }
+    }
+
+    file, err := rc.replayService.OpenFile(replay)
+    if err != nil {
+        if errors.Is(err, storage.ErrNotFound) || replay.FilePath == "" {
+            c.JSON(http.StatusNotFound, gin.H{
+                "success": false,
+                "error":   "Arquivo do replay não encontrado",
+            })
+            return
+        }
+        c.JSON(http.StatusInternalServerError, gin.H{
+            "success": false,
+            "error":   "Falha ao abrir arquivo: " + err.Error(),
+        })
+        return
+    }
+    defer file.Close()
+
+    c.Header("Content-Type", "application/octet-stream")
+    c.Header("Content-Disposition", disposition)
+    c.Header("Cache-Control", "private, no-cache")
+    if etag != "" {
+        c.Header("ETag", etag)
+    }
+
+    // ServeContent trata Range, If-Range e If-Modified-Since
+    http.ServeContent(c.Writer, c.Request, fileName, replay.UploadedAt, file)
+}
+
+// etagMatches compara o header If-None-Match (lista ou "*") com a ETag
+func etagMatches(header, etag string) bool {
+    for _, candidate := range strings.Split(header, ",") {
+        candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+        if candidate == "*" || candidate == etag {
+            return true
+        }
+    }
+    return false
+}
+
+// contentDisposition monta o header de anexo, com filename* para nomes não ASCII
+func contentDisposition(fileName string) string {
+    if value := mime.FormatMediaType("attachment", map[string]string{"filename": fileName}); value != "" {
+        return value
+    }
+    return `attachment; filename="replay.rofl"`
+}
+
+// GetReplayStatus retorna o status do processamento (mesmo documento do SSE)
+// GET /api/v1/replays/:id/status
+func (rc *ReplayController) GetReplayStatus(c *gin.Context) {
+    replay, ok := rc.loadOwnReplay(c)
+    if !ok {
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "success": true,
+        "data":    rc.progressService.Get(replay),
+    })
+}
+
+// sseKeepAlive é o intervalo dos comentários que mantêm a conexão SSE aberta em proxies
+const sseKeepAlive = 15 * time.Second
+
+// StreamReplayEvents envia as mudanças de status e o progresso do
+// processamento via Server-Sent Events (evento "status"). O stream termina
+// quando o replay é concluído ou vai para dead letter.
+// GET /api/v1/replays/:id/events
+func (rc *ReplayController) StreamReplayEvents(c *gin.Context) {
+    replay, ok := rc.loadOwnReplay(c)
+    if !ok {
+        return
+    }
+
+    // Inscreve antes de ler o estado atual para não perder transições
+    ctx := c.Request.Context()
+    pubsub, err := rc.progressService.Subscribe(ctx, replay.ID)
+    if err != nil {
+        c.JSON(http.StatusServiceUnavailable, gin.H{
+            "success": false,
+            "error":   "Falha ao acompanhar processamento: " + err.Error(),
+        })
+        return
+    }
+    defer pubsub.Close()
+
+    c.Header("Content-Type", "text/event-stream")
+    c.Header("Cache-Control", "no-cache")
+    c.Header("Connection", "keep-alive")
+    c.Header("X-Accel-Buffering", "no")
+
+    current := rc.progressService.Get(replay)
+    c.SSEvent("status", current)
+    c.Writer.Flush()
+    if current.IsFinal() {
+        return
+    }
+
+    keepAlive := time.NewTicker(sseKeepAlive)
+    defer keepAlive.Stop()
+    messages := pubsub.Channel()
+
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-keepAlive.C:
+            fmt.Fprint(c.Writer, ": ping\n\n")
+            c.Writer.Flush()
+        case msg, open := <-messages:
+            if !open {
+                return
+            }
+
+            var progress services.ReplayProgress
+            if err := json.Unmarshal([]byte(msg.Payload), &progress); err != nil {
+                continue
+            }
+            c.SSEvent("status", progress)
+            c.Writer.Flush()
+            if progress.IsFinal() {
+                return
+            }
+        }
+    }
+}
+
+// loadOwnReplay busca o replay do parâmetro :id; replays de outros usuários
+// respondem como inexistentes
+func (rc *ReplayController) loadOwnReplay(c *gin.Context) (*models.Replay, bool) {
+    id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+    if err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error":   "ID inválido",
+        })
+        return nil, false
+    }
+
+    replay, err := rc.replayService.GetByID(uint(id))
+    if err != nil || !middleware.CanAccess(c, replay.UserID) {
+        c.JSON(http.StatusNotFound, gin.H{
+            "success": false,
+            "error":   "Replay não encontrado",
+        })
+        return nil, false
+    }
+
+    return replay, true
+}
+
 // UpdateReplay atualiza dados do replay
 // PUT /api/v1/replays/:id
 func (rc *ReplayController) UpdateReplay(c *gin.Context) {
@@ -206,7 +614,7 @@ func (rc *ReplayController) UpdateReplay(c *gin.Context) {
     }
 
     var req struct {
-        Status     string `json:"status"`
+        Status     *string `json:"status"`
         Champion   string `json:"champion"`
         Queue      string `json:"queue"`
         Duration   int    `json:"duration"`
@@ -221,8 +629,19 @@ func (rc *ReplayController) UpdateReplay(c *gin.Context) {
         return
     }
 
+    // O status só muda pelo processamento (retentativas, dead letter e
+    // reenfileiramento pelo admin), nunca pela edição do replay
+    if req.Status != nil {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "success": false,
+            "error":   "O status do replay não pode ser alterado",
+        })
+        return
+    }
+
+    // Replays de outros usuários respondem como inexistentes
     replay, err := rc.replayService.GetByID(uint(id))
-    if err != nil {
+    if err != nil || !middleware.CanAccess(c, replay.UserID) {
         c.JSON(http.StatusNotFound, gin.H{
             "success": false,
             "error":   "Replay não encontrado",
@@ -230,9 +649,6 @@ func (rc *ReplayController) UpdateReplay(c *gin.Context) {
         return
     }
 
-    if req.Status != "" {
-        replay.Status = models.ReplayStatus(req.Status)
-    }
     if req.Champion != "" {
         replay.Champion = req.Champion
     }
@@ -272,7 +688,16 @@ func (rc *ReplayController) DeleteReplay(c *gin.Context) {
         return
     }
 
-    err = rc.replayService.Delete(uint(id))
+    replay, err := rc.replayService.GetByID(uint(id))
+    if err != nil || !middleware.CanAccess(c, replay.UserID) {
+        c.JSON(http.StatusNotFound, gin.H{
+            "success": false,
+            "error":   "Replay não encontrado",
+        })
+        return
+    }
+
+    err = rc.replayService.Delete(replay.ID)
     if err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{
             "success": false,
@@ -0,0 +1,175 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"wardscore-api/internal/scoring"
+	"wardscore-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScoringController gerencia as versões do modelo de score e os baselines
+// de role/fila do WardScore
+type ScoringController struct {
+	baselineService *services.BaselineService
+	analysisService *services.AnalysisService
+	jobService      *services.JobService
+}
+
+// NewScoringController cria nova instância do controller
+func NewScoringController(baselineService *services.BaselineService, analysisService *services.AnalysisService, jobService *services.JobService) *ScoringController {
+	return &ScoringController{
+		baselineService: baselineService,
+		analysisService: analysisService,
+		jobService:      jobService,
+	}
+}
+
+// GetVersions lista as versões do modelo de score e a atual
+// GET /api/v1/scoring/versions
+func (sc *ScoringController) GetVersions(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    scoring.Versions,
+		"current": scoring.Version,
+	})
+}
+
+// ReprocessAnalyses enfileira o reprocessamento de replays concluídos que
+// ainda não têm análise na versão atual do modelo de score. Processa até
+// limit replays por chamada; repita enquanto remaining > 0.
+// POST /api/v1/admin/scoring/reprocess?limit=500
+func (sc *ScoringController) ReprocessAnalyses(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "500"))
+	if limit < 1 || limit > 5000 {
+		limit = 500
+	}
+
+	replays, total, err := sc.analysisService.StartBulkReprocess(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Falha ao buscar replays: " + err.Error(),
+		})
+		return
+	}
+
+	enqueued := 0
+	for i := range replays {
+		if _, err := sc.jobService.EnqueueReplay(&replays[i]); err != nil {
+			log.Printf("⚠️ Falha ao enfileirar reprocessamento do replay %d: %v", replays[i].ID, err)
+			continue
+		}
+		enqueued++
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"data": gin.H{
+			"version":   scoring.Version,
+			"enqueued":  enqueued,
+			"remaining": total - int64(enqueued),
+		},
+		"message": "Reprocessamento enfileirado",
+	})
+}
+
+// GetBaselines lista os baselines salvos e a tabela embutida de cada role
+// GET /api/v1/admin/scoring/baselines
+func (sc *ScoringController) GetBaselines(c *gin.Context) {
+	baselines, err := sc.baselineService.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Falha ao buscar baselines: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"data":     baselines,
+		"defaults": sc.baselineService.Defaults(),
+	})
+}
+
+// SetBaseline define as metas de uma role e fila (vazias valem para todas)
+// PUT /api/v1/admin/scoring/baselines
+func (sc *ScoringController) SetBaseline(c *gin.Context) {
+	var req struct {
+		Role    string             `json:"role"`
+		Queue   string             `json:"queue"`
+		Targets map[string]float64 `json:"targets" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	baseline, err := sc.baselineService.Set(req.Role, req.Queue, req.Targets)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Falha ao salvar baseline: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    baseline,
+		"message": "Baseline salvo. Vale para as próximas análises.",
+	})
+}
+
+// DeleteBaseline remove um baseline salvo
+// DELETE /api/v1/admin/scoring/baselines/:id
+func (sc *ScoringController) DeleteBaseline(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := sc.baselineService.Delete(id); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrBaselineNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Baseline removido com sucesso",
+	})
+}
+
+// RecomputeBaselines recalcula os baselines a partir das análises salvas
+// POST /api/v1/admin/scoring/baselines/recompute
+func (sc *ScoringController) RecomputeBaselines(c *gin.Context) {
+	baselines, err := sc.baselineService.Recompute()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Falha ao recalcular baselines: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    baselines,
+		"message": "Baselines recalculados. Valem para as próximas análises.",
+	})
+}
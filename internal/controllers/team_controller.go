@@ -0,0 +1,205 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"wardscore-api/internal/middleware"
+	"wardscore-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TeamController gerencia os times do usuário e seus membros
+type TeamController struct {
+	teamService *services.TeamService
+	userService *services.UserService
+}
+
+// NewTeamController cria nova instância do controller
+func NewTeamController(teamService *services.TeamService, userService *services.UserService) *TeamController {
+	return &TeamController{
+		teamService: teamService,
+		userService: userService,
+	}
+}
+
+// CreateTeam cria um time com o usuário como dono
+// POST /api/v1/teams
+func (tc *TeamController) CreateTeam(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req struct {
+		Name string `json:"name" binding:"required,max=100"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	team, err := tc.teamService.Create(userID, req.Name)
+	if err != nil {
+		respondTeamError(c, err, "Falha ao criar time")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    team,
+	})
+}
+
+// GetTeams lista os times dos quais o usuário é membro
+// GET /api/v1/teams
+func (tc *TeamController) GetTeams(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	teams, err := tc.teamService.GetByUserID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Falha ao buscar times: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    teams,
+	})
+}
+
+// GetTeam busca um time com os membros
+// GET /api/v1/teams/:id
+func (tc *TeamController) GetTeam(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	team, err := tc.teamService.Get(userID, id)
+	if err != nil {
+		respondTeamError(c, err, "Falha ao buscar time")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    team,
+	})
+}
+
+// DeleteTeam remove o time (só o dono); os webhooks do time são desativados
+// DELETE /api/v1/teams/:id
+func (tc *TeamController) DeleteTeam(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	if err := tc.teamService.Delete(userID, id); err != nil {
+		respondTeamError(c, err, "Falha ao remover time")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Time removido com sucesso",
+	})
+}
+
+// AddMember adiciona um usuário ao time pelo Riot ID (só o dono)
+// POST /api/v1/teams/:id/members
+func (tc *TeamController) AddMember(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		RiotID string `json:"riot_id" binding:"required"` // gameName#tagLine
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	user, err := tc.userService.GetByRiotID(req.RiotID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Usuário não encontrado",
+		})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	member, err := tc.teamService.AddMember(userID, id, user.ID)
+	if err != nil {
+		respondTeamError(c, err, "Falha ao adicionar membro")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    member,
+	})
+}
+
+// RemoveMember remove um membro (o dono remove qualquer um; membros podem sair)
+// DELETE /api/v1/teams/:id/members/:user_id
+func (tc *TeamController) RemoveMember(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	memberID, ok := parseIDParam(c, "user_id")
+	if !ok {
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	if err := tc.teamService.RemoveMember(userID, id, memberID); err != nil {
+		respondTeamError(c, err, "Falha ao remover membro")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Membro removido com sucesso",
+	})
+}
+
+func respondTeamError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrTeamNotFound), errors.Is(err, services.ErrTeamMemberNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, services.ErrTeamForbidden):
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	case errors.Is(err, services.ErrTeamMemberExists):
+		c.JSON(http.StatusConflict, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   message + ": " + err.Error(),
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"wardscore-api/internal/middleware"
+	"wardscore-api/internal/rofl"
+	"wardscore-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UploadController gerencia uploads resumíveis de replays em chunks
+type UploadController struct {
+	uploadService *services.UploadService
+	jobService    *services.JobService
+}
+
+// NewUploadController cria nova instância do controller
+func NewUploadController(uploadService *services.UploadService, jobService *services.JobService) *UploadController {
+	return &UploadController{
+		uploadService: uploadService,
+		jobService:    jobService,
+	}
+}
+
+// CreateUpload abre uma sessão de upload resumível
+// POST /api/v1/replays/uploads
+func (uc *UploadController) CreateUpload(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req struct {
+		FileName string `json:"file_name" binding:"required"`
+		FileSize int64  `json:"file_size" binding:"required"`
+		Checksum string `json:"checksum" binding:"required"` // SHA-256 hex
+		services.UploadMetadata
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	fileName := filepath.Base(req.FileName)
+	if !strings.EqualFold(filepath.Ext(fileName), ".rofl") {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Arquivo deve ter extensão .rofl",
+		})
+		return
+	}
+
+	session, err := uc.uploadService.Create(userID, fileName, req.FileSize, req.Checksum, req.UploadMetadata)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, services.ErrReplayTooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"error":   "Falha ao criar upload: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data": gin.H{
+			"upload_id":    session.ID,
+			"chunk_size":   session.ChunkSize,
+			"total_chunks": session.TotalChunks(),
+			"expires_at":   session.ExpiresAt,
+		},
+		"message": "Upload criado. Envie os chunks com PUT /replays/uploads/:upload_id/chunks/:index",
+	})
+}
+
+// PutChunk recebe um chunk numerado (corpo bruto)
+// PUT /api/v1/replays/uploads/:upload_id/chunks/:index
+// Headers opcionais: Upload-Offset (byte inicial), X-Chunk-Checksum (SHA-256 hex)
+func (uc *UploadController) PutChunk(c *gin.Context) {
+	session, ok := uc.loadSession(c)
+	if !ok {
+		return
+	}
+
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Índice de chunk inválido",
+		})
+		return
+	}
+
+	offset := int64(-1)
+	if header := c.GetHeader("Upload-Offset"); header != "" {
+		offset, err = strconv.ParseInt(header, 10, 64)
+		if err != nil || offset < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "Header Upload-Offset inválido",
+			})
+			return
+		}
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, session.ChunkSize+1)
+	err = uc.uploadService.PutChunk(session, index, offset, c.Request.Body, c.GetHeader("X-Chunk-Checksum"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrChunkOutOfRange):
+			status = http.StatusRequestedRangeNotSatisfiable
+		case errors.Is(err, services.ErrChunkOffsetMismatch):
+			status = http.StatusConflict
+		case errors.Is(err, services.ErrChunkSizeMismatch), errors.Is(err, services.ErrChunkChecksum):
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"error":   "Falha ao gravar chunk: " + err.Error(),
+		})
+		return
+	}
+
+	uc.respondStatus(c, session)
+}
+
+// GetUpload retorna chunks e intervalos de bytes já recebidos
+// GET /api/v1/replays/uploads/:upload_id
+func (uc *UploadController) GetUpload(c *gin.Context) {
+	session, ok := uc.loadSession(c)
+	if !ok {
+		return
+	}
+
+	uc.respondStatus(c, session)
+}
+
+// CompleteUpload monta o arquivo, confere o checksum, cria o replay e
+// enfileira o processamento (202 com o ID do job)
+// POST /api/v1/replays/uploads/:upload_id/complete
+func (uc *UploadController) CompleteUpload(c *gin.Context) {
+	session, ok := uc.loadSession(c)
+	if !ok {
+		return
+	}
+
+	user, _ := middleware.GetUser(c)
+	replay, err := uc.uploadService.Complete(session, user)
+	if err != nil {
+		var duplicateErr *services.DuplicateReplayError
+		if errors.As(err, &duplicateErr) {
+			c.JSON(http.StatusConflict, gin.H{
+				"success":   false,
+				"error":     "Você já enviou este replay",
+				"replay_id": duplicateErr.Existing.ID,
+			})
+			return
+		}
+
+		var truncatedErr *rofl.TruncatedError
+		var corruptErr *rofl.CorruptError
+
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrUploadIncomplete), errors.Is(err, services.ErrUploadInProgress):
+			status = http.StatusConflict
+		case errors.Is(err, services.ErrUploadChecksum), errors.Is(err, rofl.ErrInvalidMagic),
+			errors.As(err, &truncatedErr), errors.As(err, &corruptErr):
+			status = http.StatusUnprocessableEntity
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"error":   "Falha ao finalizar upload: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"data":    replay,
+		"job_id":  enqueueProcessing(uc.jobService, replay),
+		"message": "Replay uploaded com sucesso! Será processado em breve.",
+	})
+}
+
+// AbortUpload cancela o upload e descarta os chunks
+// DELETE /api/v1/replays/uploads/:upload_id
+func (uc *UploadController) AbortUpload(c *gin.Context) {
+	session, ok := uc.loadSession(c)
+	if !ok {
+		return
+	}
+
+	uc.uploadService.Abort(session)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Upload cancelado",
+	})
+}
+
+func (uc *UploadController) loadSession(c *gin.Context) (*services.UploadSession, bool) {
+	userID, _ := middleware.GetUserID(c)
+
+	session, err := uc.uploadService.Get(userID, c.Param("upload_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Upload não encontrado ou expirado",
+		})
+		return nil, false
+	}
+
+	return session, true
+}
+
+func (uc *UploadController) respondStatus(c *gin.Context, session *services.UploadSession) {
+	status, err := uc.uploadService.Status(session)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Falha ao buscar status do upload: " + err.Error(),
+		})
+		return
+	}
+
+	c.Header("Upload-Expires", session.ExpiresAt.UTC().Format(http.TimeFormat))
+	c.Header("Upload-Received", fmt.Sprintf("%d/%d", status.ReceivedBytes, session.FileSize))
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    status,
+	})
+}
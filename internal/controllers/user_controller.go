@@ -3,7 +3,7 @@ package controllers
 import (
 	"net/http"
 	"strconv"
-	"wardscore-api/internal/models"
+	"wardscore-api/internal/middleware"
 	"wardscore-api/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -21,55 +21,6 @@ func NewUserController(userService *services.UserService) *UserController {
     }
 }
 
-// CreateUser cria um novo usuário
-// POST /api/v1/users
-func (uc *UserController) CreateUser(c *gin.Context) {
-    var req struct {
-        RiotID   string `json:"riot_id" binding:"required"`
-        GameName string `json:"game_name" binding:"required"`
-        TagLine  string `json:"tag_line" binding:"required"`
-        Email    string `json:"email" binding:"required,email"`
-        Region   string `json:"region"`
-        PUUID    string `json:"puuid"`
-    }
-
-    if err := c.ShouldBindJSON(&req); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{
-            "success": false,
-            "error":   "Dados inválidos: " + err.Error(),
-        })
-        return
-    }
-
-    user := &models.User{
-        RiotID:   req.RiotID,
-        GameName: req.GameName,
-        TagLine:  req.TagLine,
-        Email:    req.Email,
-        Region:   req.Region,
-        PUUID:    req.PUUID,
-    }
-
-    if user.Region == "" {
-        user.Region = "BR1"
-    }
-
-    createdUser, err := uc.userService.Create(user)
-    if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{
-            "success": false,
-            "error":   "Falha ao criar usuário: " + err.Error(),
-        })
-        return
-    }
-
-    c.JSON(http.StatusCreated, gin.H{
-        "success": true,
-        "data":    createdUser,
-        "message": "Usuário criado com sucesso",
-    })
-}
-
 // GetAllUsers lista todos os usuários com paginação
 // GET /api/v1/users?page=1&limit=10
 func (uc *UserController) GetAllUsers(c *gin.Context) {
@@ -113,26 +64,16 @@ func (uc *UserController) GetAllUsers(c *gin.Context) {
 // GetProfile busca perfil do usuário
 // GET /api/v1/users/profile
 func (uc *UserController) GetProfile(c *gin.Context) {
-    userID := c.GetHeader("X-User-ID")
-    
-    if userID == "" {
-        c.JSON(http.StatusBadRequest, gin.H{
-            "success": false,
-            "error":   "User ID é obrigatório no header X-User-ID",
-        })
-        return
-    }
-
-    id, err := strconv.ParseUint(userID, 10, 32)
-    if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{
+    id, ok := middleware.GetUserID(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
             "success": false,
-            "error":   "User ID inválido",
+            "error":   "Usuário não autenticado",
         })
         return
     }
 
-    user, err := uc.userService.GetByID(uint(id))
+    user, err := uc.userService.GetByID(id)
     if err != nil {
         c.JSON(http.StatusNotFound, gin.H{
             "success": false,
@@ -150,32 +91,20 @@ func (uc *UserController) GetProfile(c *gin.Context) {
 // UpdateProfile atualiza perfil do usuário
 // PUT /api/v1/users/profile
 func (uc *UserController) UpdateProfile(c *gin.Context) {
-    userID := c.GetHeader("X-User-ID")
-    
-    if userID == "" {
-        c.JSON(http.StatusBadRequest, gin.H{
+    id, ok := middleware.GetUserID(c)
+    if !ok {
+        c.JSON(http.StatusUnauthorized, gin.H{
             "success": false,
-            "error":   "User ID é obrigatório no header X-User-ID",
-        })
-        return
-    }
-
-    id, err := strconv.ParseUint(userID, 10, 32)
-    if err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{
-            "success": false,
-            "error":   "User ID inválido",
+            "error":   "Usuário não autenticado",
         })
         return
     }
 
+    // Riot ID, gameName, tagLine e PUUID vêm do login Riot e não são editáveis
     var req struct {
-        GameName  string `json:"game_name"`
-        TagLine   string `json:"tag_line"`
-        Email     string `json:"email"`
+        Email     string `json:"email" binding:"omitempty,email"`
         AvatarURL string `json:"avatar_url"`
         Region    string `json:"region"`
-        PUUID     string `json:"puuid"`
     }
 
     if err := c.ShouldBindJSON(&req); err != nil {
@@ -186,7 +115,7 @@ func (uc *UserController) UpdateProfile(c *gin.Context) {
         return
     }
 
-    user, err := uc.userService.GetByID(uint(id))
+    user, err := uc.userService.GetByID(id)
     if err != nil {
         c.JSON(http.StatusNotFound, gin.H{
             "success": false,
@@ -195,14 +124,8 @@ func (uc *UserController) UpdateProfile(c *gin.Context) {
         return
     }
 
-    if req.GameName != "" {
-        user.GameName = req.GameName
-    }
-    if req.TagLine != "" {
-        user.TagLine = req.TagLine
-    }
     if req.Email != "" {
-        user.Email = req.Email
+        user.Email = &req.Email
     }
     if req.AvatarURL != "" {
         user.AvatarURL = req.AvatarURL
@@ -210,9 +133,6 @@ func (uc *UserController) UpdateProfile(c *gin.Context) {
     if req.Region != "" {
         user.Region = req.Region
     }
-    if req.PUUID != "" {
-        user.PUUID = req.PUUID
-    }
 
     updatedUser, err := uc.userService.Update(user)
     if err != nil {
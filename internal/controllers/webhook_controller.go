@@ -0,0 +1,319 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"wardscore-api/internal/middleware"
+	"wardscore-api/internal/models"
+	"wardscore-api/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookController gerencia os webhooks do usuário e o log de entregas
+type WebhookController struct {
+	webhookService *services.WebhookService
+}
+
+// NewWebhookController cria nova instância do controller
+func NewWebhookController(webhookService *services.WebhookService) *WebhookController {
+	return &WebhookController{
+		webhookService: webhookService,
+	}
+}
+
+// CreateWebhook cria um webhook; o secret de assinatura só é exibido nesta resposta
+// POST /api/v1/webhooks
+func (wc *WebhookController) CreateWebhook(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	var req struct {
+		URL         string                `json:"url" binding:"required,max=2048"`
+		Description string                `json:"description" binding:"max=255"`
+		Events      []models.WebhookEvent `json:"events" binding:"required"`
+		TeamID      *uint                 `json:"team_id"` // webhook do time (só o dono)
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	webhook, err := wc.webhookService.Create(userID, req.TeamID, req.URL, req.Description, req.Events)
+	if err != nil {
+		respondWebhookError(c, err, "Falha ao criar webhook")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    webhook,
+		"secret":  webhook.Secret,
+		"message": "Webhook criado. Guarde o secret agora, ele não será exibido novamente.",
+	})
+}
+
+// GetWebhooks lista os webhooks do usuário
+// GET /api/v1/webhooks
+func (wc *WebhookController) GetWebhooks(c *gin.Context) {
+	userID, _ := middleware.GetUserID(c)
+
+	webhooks, err := wc.webhookService.GetByUserID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Falha ao buscar webhooks: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    webhooks,
+		"events":  models.WebhookEvents,
+	})
+}
+
+// GetWebhook busca um webhook do usuário
+// GET /api/v1/webhooks/:id
+func (wc *WebhookController) GetWebhook(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	webhook, err := wc.webhookService.Get(userID, id)
+	if err != nil {
+		respondWebhookError(c, err, "Falha ao buscar webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    webhook,
+	})
+}
+
+// UpdateWebhook altera URL, descrição, eventos ou ativa/desativa o webhook
+// PUT /api/v1/webhooks/:id
+func (wc *WebhookController) UpdateWebhook(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req services.WebhookUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	webhook, err := wc.webhookService.Update(userID, id, req)
+	if err != nil {
+		respondWebhookError(c, err, "Falha ao atualizar webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    webhook,
+		"message": "Webhook atualizado com sucesso",
+	})
+}
+
+// RotateWebhookSecret gera um novo secret; o anterior deixa de valer na hora
+// POST /api/v1/webhooks/:id/rotate-secret
+func (wc *WebhookController) RotateWebhookSecret(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	webhook, err := wc.webhookService.RotateSecret(userID, id)
+	if err != nil {
+		respondWebhookError(c, err, "Falha ao gerar novo secret")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    webhook,
+		"secret":  webhook.Secret,
+		"message": "Secret gerado. Guarde-o agora, ele não será exibido novamente.",
+	})
+}
+
+// DeleteWebhook remove um webhook do usuário
+// DELETE /api/v1/webhooks/:id
+func (wc *WebhookController) DeleteWebhook(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	if err := wc.webhookService.Delete(userID, id); err != nil {
+		respondWebhookError(c, err, "Falha ao remover webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Webhook removido com sucesso",
+	})
+}
+
+// GetDeliveries lista o log de entregas do webhook
+// GET /api/v1/webhooks/:id/deliveries?status=failed&page=1&limit=20
+func (wc *WebhookController) GetDeliveries(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	status := c.Query("status")
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	switch models.DeliveryStatus(status) {
+	case "", models.DeliveryPending, models.DeliverySucceeded, models.DeliveryFailed:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Status inválido",
+		})
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	deliveries, total, err := wc.webhookService.GetDeliveries(userID, id, status, page, limit)
+	if err != nil {
+		respondWebhookError(c, err, "Falha ao buscar entregas")
+		return
+	}
+
+	totalPages := (total + int64(limit) - 1) / int64(limit)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    deliveries,
+		"meta": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": totalPages,
+			"has_next":    page < int(totalPages),
+			"has_prev":    page > 1,
+		},
+	})
+}
+
+// GetDelivery detalha uma entrega (payload, resposta e erro)
+// GET /api/v1/webhooks/:id/deliveries/:delivery_id
+func (wc *WebhookController) GetDelivery(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	deliveryID, ok := parseIDParam(c, "delivery_id")
+	if !ok {
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	delivery, err := wc.webhookService.GetDelivery(userID, id, deliveryID)
+	if err != nil {
+		respondWebhookError(c, err, "Falha ao buscar entrega")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    delivery,
+	})
+}
+
+// Redeliver agenda o reenvio de uma entrega (mesmo evento, nova entrega no log)
+// POST /api/v1/webhooks/:id/deliveries/:delivery_id/redeliver
+func (wc *WebhookController) Redeliver(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	deliveryID, ok := parseIDParam(c, "delivery_id")
+	if !ok {
+		return
+	}
+
+	userID, _ := middleware.GetUserID(c)
+	delivery, err := wc.webhookService.Redeliver(userID, id, deliveryID)
+	if err != nil {
+		respondWebhookError(c, err, "Falha ao reenviar entrega")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"data":    delivery,
+		"message": "Reenvio agendado",
+	})
+}
+
+func respondWebhookError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrWebhookNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Webhook não encontrado",
+		})
+	case errors.Is(err, services.ErrTeamNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Time não encontrado ou você não é o dono",
+		})
+	case errors.Is(err, services.ErrDeliveryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   "Entrega não encontrada",
+		})
+	case errors.Is(err, services.ErrWebhookURL):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   message + ": " + err.Error(),
+		})
+	}
+}
+
+// parseIDParam lê um ID numérico da rota, respondendo 400 se for inválido
+func parseIDParam(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "ID inválido",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
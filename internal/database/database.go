@@ -50,12 +50,47 @@ func Migrate() {
 		&models.User{},
         &models.Replay{},
         &models.Analysis{},
+        &models.APIKey{},
+        &models.Webhook{},
+        &models.WebhookDelivery{},
+        &models.ScoringBaseline{},
+        &models.InsightRule{},
+        &models.Achievement{},
+        &models.UserAchievement{},
+        &models.Team{},
+        &models.TeamMember{},
 	)
 
 	if err != nil {
         log.Fatal("❌ Falha nas migrations:", err)
     }
 
+    // Replay pode ter uma análise por versão do modelo de score
+    if DB.Migrator().HasIndex(&models.Analysis{}, "idx_analyses_replay_id") {
+        if err := DB.Migrator().DropIndex(&models.Analysis{}, "idx_analyses_replay_id"); err != nil {
+            log.Fatal("❌ Falha nas migrations:", err)
+        }
+    }
+
+    // Match ID deixou de ser único globalmente (agora é único por usuário)
+    if DB.Migrator().HasIndex(&models.Replay{}, "idx_replays_match_id") {
+        if err := DB.Migrator().DropIndex(&models.Replay{}, "idx_replays_match_id"); err != nil {
+            log.Fatal("❌ Falha nas migrations:", err)
+        }
+    }
+
+    // Catálogo inicial de conquistas
+    var achievements int64
+    if err := DB.Model(&models.Achievement{}).Count(&achievements).Error; err != nil {
+        log.Fatal("❌ Falha nas migrations:", err)
+    }
+    if achievements == 0 {
+        defaults := models.DefaultAchievements()
+        if err := DB.Create(&defaults).Error; err != nil {
+            log.Fatal("❌ Falha nas migrations:", err)
+        }
+    }
+
 	log.Println("✅ Migrations executadas com sucesso")
 }
 
@@ -0,0 +1,223 @@
+// Package heatmap agrupa as posições das wards numa grade de resolução fixa
+// do Summoner's Rift, separada pelo lado do time do jogador e pela fase da
+// partida, e desenha a grade como overlay (SVG ou PNG) para o minimapa.
+//
+// A grade tem Resolution × Resolution células. Cells[linha][coluna] conta as
+// wards da célula; a linha 0 é a borda inferior do mapa (y mínimo, lado da
+// base azul) e a coluna 0 a borda esquerda.
+package heatmap
+
+import (
+	"sort"
+	"time"
+	"wardscore-api/internal/timeline"
+)
+
+// Resolution é o número de células por lado da grade
+const Resolution = 32
+
+// Lados do time do jogador
+const (
+	SideBlue = "blue"
+	SideRed  = "red"
+)
+
+// Fases da partida
+const (
+	PhaseEarly = "early"
+	PhaseMid   = "mid"
+	PhaseLate  = "late"
+)
+
+// Sides e Phases definem a ordem das grades
+var (
+	Sides  = []string{SideBlue, SideRed}
+	Phases = []string{PhaseEarly, PhaseMid, PhaseLate}
+)
+
+// Limites das fases: early até 14 minutos (fim das placas), mid até 25 e
+// late depois disso
+const (
+	MidPhaseStart  = 14 * time.Minute
+	LatePhaseStart = 25 * time.Minute
+)
+
+// Grid é a contagem de wards de um lado e fase
+type Grid struct {
+	Side  string  `json:"side"`
+	Phase string  `json:"phase"`
+	Total int     `json:"total"`
+	Max   int     `json:"max"`
+	Cells [][]int `json:"cells"`
+}
+
+// Heatmap são as grades de uma partida (um lado) ou agregadas de várias
+type Heatmap struct {
+	Resolution int    `json:"resolution"`
+	MapWidth   int    `json:"map_width"`
+	MapHeight  int    `json:"map_height"`
+	Games      int    `json:"games"`
+	Total      int    `json:"total"`
+	Grids      []Grid `json:"grids"`
+}
+
+// SideOf converte o time do replay ("100" ou "200") no lado
+func SideOf(team string) string {
+	switch team {
+	case "100":
+		return SideBlue
+	case "200":
+		return SideRed
+	default:
+		return ""
+	}
+}
+
+// PhaseOf retorna a fase da partida no instante informado (em ms)
+func PhaseOf(ms int64) string {
+	at := time.Duration(ms) * time.Millisecond
+	switch {
+	case at < MidPhaseStart:
+		return PhaseEarly
+	case at < LatePhaseStart:
+		return PhaseMid
+	default:
+		return PhaseLate
+	}
+}
+
+// New cria um heatmap vazio, sem partidas
+func New() *Heatmap {
+	return &Heatmap{
+		Resolution: Resolution,
+		MapWidth:   timeline.MapWidth,
+		MapHeight:  timeline.MapHeight,
+		Grids:      []Grid{},
+	}
+}
+
+// Build agrupa as wards de uma partida jogada no lado informado
+func Build(wards []timeline.Ward, side string) *Heatmap {
+	h := New()
+	h.Games = 1
+	for _, phase := range Phases {
+		h.grid(side, phase)
+	}
+
+	for _, ward := range wards {
+		row, col := Cell(ward.X, ward.Y)
+		grid := h.grid(side, PhaseOf(ward.PlacedAt))
+		grid.Cells[row][col]++
+		grid.Total++
+		if grid.Cells[row][col] > grid.Max {
+			grid.Max = grid.Cells[row][col]
+		}
+		h.Total++
+	}
+	return h
+}
+
+// Cell retorna a linha e a coluna da grade que contém a posição
+func Cell(x, y int) (row, col int) {
+	col = clamp(x*Resolution/(timeline.MapWidth+1), 0, Resolution-1)
+	row = clamp(y*Resolution/(timeline.MapHeight+1), 0, Resolution-1)
+	return row, col
+}
+
+// Add soma as grades de outro heatmap (mesma resolução)
+func (h *Heatmap) Add(other *Heatmap) {
+	if other == nil || other.Resolution != h.Resolution {
+		return
+	}
+
+	h.Games += other.Games
+	h.Total += other.Total
+	for _, source := range other.Grids {
+		grid := h.grid(source.Side, source.Phase)
+		grid.Total += source.Total
+		for row := range source.Cells {
+			for col, count := range source.Cells[row] {
+				grid.Cells[row][col] += count
+				if grid.Cells[row][col] > grid.Max {
+					grid.Max = grid.Cells[row][col]
+				}
+			}
+		}
+	}
+}
+
+// Filter soma as grades do lado e da fase informados (vazios valem todos)
+func (h *Heatmap) Filter(side, phase string) *Grid {
+	result := newGrid(side, phase, h.Resolution)
+	for _, grid := range h.Grids {
+		if (side != "" && grid.Side != side) || (phase != "" && grid.Phase != phase) {
+			continue
+		}
+		result.Total += grid.Total
+		for row := range grid.Cells {
+			for col, count := range grid.Cells[row] {
+				result.Cells[row][col] += count
+				if result.Cells[row][col] > result.Max {
+					result.Max = result.Cells[row][col]
+				}
+			}
+		}
+	}
+	return result
+}
+
+// grid retorna a grade do lado e fase, criando-a na ordem de Sides e Phases
+func (h *Heatmap) grid(side, phase string) *Grid {
+	for i := range h.Grids {
+		if h.Grids[i].Side == side && h.Grids[i].Phase == phase {
+			return &h.Grids[i]
+		}
+	}
+
+	h.Grids = append(h.Grids, *newGrid(side, phase, h.Resolution))
+	sortGrids(h.Grids)
+	for i := range h.Grids {
+		if h.Grids[i].Side == side && h.Grids[i].Phase == phase {
+			return &h.Grids[i]
+		}
+	}
+	return nil
+}
+
+func newGrid(side, phase string, resolution int) *Grid {
+	cells := make([][]int, resolution)
+	for row := range cells {
+		cells[row] = make([]int, resolution)
+	}
+	return &Grid{Side: side, Phase: phase, Cells: cells}
+}
+
+// sortGrids ordena por lado e fase
+func sortGrids(grids []Grid) {
+	sort.SliceStable(grids, func(i, j int) bool {
+		return gridOrder(grids[i]) < gridOrder(grids[j])
+	})
+}
+
+func gridOrder(grid Grid) int {
+	return indexOf(Sides, grid.Side)*len(Phases) + indexOf(Phases, grid.Phase)
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return len(values)
+}
+
+func clamp(value, min, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
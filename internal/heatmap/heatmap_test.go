@@ -0,0 +1,136 @@
+package heatmap
+
+import (
+	"testing"
+	"wardscore-api/internal/timeline"
+)
+
+func TestCell(t *testing.T) {
+	tests := []struct {
+		name     string
+		x, y     int
+		row, col int
+	}{
+		{"origem (base azul)", 0, 0, 0, 0},
+		{"canto oposto (base vermelha)", timeline.MapWidth, timeline.MapHeight, Resolution - 1, Resolution - 1},
+		{"canto superior esquerdo", 0, timeline.MapHeight, Resolution - 1, 0},
+		{"canto inferior direito", timeline.MapWidth, 0, 0, Resolution - 1},
+		{"última posição da primeira coluna", 464, 0, 0, 0},
+		{"primeira posição da segunda coluna", 465, 0, 0, 1},
+		{"centro do mapa", 7435, 7490, 15, 15},
+		{"fora do mapa é limitado à borda", -50, 20000, Resolution - 1, 0},
+	}
+
+	for _, tt := range tests {
+		row, col := Cell(tt.x, tt.y)
+		if row != tt.row || col != tt.col {
+			t.Errorf("%s: Cell(%d, %d) = (%d, %d), esperado (%d, %d)", tt.name, tt.x, tt.y, row, col, tt.row, tt.col)
+		}
+	}
+}
+
+func TestPhaseOf(t *testing.T) {
+	tests := []struct {
+		ms   int64
+		want string
+	}{
+		{0, PhaseEarly},
+		{839999, PhaseEarly},
+		{840000, PhaseMid},
+		{1499999, PhaseMid},
+		{1500000, PhaseLate},
+		{3600000, PhaseLate},
+	}
+
+	for _, tt := range tests {
+		if got := PhaseOf(tt.ms); got != tt.want {
+			t.Errorf("PhaseOf(%d) = %s, esperado %s", tt.ms, got, tt.want)
+		}
+	}
+}
+
+func TestSideOf(t *testing.T) {
+	for team, want := range map[string]string{"100": SideBlue, "200": SideRed, "": "", "300": ""} {
+		if got := SideOf(team); got != want {
+			t.Errorf("SideOf(%q) = %q, esperado %q", team, got, want)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	wards := []timeline.Ward{
+		{X: 0, Y: 0, PlacedAt: 60000},
+		{X: 100, Y: 100, PlacedAt: 839999},
+		{X: 7435, Y: 7490, PlacedAt: 840000},
+		{X: timeline.MapWidth, Y: timeline.MapHeight, PlacedAt: 1500000},
+	}
+
+	h := Build(wards, SideRed)
+	if h.Games != 1 || h.Total != 4 || h.Resolution != Resolution {
+		t.Fatalf("heatmap = %d partidas, %d wards, resolução %d", h.Games, h.Total, h.Resolution)
+	}
+	if len(h.Grids) != len(Phases) {
+		t.Fatalf("grades = %d, esperado uma por fase", len(h.Grids))
+	}
+
+	want := map[string]struct {
+		total, max int
+		row, col   int
+	}{
+		PhaseEarly: {2, 2, 0, 0},
+		PhaseMid:   {1, 1, 15, 15},
+		PhaseLate:  {1, 1, Resolution - 1, Resolution - 1},
+	}
+	for i, phase := range Phases {
+		grid := h.Grids[i]
+		w := want[phase]
+		if grid.Side != SideRed || grid.Phase != phase {
+			t.Errorf("grade %d = %s/%s, esperado %s/%s", i, grid.Side, grid.Phase, SideRed, phase)
+		}
+		if grid.Total != w.total || grid.Max != w.max || grid.Cells[w.row][w.col] != w.max {
+			t.Errorf("%s: total %d, máximo %d, célula (%d, %d) = %d", phase, grid.Total, grid.Max, w.row, w.col, grid.Cells[w.row][w.col])
+		}
+	}
+}
+
+func TestAdd(t *testing.T) {
+	blue := Build([]timeline.Ward{{X: 7435, Y: 7490, PlacedAt: 60000}, {X: 7435, Y: 7490, PlacedAt: 120000}}, SideBlue)
+	red := Build([]timeline.Ward{{X: 7435, Y: 7490, PlacedAt: 60000}, {X: 0, Y: 0, PlacedAt: 1600000}}, SideRed)
+
+	total := New()
+	total.Add(blue)
+	total.Add(red)
+	total.Add(red)
+	total.Add(nil)
+	total.Add(&Heatmap{Resolution: 16, Games: 1, Total: 5})
+
+	if total.Games != 3 || total.Total != 6 {
+		t.Fatalf("agregado = %d partidas e %d wards, esperado 3 e 6", total.Games, total.Total)
+	}
+	if len(total.Grids) != len(Sides)*len(Phases) {
+		t.Fatalf("grades = %d, esperado %d", len(total.Grids), len(Sides)*len(Phases))
+	}
+	for i, grid := range total.Grids {
+		if want := Sides[i/len(Phases)] + "/" + Phases[i%len(Phases)]; grid.Side+"/"+grid.Phase != want {
+			t.Errorf("grade %d = %s/%s, esperado %s", i, grid.Side, grid.Phase, want)
+		}
+	}
+
+	if grid := total.Filter(SideBlue, PhaseEarly); grid.Total != 2 || grid.Max != 2 || grid.Cells[15][15] != 2 {
+		t.Errorf("azul early = total %d, máximo %d", grid.Total, grid.Max)
+	}
+	if grid := total.Filter("", PhaseEarly); grid.Total != 4 || grid.Cells[15][15] != 4 {
+		t.Errorf("early dos dois lados = total %d, centro %d", grid.Total, grid.Cells[15][15])
+	}
+	if grid := total.Filter(SideRed, ""); grid.Total != 4 || grid.Cells[0][0] != 2 {
+		t.Errorf("vermelho em todas as fases = total %d, origem %d", grid.Total, grid.Cells[0][0])
+	}
+	if grid := total.Filter("", ""); grid.Total != total.Total {
+		t.Errorf("todas as grades = %d wards, esperado %d", grid.Total, total.Total)
+	}
+
+	// Agregar não altera os heatmaps de origem
+	if red.Total != 2 || red.Grids[0].Cells[15][15] != 1 {
+		t.Errorf("heatmap de origem alterado: %+v", red.Grids[0].Total)
+	}
+}
@@ -0,0 +1,77 @@
+package heatmap
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// Tamanho da imagem, em pixels por lado
+const (
+	DefaultImageSize = 512
+	MinImageSize     = 64
+	MaxImageSize     = 2048
+)
+
+// RenderSVG desenha a grade como SVG de fundo transparente, para sobrepor ao
+// minimapa
+func RenderSVG(grid *Grid, size int) []byte {
+	var buf bytes.Buffer
+	resolution := len(grid.Cells)
+	cell := float64(size) / float64(resolution)
+
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, size, size, size, size)
+	for row := range grid.Cells {
+		for col, count := range grid.Cells[row] {
+			if count == 0 {
+				continue
+			}
+			c := heatColor(count, grid.Max)
+			// Linha 0 é a borda inferior do mapa; no SVG o y cresce para baixo
+			y := float64(resolution-1-row) * cell
+			fmt.Fprintf(&buf, `<rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="rgb(%d,%d,%d)" fill-opacity="%.2f"><title>%d</title></rect>`,
+				float64(col)*cell, y, cell, cell, c.R, c.G, c.B, float64(c.A)/255, count)
+		}
+	}
+	buf.WriteString(`</svg>`)
+	return buf.Bytes()
+}
+
+// RenderPNG desenha a grade como PNG de fundo transparente, para sobrepor ao
+// minimapa
+func RenderPNG(grid *Grid, size int) ([]byte, error) {
+	resolution := len(grid.Cells)
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+
+	for py := 0; py < size; py++ {
+		row := resolution - 1 - py*resolution/size
+		for px := 0; px < size; px++ {
+			col := px * resolution / size
+			if count := grid.Cells[row][col]; count > 0 {
+				img.SetNRGBA(px, py, heatColor(count, grid.Max))
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// heatColor vai de amarelo translúcido (poucas wards) a vermelho (máximo)
+func heatColor(count, max int) color.NRGBA {
+	t := 1.0
+	if max > 0 {
+		t = float64(count) / float64(max)
+	}
+	return color.NRGBA{
+		R: 255,
+		G: uint8(220 * (1 - t)),
+		B: 0,
+		A: uint8(255 * (0.3 + 0.5*t)),
+	}
+}
@@ -0,0 +1,86 @@
+package heatmap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"testing"
+	"wardscore-api/internal/timeline"
+)
+
+func renderGrid() *Grid {
+	h := Build([]timeline.Ward{
+		{X: 0, Y: 0, PlacedAt: 60000},
+		{X: 0, Y: 0, PlacedAt: 90000},
+		{X: timeline.MapWidth, Y: timeline.MapHeight, PlacedAt: 120000},
+	}, SideBlue)
+	return h.Filter(SideBlue, PhaseEarly)
+}
+
+func TestRenderSVG(t *testing.T) {
+	output := RenderSVG(renderGrid(), 256)
+
+	var svg struct {
+		XMLName xml.Name
+		Width   string `xml:"width,attr"`
+		Height  string `xml:"height,attr"`
+		Rects   []struct {
+			X     string `xml:"x,attr"`
+			Y     string `xml:"y,attr"`
+			Title string `xml:"title"`
+		} `xml:"rect"`
+	}
+	if err := xml.Unmarshal(output, &svg); err != nil {
+		t.Fatalf("SVG inválido: %v", err)
+	}
+	if svg.XMLName.Local != "svg" || svg.XMLName.Space != "http://www.w3.org/2000/svg" {
+		t.Errorf("elemento raiz = %+v", svg.XMLName)
+	}
+	if svg.Width != "256" || svg.Height != "256" {
+		t.Errorf("dimensões = %sx%s, esperado 256x256", svg.Width, svg.Height)
+	}
+
+	// Só as células com wards; a linha 0 (base azul) fica embaixo
+	if len(svg.Rects) != 2 {
+		t.Fatalf("retângulos = %d, esperado 2", len(svg.Rects))
+	}
+	if r := svg.Rects[0]; r.X != "0.00" || r.Y != "248.00" || r.Title != "2" {
+		t.Errorf("célula da base azul = %+v", r)
+	}
+	if r := svg.Rects[1]; r.X != "248.00" || r.Y != "0.00" || r.Title != "1" {
+		t.Errorf("célula da base vermelha = %+v", r)
+	}
+}
+
+func TestRenderPNG(t *testing.T) {
+	output, err := RenderPNG(renderGrid(), 128)
+	if err != nil {
+		t.Fatalf("RenderPNG: %v", err)
+	}
+	if contentType := http.DetectContentType(output); contentType != "image/png" {
+		t.Errorf("content type = %s, esperado image/png", contentType)
+	}
+
+	img, err := png.Decode(bytes.NewReader(output))
+	if err != nil {
+		t.Fatalf("PNG inválido: %v", err)
+	}
+	if bounds := img.Bounds(); bounds != image.Rect(0, 0, 128, 128) {
+		t.Errorf("dimensões = %v, esperado 128x128", bounds)
+	}
+
+	// Cada célula ocupa 4×4 pixels: base azul no canto inferior esquerdo, com
+	// a cor do máximo; base vermelha no canto superior direito; resto transparente
+	if c := color.NRGBAModel.Convert(img.At(0, 127)).(color.NRGBA); c != heatColor(2, 2) || c.G != 0 {
+		t.Errorf("pixel da base azul = %+v, esperado o vermelho do máximo", c)
+	}
+	if _, _, _, a := img.At(127, 0).RGBA(); a == 0 {
+		t.Errorf("pixel da base vermelha transparente")
+	}
+	if _, _, _, a := img.At(64, 64).RGBA(); a != 0 {
+		t.Errorf("pixel sem wards com alfa %d", a)
+	}
+}
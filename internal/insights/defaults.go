@@ -0,0 +1,153 @@
+package insights
+
+// DefaultRules é o catálogo embutido. Regras salvas com a mesma key
+// substituem a embutida (ou a desativam, com disabled); keys novas são
+// adicionadas ao catálogo.
+func DefaultRules() []Rule {
+	return []Rule{
+		{
+			Key:        "low_control_wards",
+			Metric:     MetricControlWardsPer30Minutes,
+			Operator:   OpLess,
+			Threshold:  2,
+			MinMinutes: 15,
+			Severity:   SeverityWarning,
+			Insight: Message{
+				Key: "insights.low_control_wards",
+				Text: map[string]string{
+					"pt-BR": "Você comprou {control_wards} control wards em {minutes} minutos.",
+					"en":    "You bought {control_wards} control wards in {minutes} minutes.",
+				},
+			},
+			Suggestion: &Message{
+				Key: "suggestions.low_control_wards",
+				Text: map[string]string{
+					"pt-BR": "Compre uma control ward a cada volta à base e mantenha sempre uma no inventário.",
+					"en":    "Buy a control ward every time you back and always keep one in your inventory.",
+				},
+			},
+		},
+		{
+			Key:             "low_vision_control_ratio",
+			Metric:          MetricVisionControlRatio,
+			Operator:        OpLess,
+			Threshold:       1,
+			ThresholdMetric: PrefixBaseline + MetricVisionControlRatio,
+			Severity:        SeverityWarning,
+			Insight: Message{
+				Key: "insights.low_vision_control_ratio",
+				Text: map[string]string{
+					"pt-BR": "Sua razão de controle de visão ({value}) ficou abaixo da meta da sua role ({threshold}).",
+					"en":    "Your vision control ratio ({value}) was below your role's target ({threshold}).",
+				},
+			},
+			Suggestion: &Message{
+				Key: "suggestions.low_vision_control_ratio",
+				Text: map[string]string{
+					"pt-BR": "Use a Lente do Oráculo e control wards para limpar a visão inimiga antes dos objetivos.",
+					"en":    "Use Oracle Lens and control wards to clear enemy vision before objectives.",
+				},
+			},
+		},
+		{
+			Key:       "no_early_wards",
+			Metric:    MetricWardsFirst5Minutes,
+			Operator:  OpEqual,
+			Threshold: 0,
+			Severity:  SeverityWarning,
+			Insight: Message{
+				Key: "insights.no_early_wards",
+				Text: map[string]string{
+					"pt-BR": "Você não colocou nenhuma ward nos primeiros 5 minutos.",
+					"en":    "You placed no wards in the first 5 minutes.",
+				},
+			},
+			Suggestion: &Message{
+				Key: "suggestions.no_early_wards",
+				Text: map[string]string{
+					"pt-BR": "Use a trinket logo no início da fase de rotas para se proteger de ganks.",
+					"en":    "Use your trinket early in the laning phase to protect yourself from ganks.",
+				},
+			},
+		},
+		{
+			Key:       "low_vision_score",
+			Metric:    PrefixRating + MetricVisionScore,
+			Operator:  OpLess,
+			Threshold: 0.5,
+			Severity:  SeverityCritical,
+			Insight: Message{
+				Key: "insights.low_vision_score",
+				Text: map[string]string{
+					"pt-BR": "Seu vision score por minuto ({vision_score_per_minute}) ficou abaixo da metade da meta da sua role ({baseline.vision_score}).",
+					"en":    "Your vision score per minute ({vision_score_per_minute}) was below half of your role's target ({baseline.vision_score}).",
+				},
+			},
+			Suggestion: &Message{
+				Key: "suggestions.low_vision_score",
+				Text: map[string]string{
+					"pt-BR": "Use todas as cargas da trinket e coloque wards nas entradas da selva do seu lado do mapa.",
+					"en":    "Spend every trinket charge and ward the jungle entrances on your side of the map.",
+				},
+			},
+		},
+		{
+			Key:       "support_few_wards",
+			Metric:    MetricWardsPerMinute,
+			Operator:  OpLess,
+			Threshold: 0.8,
+			Roles:     []string{"SUPPORT"},
+			Severity:  SeverityWarning,
+			Insight: Message{
+				Key: "insights.support_few_wards",
+				Text: map[string]string{
+					"pt-BR": "Como support, você colocou {value} wards por minuto.",
+					"en":    "As support, you placed {value} wards per minute.",
+				},
+			},
+			Suggestion: &Message{
+				Key: "suggestions.support_few_wards",
+				Text: map[string]string{
+					"pt-BR": "Gaste as cargas do item de support assim que estiverem disponíveis.",
+					"en":    "Spend your support item charges as soon as they are available.",
+				},
+			},
+		},
+		{
+			Key:             "weak_objective_setup",
+			Metric:          MetricObjectiveSetup,
+			Operator:        OpLess,
+			Threshold:       0.5,
+			ThresholdMetric: PrefixBaseline + MetricObjectiveSetup,
+			Severity:        SeverityWarning,
+			Insight: Message{
+				Key: "insights.weak_objective_setup",
+				Text: map[string]string{
+					"pt-BR": "Você tinha wards em volta do covil em apenas {objectives_covered} de {objectives} objetivos.",
+					"en":    "You had wards around the pit for only {objectives_covered} of {objectives} objectives.",
+				},
+			},
+			Suggestion: &Message{
+				Key: "suggestions.weak_objective_setup",
+				Text: map[string]string{
+					"pt-BR": "Coloque wards em volta do dragão e do barão nos 90 segundos antes de nascerem ou serem disputados.",
+					"en":    "Ward around dragon and baron in the 90 seconds before they spawn or are contested.",
+				},
+			},
+		},
+		{
+			Key:       "great_vision",
+			Metric:    MetricWardScore,
+			Operator:  OpGreaterEqual,
+			Threshold: 90,
+			Severity:  SeverityPositive,
+			Insight: Message{
+				Key: "insights.great_vision",
+				Text: map[string]string{
+					"pt-BR": "Excelente controle de visão: WardScore {ward_score}.",
+					"en":    "Excellent vision control: WardScore {ward_score}.",
+				},
+			},
+		},
+	}
+}
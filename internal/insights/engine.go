@@ -0,0 +1,150 @@
+package insights
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Insight é uma observação sobre a partida gerada por uma regra. Key e
+// Params permitem ao cliente traduzir a mensagem; Text traz a mensagem já
+// preenchida em cada locale definido na regra.
+type Insight struct {
+	Rule      string            `json:"rule"`
+	Key       string            `json:"key"`
+	Severity  Severity          `json:"severity"`
+	Metric    string            `json:"metric"`
+	Value     float64           `json:"value"`
+	Threshold float64           `json:"threshold"`
+	Params    map[string]string `json:"params"`
+	Text      map[string]string `json:"text"`
+}
+
+// Suggestion é a ação recomendada por uma regra que disparou
+type Suggestion struct {
+	Rule     string            `json:"rule"`
+	Key      string            `json:"key"`
+	Severity Severity          `json:"severity"`
+	Metric   string            `json:"metric"`
+	Params   map[string]string `json:"params"`
+	Text     map[string]string `json:"text"`
+}
+
+var placeholderPattern = regexp.MustCompile(`\{([a-z0-9_.]+)\}`)
+
+// Evaluate avalia as regras sobre a partida e retorna os insights e as
+// sugestões das regras que dispararam, do mais grave para o positivo
+func Evaluate(rules []Rule, ctx *Context) ([]Insight, []Suggestion) {
+	insights := []Insight{}
+	suggestions := []Suggestion{}
+
+	for i := range rules {
+		rule := &rules[i]
+		if !rule.appliesTo(ctx) {
+			continue
+		}
+
+		value, ok := ctx.Metrics[rule.Metric]
+		if !ok {
+			continue
+		}
+		threshold := rule.Threshold
+		if rule.ThresholdMetric != "" {
+			reference, ok := ctx.Metrics[rule.ThresholdMetric]
+			if !ok {
+				continue
+			}
+			threshold *= reference
+		}
+		if !rule.Operator.compare(value, threshold) {
+			continue
+		}
+
+		params := ctx.params(value, threshold)
+		insightParams := pick(params, rule.Insight.Text)
+		insights = append(insights, Insight{
+			Rule:      rule.Key,
+			Key:       rule.Insight.Key,
+			Severity:  rule.Severity,
+			Metric:    rule.Metric,
+			Value:     round(value),
+			Threshold: round(threshold),
+			Params:    insightParams,
+			Text:      render(rule.Insight.Text, insightParams),
+		})
+
+		if rule.Suggestion != nil {
+			suggestionParams := pick(params, rule.Suggestion.Text)
+			suggestions = append(suggestions, Suggestion{
+				Rule:     rule.Key,
+				Key:      rule.Suggestion.Key,
+				Severity: rule.Severity,
+				Metric:   rule.Metric,
+				Params:   suggestionParams,
+				Text:     render(rule.Suggestion.Text, suggestionParams),
+			})
+		}
+	}
+
+	sort.SliceStable(insights, func(i, j int) bool {
+		return severityOrder[insights[i].Severity] < severityOrder[insights[j].Severity]
+	})
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return severityOrder[suggestions[i].Severity] < severityOrder[suggestions[j].Severity]
+	})
+	return insights, suggestions
+}
+
+// params são os valores disponíveis para os placeholders dos textos
+func (ctx *Context) params(value, threshold float64) map[string]string {
+	params := map[string]string{
+		"value":     formatNumber(value),
+		"threshold": formatNumber(threshold),
+		"minutes":   formatNumber(math.Round(ctx.Minutes)),
+		"role":      ctx.Role,
+		"queue":     ctx.Queue,
+		"champion":  ctx.Champion,
+	}
+	for name, metricValue := range ctx.Metrics {
+		params[name] = formatNumber(metricValue)
+	}
+	return params
+}
+
+// pick mantém só os parâmetros usados nos textos
+func pick(params map[string]string, texts map[string]string) map[string]string {
+	picked := map[string]string{}
+	for _, text := range texts {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(text, -1) {
+			if value, ok := params[match[1]]; ok {
+				picked[match[1]] = value
+			}
+		}
+	}
+	return picked
+}
+
+// render preenche os placeholders de cada locale; placeholders sem valor
+// ficam como estão
+func render(texts map[string]string, params map[string]string) map[string]string {
+	rendered := make(map[string]string, len(texts))
+	for locale, text := range texts {
+		rendered[locale] = placeholderPattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+			if value, ok := params[strings.Trim(placeholder, "{}")]; ok {
+				return value
+			}
+			return placeholder
+		})
+	}
+	return rendered
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(round(value), 'f', -1, 64)
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
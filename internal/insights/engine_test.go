@@ -0,0 +1,306 @@
+package insights
+
+import (
+	"sort"
+	"testing"
+)
+
+// goodGame é uma partida de 30 minutos em que nenhuma regra embutida dispara
+func goodGame(role string, overrides map[string]float64) *Context {
+	ctx := &Context{
+		Role:    role,
+		Queue:   "RANKED_SOLO",
+		Minutes: 30,
+		Metrics: map[string]float64{
+			MetricDurationMinutes:                     30,
+			MetricWardScore:                           70,
+			MetricControlWards:                        4,
+			MetricControlWardsPer30Minutes:            4,
+			MetricVisionControlRatio:                  0.5,
+			PrefixBaseline + MetricVisionControlRatio: 0.4,
+			MetricWardsFirst5Minutes:                  2,
+			PrefixRating + MetricVisionScore:          0.8,
+			MetricVisionScorePerMinute:                1.6,
+			PrefixBaseline + MetricVisionScore:        2,
+			MetricWardsPerMinute:                      1,
+			MetricObjectiveSetup:                      0.7,
+			PrefixBaseline + MetricObjectiveSetup:     0.6,
+			MetricObjectives:                          4,
+			MetricObjectivesCovered:                   3,
+		},
+	}
+	for name, value := range overrides {
+		if value < 0 {
+			delete(ctx.Metrics, name)
+			continue
+		}
+		ctx.Metrics[name] = value
+	}
+	if minutes, ok := overrides[MetricDurationMinutes]; ok {
+		ctx.Minutes = minutes
+	}
+	return ctx
+}
+
+func firedRules(insights []Insight) []string {
+	keys := []string{}
+	for _, insight := range insights {
+		keys = append(keys, insight.Rule)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestEvaluateDefaultRules(t *testing.T) {
+	tests := []struct {
+		name      string
+		role      string
+		overrides map[string]float64 // valor negativo remove a métrica
+		want      []string
+	}{
+		{"partida boa", "MIDDLE", nil, []string{}},
+		{"poucas control wards", "MIDDLE", map[string]float64{MetricControlWardsPer30Minutes: 1.5}, []string{"low_control_wards"}},
+		{"poucas control wards em partida curta", "MIDDLE", map[string]float64{MetricControlWardsPer30Minutes: 1.5, MetricDurationMinutes: 14}, []string{}},
+		{"razão de controle abaixo da meta", "MIDDLE", map[string]float64{MetricVisionControlRatio: 0.39}, []string{"low_vision_control_ratio"}},
+		{"razão de controle igual à meta", "MIDDLE", map[string]float64{MetricVisionControlRatio: 0.4}, []string{}},
+		{"nenhuma ward no início", "MIDDLE", map[string]float64{MetricWardsFirst5Minutes: 0}, []string{"no_early_wards"}},
+		{"sem timeline", "MIDDLE", map[string]float64{MetricWardsFirst5Minutes: -1, MetricObjectiveSetup: -1}, []string{}},
+		{"vision score abaixo da metade da meta", "MIDDLE", map[string]float64{PrefixRating + MetricVisionScore: 0.49}, []string{"low_vision_score"}},
+		{"support com poucas wards", "SUPPORT", map[string]float64{MetricWardsPerMinute: 0.5}, []string{"support_few_wards"}},
+		{"poucas wards fora do support", "BOTTOM", map[string]float64{MetricWardsPerMinute: 0.5}, []string{}},
+		// Limite = 0.5 × meta de objective_setup (0.6) = 0.3
+		{"setup de objetivos fraco", "JUNGLE", map[string]float64{MetricObjectiveSetup: 0.29}, []string{"weak_objective_setup"}},
+		{"setup de objetivos no limite", "JUNGLE", map[string]float64{MetricObjectiveSetup: 0.3}, []string{}},
+		{"meta de objective_setup ausente", "JUNGLE", map[string]float64{MetricObjectiveSetup: 0.1, PrefixBaseline + MetricObjectiveSetup: -1}, []string{}},
+		{"visão excelente", "SUPPORT", map[string]float64{MetricWardScore: 90}, []string{"great_vision"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			insights, suggestions := Evaluate(DefaultRules(), goodGame(tt.role, tt.overrides))
+
+			got := firedRules(insights)
+			if len(got) != len(tt.want) {
+				t.Fatalf("regras disparadas = %v, esperado %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("regras disparadas = %v, esperado %v", got, tt.want)
+				}
+			}
+
+			for _, suggestion := range suggestions {
+				if suggestion.Rule == "great_vision" {
+					t.Errorf("great_vision não tem sugestão")
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateThresholdMetric(t *testing.T) {
+	ctx := goodGame("JUNGLE", map[string]float64{MetricObjectiveSetup: 0.25, PrefixBaseline + MetricObjectiveSetup: 0.7})
+	insights, _ := Evaluate(DefaultRules(), ctx)
+
+	if len(insights) != 1 {
+		t.Fatalf("insights = %+v, esperado só weak_objective_setup", insights)
+	}
+	insight := insights[0]
+	if insight.Metric != MetricObjectiveSetup || insight.Value != 0.25 || insight.Threshold != 0.35 {
+		t.Errorf("métrica = %s, valor = %v, limite = %v; esperado objective_setup, 0.25 e 0.35", insight.Metric, insight.Value, insight.Threshold)
+	}
+}
+
+func TestEvaluateOperators(t *testing.T) {
+	tests := []struct {
+		operator Operator
+		value    float64
+		want     bool
+	}{
+		{OpLess, 1.9, true},
+		{OpLess, 2, false},
+		{OpLessEqual, 2, true},
+		{OpLessEqual, 2.1, false},
+		{OpGreater, 2.1, true},
+		{OpGreater, 2, false},
+		{OpGreaterEqual, 2, true},
+		{OpGreaterEqual, 1.9, false},
+		{OpEqual, 2, true},
+		{OpEqual, 2.1, false},
+		{"ne", 1, false},
+	}
+
+	for _, tt := range tests {
+		rule := Rule{
+			Key:       "operator_test",
+			Metric:    MetricControlWards,
+			Operator:  tt.operator,
+			Threshold: 2,
+			Severity:  SeverityInfo,
+			Insight:   Message{Key: "insights.operator_test", Text: map[string]string{DefaultLocale: "{value}"}},
+		}
+		ctx := &Context{Minutes: 30, Metrics: map[string]float64{MetricControlWards: tt.value}}
+
+		insights, _ := Evaluate([]Rule{rule}, ctx)
+		if got := len(insights) == 1; got != tt.want {
+			t.Errorf("%v %s 2 disparou = %v, esperado %v", tt.value, tt.operator, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateAppliesTo(t *testing.T) {
+	rule := Rule{
+		Key:        "filtered",
+		Metric:     MetricWardScore,
+		Operator:   OpGreaterEqual,
+		Threshold:  0,
+		MinMinutes: 15,
+		Roles:      []string{"SUPPORT", "JUNGLE"},
+		Queues:     []string{"RANKED_SOLO"},
+		Severity:   SeverityInfo,
+		Insight:    Message{Key: "insights.filtered", Text: map[string]string{DefaultLocale: "ok"}},
+	}
+
+	tests := []struct {
+		name     string
+		role     string
+		queue    string
+		minutes  float64
+		disabled bool
+		want     bool
+	}{
+		{"role e fila aceitas", "SUPPORT", "RANKED_SOLO", 30, false, true},
+		{"role em minúsculas", "jungle", "ranked_solo", 30, false, true},
+		{"outra role", "TOP", "RANKED_SOLO", 30, false, false},
+		{"outra fila", "SUPPORT", "ARAM", 30, false, false},
+		{"partida curta", "SUPPORT", "RANKED_SOLO", 14.9, false, false},
+		{"regra desativada", "SUPPORT", "RANKED_SOLO", 30, true, false},
+	}
+
+	for _, tt := range tests {
+		rule.Disabled = tt.disabled
+		ctx := &Context{Role: tt.role, Queue: tt.queue, Minutes: tt.minutes, Metrics: map[string]float64{MetricWardScore: 50}}
+		insights, _ := Evaluate([]Rule{rule}, ctx)
+		if got := len(insights) == 1; got != tt.want {
+			t.Errorf("%s: disparou = %v, esperado %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateRendersLocales(t *testing.T) {
+	ctx := goodGame("MIDDLE", map[string]float64{MetricControlWardsPer30Minutes: 1.2, MetricControlWards: 1, MetricDurationMinutes: 25.4})
+	insights, suggestions := Evaluate(DefaultRules(), ctx)
+	if len(insights) != 1 || len(suggestions) != 1 {
+		t.Fatalf("insights = %+v, sugestões = %+v", insights, suggestions)
+	}
+
+	insight := insights[0]
+	if insight.Key != "insights.low_control_wards" || insight.Severity != SeverityWarning {
+		t.Errorf("insight = %+v", insight)
+	}
+	wantText := map[string]string{
+		"pt-BR": "Você comprou 1 control wards em 25 minutos.",
+		"en":    "You bought 1 control wards in 25 minutes.",
+	}
+	for locale, want := range wantText {
+		if got := insight.Text[locale]; got != want {
+			t.Errorf("texto %s = %q, esperado %q", locale, got, want)
+		}
+	}
+	// Só os parâmetros usados nos textos
+	if len(insight.Params) != 2 || insight.Params["control_wards"] != "1" || insight.Params["minutes"] != "25" {
+		t.Errorf("parâmetros = %v", insight.Params)
+	}
+	if suggestions[0].Key != "suggestions.low_control_wards" || suggestions[0].Text["en"] == "" || len(suggestions[0].Params) != 0 {
+		t.Errorf("sugestão = %+v", suggestions[0])
+	}
+
+	rule := Rule{
+		Key:       "unknown_placeholder",
+		Metric:    MetricWardScore,
+		Operator:  OpGreater,
+		Threshold: 0,
+		Severity:  SeverityInfo,
+		Insight:   Message{Key: "insights.unknown_placeholder", Text: map[string]string{DefaultLocale: "{ward_score} de {desconhecido} ({threshold})"}},
+	}
+	insights, _ = Evaluate([]Rule{rule}, &Context{Minutes: 30, Metrics: map[string]float64{MetricWardScore: 71.456}})
+	if got := insights[0].Text[DefaultLocale]; got != "71.46 de {desconhecido} (0)" {
+		t.Errorf("texto = %q", got)
+	}
+}
+
+func TestEvaluateSeverityOrder(t *testing.T) {
+	ctx := goodGame("SUPPORT", map[string]float64{
+		MetricWardScore:                  95,
+		MetricWardsPerMinute:             0.5,
+		PrefixRating + MetricVisionScore: 0.3,
+		MetricControlWardsPer30Minutes:   1,
+	})
+	insights, suggestions := Evaluate(DefaultRules(), ctx)
+
+	want := []string{"low_vision_score", "low_control_wards", "support_few_wards", "great_vision"}
+	if len(insights) != len(want) {
+		t.Fatalf("insights = %+v", insights)
+	}
+	for i, rule := range want {
+		if insights[i].Rule != rule {
+			t.Errorf("insight %d = %s, esperado %s", i, insights[i].Rule, rule)
+		}
+	}
+
+	wantSuggestions := []string{"low_vision_score", "low_control_wards", "support_few_wards"}
+	if len(suggestions) != len(wantSuggestions) {
+		t.Fatalf("sugestões = %+v", suggestions)
+	}
+	for i, rule := range wantSuggestions {
+		if suggestions[i].Rule != rule {
+			t.Errorf("sugestão %d = %s, esperado %s", i, suggestions[i].Rule, rule)
+		}
+	}
+}
+
+func TestRuleValidate(t *testing.T) {
+	for _, rule := range DefaultRules() {
+		if err := rule.Validate(); err != nil {
+			t.Errorf("regra embutida %s inválida: %v", rule.Key, err)
+		}
+	}
+
+	valid := func() Rule {
+		return Rule{
+			Key:             "custom_rule",
+			Metric:          MetricVisionControlRatio,
+			Operator:        OpLess,
+			Threshold:       1,
+			ThresholdMetric: PrefixBaseline + MetricVisionControlRatio,
+			Severity:        SeverityWarning,
+			Insight:         Message{Key: "insights.custom_rule", Text: map[string]string{DefaultLocale: "texto"}},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		change func(*Rule)
+	}{
+		{"key inválida", func(r *Rule) { r.Key = "Custom Rule" }},
+		{"métrica desconhecida", func(r *Rule) { r.Metric = "kills" }},
+		{"fator desconhecido", func(r *Rule) { r.ThresholdMetric = PrefixBaseline + "kills" }},
+		{"threshold zero com threshold_metric", func(r *Rule) { r.Threshold = 0 }},
+		{"operador inválido", func(r *Rule) { r.Operator = "ne" }},
+		{"severidade inválida", func(r *Rule) { r.Severity = "fatal" }},
+		{"texto sem locale padrão", func(r *Rule) { r.Insight.Text = map[string]string{"en": "text"} }},
+		{"sugestão sem key", func(r *Rule) { r.Suggestion = &Message{Text: map[string]string{DefaultLocale: "texto"}} }},
+	}
+
+	base := valid()
+	if err := base.Validate(); err != nil {
+		t.Fatalf("regra válida recusada: %v", err)
+	}
+	for _, tt := range tests {
+		rule := valid()
+		tt.change(&rule)
+		if err := rule.Validate(); err == nil {
+			t.Errorf("%s: regra aceita", tt.name)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package insights
+
+import (
+	"math"
+	"strings"
+	"wardscore-api/internal/scoring"
+)
+
+// Métricas disponíveis para as regras, além das famílias por fator
+// (baseline.<fator>, rating.<fator> e lost_points.<fator>)
+const (
+	MetricDurationMinutes          = "duration_minutes"
+	MetricWardScore                = "ward_score"
+	MetricVisionScore              = "vision_score"
+	MetricWardsPlaced              = "wards_placed"
+	MetricWardsDestroyed           = "wards_destroyed"
+	MetricControlWards             = "control_wards"
+	MetricVisionScorePerMinute     = "vision_score_per_minute"
+	MetricWardsPerMinute           = "wards_per_minute"
+	MetricWardsDestroyedPerMinute  = "wards_destroyed_per_minute"
+	MetricControlWardsPerMinute    = "control_wards_per_minute"
+	MetricControlWardsPer30Minutes = "control_wards_per_30_minutes"
+	MetricVisionControlRatio       = "vision_control_ratio"
+
+	// Métricas que dependem da timeline da partida; sem ela as regras sobre
+	// essas métricas são ignoradas
+	MetricWardsFirst5Minutes = "wards_placed_first_5_minutes"
+	MetricObjectiveSetup     = "objective_setup"
+	MetricObjectives         = "objectives"
+	MetricObjectivesCovered  = "objectives_covered"
+)
+
+// Prefixos das famílias de métricas por fator do WardScore
+const (
+	PrefixBaseline   = "baseline."
+	PrefixRating     = "rating."
+	PrefixLostPoints = "lost_points."
+)
+
+// MetricInfo descreve uma métrica para quem escreve regras
+type MetricInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Metrics é o catálogo de métricas aceitas nas regras
+var Metrics = []MetricInfo{
+	{MetricDurationMinutes, "duração da partida em minutos"},
+	{MetricWardScore, "WardScore de 0 a 100"},
+	{MetricVisionScore, "vision score total"},
+	{MetricWardsPlaced, "wards colocadas"},
+	{MetricWardsDestroyed, "wards destruídas"},
+	{MetricControlWards, "control wards compradas"},
+	{MetricVisionScorePerMinute, "vision score por minuto"},
+	{MetricWardsPerMinute, "wards colocadas por minuto"},
+	{MetricWardsDestroyedPerMinute, "wards destruídas por minuto"},
+	{MetricControlWardsPerMinute, "control wards por minuto"},
+	{MetricControlWardsPer30Minutes, "control wards a cada 30 minutos"},
+	{MetricVisionControlRatio, "wards destruídas / colocadas"},
+	{MetricWardsFirst5Minutes, "wards colocadas nos 5 primeiros minutos (requer timeline)"},
+	{MetricObjectiveSetup, "fração dos objetivos com ward do jogador em volta do covil antes do evento (requer timeline)"},
+	{MetricObjectives, "objetivos épicos avaliados no setup de visão (requer timeline)"},
+	{MetricObjectivesCovered, "objetivos com ward do jogador em volta do covil (requer timeline)"},
+	{PrefixBaseline + "<fator>", "meta do fator no baseline da role/fila, na unidade do fator"},
+	{PrefixRating + "<fator>", "nota do fator, de 0 a 1"},
+	{PrefixLostPoints + "<fator>", "pontos do WardScore perdidos no fator"},
+}
+
+// factorMetrics associa cada fator à métrica com o valor comparado com a meta
+var factorMetrics = map[string]string{
+	scoring.FactorVisionScore:        MetricVisionScorePerMinute,
+	scoring.FactorWardsPlaced:        MetricWardsPerMinute,
+	scoring.FactorWardsDestroyed:     MetricWardsDestroyedPerMinute,
+	scoring.FactorControlWards:       MetricControlWardsPerMinute,
+	scoring.FactorVisionControlRatio: MetricVisionControlRatio,
+	scoring.FactorObjectiveSetup:     MetricObjectiveSetup,
+}
+
+// countFactors são os fatores cujo valor bruto é uma contagem da partida,
+// exposta com o nome do fator
+var countFactors = map[string]bool{
+	scoring.FactorVisionScore:    true,
+	scoring.FactorWardsPlaced:    true,
+	scoring.FactorWardsDestroyed: true,
+	scoring.FactorControlWards:   true,
+}
+
+// IsKnownMetric indica se a métrica existe no catálogo
+func IsKnownMetric(name string) bool {
+	for _, prefix := range []string{PrefixBaseline, PrefixRating, PrefixLostPoints} {
+		if strings.HasPrefix(name, prefix) {
+			return scoring.IsKnownFactor(strings.TrimPrefix(name, prefix))
+		}
+	}
+	for _, metric := range Metrics {
+		if metric.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Context é a partida avaliada pelas regras: role, fila, campeão, duração e
+// os valores das métricas disponíveis
+type Context struct {
+	Role     string
+	Queue    string
+	Champion string
+	Minutes  float64
+	Metrics  map[string]float64
+}
+
+// NewContext monta o contexto a partir das estatísticas e do resultado do
+// WardScore. Métricas que dependem de outras fontes (timeline) são
+// adicionadas com Set.
+func NewContext(in scoring.Input, result scoring.Result, role, queue, champion string) *Context {
+	minutes := float64(in.DurationSeconds) / 60.0
+
+	ctx := &Context{
+		Role:     scoring.NormalizeRole(role),
+		Queue:    scoring.NormalizeQueue(queue),
+		Champion: champion,
+		Minutes:  minutes,
+		Metrics: map[string]float64{
+			MetricDurationMinutes: minutes,
+			MetricWardScore:       result.WardScore,
+		},
+	}
+
+	for _, component := range result.Components {
+		ctx.Metrics[factorMetrics[component.Name]] = component.Value
+		ctx.Metrics[PrefixBaseline+component.Name] = component.Target
+		ctx.Metrics[PrefixRating+component.Name] = component.Rating
+		ctx.Metrics[PrefixLostPoints+component.Name] = component.LostPoints
+		if countFactors[component.Name] {
+			ctx.Metrics[component.Name] = component.Raw
+		}
+	}
+	ctx.Metrics[MetricControlWardsPer30Minutes] = float64(in.ControlWardsPlaced) / math.Max(minutes, scoring.MinGameMinutes) * 30
+
+	return ctx
+}
+
+// Set adiciona ou substitui o valor de uma métrica
+func (ctx *Context) Set(name string, value float64) {
+	ctx.Metrics[name] = value
+}
@@ -0,0 +1,163 @@
+// Package insights gera insights e sugestões de uma análise avaliando um
+// catálogo de regras sobre as métricas da partida.
+//
+// As regras são dados (Rule, serializável em JSON): cada uma compara uma
+// métrica com um limite fixo ou relativo a outra métrica (por exemplo a meta
+// do baseline da role) e, quando dispara, emite um insight e opcionalmente
+// uma sugestão. Os textos ficam por locale, com placeholders {nome}
+// preenchidos pelas métricas, e cada mensagem leva uma chave estável para o
+// frontend traduzir por conta própria.
+package insights
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultLocale é o locale obrigatório dos textos das regras
+const DefaultLocale = "pt-BR"
+
+// Severity é a gravidade de um insight
+type Severity string
+
+const (
+	SeverityPositive Severity = "positive"
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// severityOrder ordena os insights do mais grave para o positivo
+var severityOrder = map[Severity]int{
+	SeverityCritical: 0,
+	SeverityWarning:  1,
+	SeverityInfo:     2,
+	SeverityPositive: 3,
+}
+
+// Operator compara a métrica com o limite
+type Operator string
+
+const (
+	OpLess         Operator = "lt"
+	OpLessEqual    Operator = "lte"
+	OpGreater      Operator = "gt"
+	OpGreaterEqual Operator = "gte"
+	OpEqual        Operator = "eq"
+)
+
+// Message é o texto de um insight ou sugestão: chave estável e texto por locale
+type Message struct {
+	Key  string            `json:"key"`
+	Text map[string]string `json:"text"`
+}
+
+// Rule é uma regra do catálogo. A regra dispara quando
+//
+//	métrica <operator> limite
+//
+// onde limite = Threshold, ou Threshold × ThresholdMetric quando
+// ThresholdMetric é informado (Threshold 1 compara com a própria métrica).
+// Regras cuja métrica não existe na partida (ex.: dados de timeline
+// ausentes) são ignoradas.
+type Rule struct {
+	Key             string   `json:"key"`
+	Disabled        bool     `json:"disabled,omitempty"`
+	Metric          string   `json:"metric"`
+	Operator        Operator `json:"operator"`
+	Threshold       float64  `json:"threshold"`
+	ThresholdMetric string   `json:"threshold_metric,omitempty"`
+	MinMinutes      float64  `json:"min_minutes,omitempty"`
+	Roles           []string `json:"roles,omitempty"`
+	Queues          []string `json:"queues,omitempty"`
+	Severity        Severity `json:"severity"`
+	Insight         Message  `json:"insight"`
+	Suggestion      *Message `json:"suggestion,omitempty"`
+}
+
+var ruleKeyPattern = regexp.MustCompile(`^[a-z0-9_]{3,64}$`)
+
+// Validate verifica se a regra pode ser avaliada
+func (r *Rule) Validate() error {
+	if !ruleKeyPattern.MatchString(r.Key) {
+		return errors.New("key deve ter de 3 a 64 caracteres entre a-z, 0-9 e _")
+	}
+	if !IsKnownMetric(r.Metric) {
+		return fmt.Errorf("métrica desconhecida: %s", r.Metric)
+	}
+	if r.ThresholdMetric != "" && !IsKnownMetric(r.ThresholdMetric) {
+		return fmt.Errorf("métrica de limite desconhecida: %s", r.ThresholdMetric)
+	}
+	if r.ThresholdMetric != "" && r.Threshold <= 0 {
+		return errors.New("threshold deve ser maior que zero com threshold_metric (1 compara com a própria métrica)")
+	}
+	switch r.Operator {
+	case OpLess, OpLessEqual, OpGreater, OpGreaterEqual, OpEqual:
+	default:
+		return fmt.Errorf("operador inválido: %s", r.Operator)
+	}
+	if _, ok := severityOrder[r.Severity]; !ok {
+		return fmt.Errorf("severidade inválida: %s", r.Severity)
+	}
+	if err := r.Insight.validate("insight"); err != nil {
+		return err
+	}
+	if r.Suggestion != nil {
+		if err := r.Suggestion.validate("suggestion"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Message) validate(field string) error {
+	if strings.TrimSpace(m.Key) == "" {
+		return fmt.Errorf("%s.key é obrigatório", field)
+	}
+	if strings.TrimSpace(m.Text[DefaultLocale]) == "" {
+		return fmt.Errorf("%s.text precisa do locale %s", field, DefaultLocale)
+	}
+	return nil
+}
+
+// appliesTo verifica os filtros de role, fila e duração
+func (r *Rule) appliesTo(ctx *Context) bool {
+	if r.Disabled || ctx.Minutes < r.MinMinutes {
+		return false
+	}
+	if len(r.Roles) > 0 && !containsFold(r.Roles, ctx.Role) {
+		return false
+	}
+	if len(r.Queues) > 0 && !containsFold(r.Queues, ctx.Queue) {
+		return false
+	}
+	return true
+}
+
+func (op Operator) compare(value, limit float64) bool {
+	switch op {
+	case OpLess:
+		return value < limit
+	case OpLessEqual:
+		return value <= limit
+	case OpGreater:
+		return value > limit
+	case OpGreaterEqual:
+		return value >= limit
+	case OpEqual:
+		return value == limit
+	default:
+		return false
+	}
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
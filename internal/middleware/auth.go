@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"wardscore-api/internal/config"
+	"wardscore-api/internal/models"
+	"wardscore-api/internal/services"
+	"wardscore-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Chaves usadas para guardar o usuário autenticado no gin.Context
+const (
+	ContextUserIDKey    = "user_id"
+	ContextUserKey      = "user"
+	ContextSessionIDKey = "session_id"
+	ContextAPIKeyKey    = "api_key"
+)
+
+// AuthMiddleware valida o access token (Authorization: Bearer <token>) ou a
+// API key pessoal (Authorization: Bearer wsk_... ou X-API-Key) e coloca o
+// usuário autenticado no contexto
+func AuthMiddleware(userService *services.UserService, sessionService *services.SessionService, apiKeyService *services.APIKeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString, ok := bearerToken(c.GetHeader("Authorization"))
+		if !ok {
+			tokenString = strings.TrimSpace(c.GetHeader("X-API-Key"))
+			ok = tokenString != ""
+		}
+		if !ok {
+			abortUnauthorized(c, "Token de autenticação é obrigatório")
+			return
+		}
+
+		var userID uint
+		if strings.HasPrefix(tokenString, models.APIKeyPrefix) {
+			apiKey, err := apiKeyService.Authenticate(tokenString)
+			if err != nil {
+				abortUnauthorized(c, "API key inválida, revogada ou expirada")
+				return
+			}
+			userID = apiKey.UserID
+			c.Set(ContextAPIKeyKey, apiKey)
+		} else {
+			claims, err := utils.ValidateJWT(tokenString, config.AppConfig.JWTSecret)
+			if err != nil {
+				if errors.Is(err, utils.ErrTokenExpired) {
+					abortUnauthorized(c, "Token expirado")
+					return
+				}
+				abortUnauthorized(c, "Token inválido")
+				return
+			}
+
+			// Sessões revogadas (logout, reuso de refresh token) invalidam o token imediatamente
+			if !sessionService.IsActive(claims.SessionID, claims.UserID) {
+				abortUnauthorized(c, "Sessão revogada")
+				return
+			}
+			userID = claims.UserID
+			c.Set(ContextSessionIDKey, claims.SessionID)
+		}
+
+		// Garante que o usuário do token ainda existe
+		user, err := userService.GetByID(userID)
+		if err != nil {
+			abortUnauthorized(c, "Token inválido")
+			return
+		}
+
+		if user.IsBanned() {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "Usuário banido",
+			})
+			return
+		}
+
+		c.Set(ContextUserIDKey, user.ID)
+		c.Set(ContextUserKey, user)
+		c.Next()
+	}
+}
+
+// GetUserID retorna o ID do usuário autenticado
+func GetUserID(c *gin.Context) (uint, bool) {
+	value, exists := c.Get(ContextUserIDKey)
+	if !exists {
+		return 0, false
+	}
+	id, ok := value.(uint)
+	return id, ok
+}
+
+// GetSessionID retorna o ID da sessão do access token
+func GetSessionID(c *gin.Context) string {
+	return c.GetString(ContextSessionIDKey)
+}
+
+// GetAPIKey retorna a API key usada na requisição, se houver
+func GetAPIKey(c *gin.Context) (*models.APIKey, bool) {
+	value, exists := c.Get(ContextAPIKeyKey)
+	if !exists {
+		return nil, false
+	}
+	apiKey, ok := value.(*models.APIKey)
+	return apiKey, ok
+}
+
+// GetUser retorna o usuário autenticado
+func GetUser(c *gin.Context) (*models.User, bool) {
+	value, exists := c.Get(ContextUserKey)
+	if !exists {
+		return nil, false
+	}
+	user, ok := value.(*models.User)
+	return user, ok
+}
+
+func bearerToken(header string) (string, bool) {
+	const prefix = "Bearer "
+	if len(header) <= len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return "", false
+	}
+	token := strings.TrimSpace(header[len(prefix):])
+	return token, token != ""
+}
+
+func abortUnauthorized(c *gin.Context, message string) {
+	c.Header("WWW-Authenticate", `Bearer realm="wardscore-api"`)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+		"success": false,
+		"error":   message,
+	})
+}
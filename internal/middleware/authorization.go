@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"wardscore-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CanAccess verifica se o usuário autenticado é dono do recurso ou admin
+func CanAccess(c *gin.Context, ownerID uint) bool {
+	user, ok := GetUser(c)
+	return ok && user.CanAccess(ownerID)
+}
+
+// RequireSelfOrAdmin restringe rotas com ID de usuário na URL (ex: /users/:id)
+// ao próprio usuário ou a admins. Responde 403 sem consultar o banco, então
+// não revela se o usuário existe.
+func RequireSelfOrAdmin(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		targetID, err := strconv.ParseUint(c.Param(param), 10, 32)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"error":   "ID inválido",
+			})
+			return
+		}
+
+		if !CanAccess(c, uint(targetID)) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "Acesso negado",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequirePermission restringe a rota a usuários cujo papel concede a permissão.
+// Com API key, a permissão também precisa estar nos escopos da chave.
+func RequirePermission(permission models.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := GetUser(c)
+		if !ok || !user.HasPermission(permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "Permissão insuficiente",
+			})
+			return
+		}
+
+		if apiKey, isAPIKey := GetAPIKey(c); isAPIKey && !apiKey.HasScope(permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "Escopo da API key insuficiente: " + string(permission),
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireSession bloqueia rotas de conta (sessões, API keys) para requisições
+// autenticadas por API key
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, isAPIKey := GetAPIKey(c); isAPIKey {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"error":   "Rota indisponível para API keys",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
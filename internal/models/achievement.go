@@ -28,6 +28,23 @@ const (
     RarityLegendary AchievementRarity = "legendary"
 )
 
+// Tipos de critério de desbloqueio (AchievementRequirement.Type). Os de
+// partida comparam a métrica de uma análise; games conta as partidas analisadas.
+const (
+    RequirementScore        = "score"
+    RequirementVisionScore  = "vision_score"
+    RequirementWardsPlaced  = "wards_placed"
+    RequirementControlWards = "control_wards"
+    RequirementGames        = "games"
+)
+
+// AchievementRequirement é o critério guardado em Achievement.Requirement:
+// a conquista é desbloqueada quando a métrica atinge Value
+type AchievementRequirement struct {
+    Type  string  `json:"type"`
+    Value float64 `json:"value"`
+}
+
 // Achievement representa uma conquista
 type Achievement struct {
     ID        uint           `json:"id" gorm:"primaryKey"`
@@ -54,6 +71,13 @@ func (Achievement) TableName() string {
     return "achievements"
 }
 
+// RequirementSpec decodifica o critério de desbloqueio
+func (a *Achievement) RequirementSpec() AchievementRequirement {
+    var requirement AchievementRequirement
+    json.Unmarshal(a.Requirement, &requirement)
+    return requirement
+}
+
 // UserAchievement representa conquista de um usuário
 type UserAchievement struct {
     ID        uint           `json:"id" gorm:"primaryKey"`
@@ -66,9 +90,10 @@ type UserAchievement struct {
     UnlockedAt  *time.Time `json:"unlocked_at,omitempty"`
     
     // Relacionamentos
-    UserID        uint        `json:"user_id" gorm:"not null;index"`
+    // Cada conquista é desbloqueada uma única vez por usuário
+    UserID        uint        `json:"user_id" gorm:"not null;uniqueIndex:idx_user_achievements_user_achievement,priority:1"`
     User          User        `json:"user,omitempty" gorm:"foreignKey:UserID"`
-    AchievementID uint        `json:"achievement_id" gorm:"not null;index"`
+    AchievementID uint        `json:"achievement_id" gorm:"not null;index;uniqueIndex:idx_user_achievements_user_achievement,priority:2"`
     Achievement   Achievement `json:"achievement,omitempty" gorm:"foreignKey:AchievementID"`
 }
 
@@ -86,4 +111,34 @@ func (ua *UserAchievement) Unlock() {
     now := time.Now()
     ua.UnlockedAt = &now
     ua.Progress = 100
-}
\ No newline at end of file
+}
+
+// DefaultAchievements é o catálogo inicial, gravado pela migration quando a
+// tabela de conquistas está vazia
+func DefaultAchievements() []Achievement {
+    return []Achievement{
+        defaultAchievement("Primeira análise", "Tenha sua primeira partida analisada", CategorySpecial, RarityCommon, "first_analysis", RequirementGames, 1, 50),
+        defaultAchievement("Vigia dedicado", "Tenha 25 partidas analisadas", CategorySpecial, RarityRare, "dedicated_watcher", RequirementGames, 25, 200),
+        defaultAchievement("Semeador de wards", "Coloque 20 wards em uma partida", CategoryWard, RarityCommon, "ward_seeder", RequirementWardsPlaced, 20, 100),
+        defaultAchievement("Mestre das wards", "Coloque 35 wards em uma partida", CategoryWard, RarityEpic, "ward_master", RequirementWardsPlaced, 35, 300),
+        defaultAchievement("Controle total", "Compre 5 control wards em uma partida", CategoryWard, RarityRare, "control_freak", RequirementControlWards, 5, 150),
+        defaultAchievement("Olhos em todo lugar", "Alcance 80 de placar de visão em uma partida", CategoryVision, RarityRare, "all_seeing", RequirementVisionScore, 80, 150),
+        defaultAchievement("Visão lendária", "Alcance 120 de placar de visão em uma partida", CategoryVision, RarityLegendary, "legendary_vision", RequirementVisionScore, 120, 500),
+        defaultAchievement("Nota alta", "Tire WardScore 80 ou mais em uma partida", CategoryImprovement, RarityRare, "high_score", RequirementScore, 80, 150),
+        defaultAchievement("Perfeccionista", "Tire WardScore 95 ou mais em uma partida", CategoryImprovement, RarityLegendary, "perfectionist", RequirementScore, 95, 500),
+    }
+}
+
+func defaultAchievement(title, description string, category AchievementCategory, rarity AchievementRarity, icon, requirementType string, value float64, xp int) Achievement {
+    requirement, _ := json.Marshal(AchievementRequirement{Type: requirementType, Value: value})
+    reward, _ := json.Marshal(map[string]interface{}{"xp": xp, "badge": icon})
+    return Achievement{
+        Title:       title,
+        Description: description,
+        Category:    category,
+        Rarity:      rarity,
+        Icon:        icon,
+        Requirement: requirement,
+        Reward:      reward,
+    }
+}
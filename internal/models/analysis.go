@@ -3,6 +3,7 @@ package models
 import (
 	"encoding/json"
 	"time"
+	"wardscore-api/internal/scoring"
 
 	"gorm.io/gorm"
 )
@@ -16,6 +17,10 @@ type Analysis struct {
     WardScore float64 `json:"ward_score" gorm:"not null"`
     Rank      string  `json:"rank" gorm:"not null"`
 
+    // Versão do modelo de score (scoring.Version); cada replay tem no máximo
+    // uma análise por versão
+    ScoringVersion int `json:"scoring_version" gorm:"not null;default:0;uniqueIndex:idx_analyses_replay_version,priority:2,where:deleted_at IS NULL"`
+
     WardsPlaced         int     `json:"wards_placed"`
     WardsDestroyed      int     `json:"wards_destroyed"`
     VisionScore         int     `json:"vision_score"`
@@ -23,6 +28,16 @@ type Analysis struct {
     WardsPerMinute      float64 `json:"wards_per_minute"`
     VisionControlRatio  float64 `json:"vision_control_ratio"`
 
+    // Baseline de role/fila usado no cálculo do WardScore
+    Baseline json.RawMessage `json:"baseline,omitempty" gorm:"type:jsonb"`
+    // Contribuição de cada fator e faixas de rank (scoring.Breakdown)
+    Breakdown json.RawMessage `json:"breakdown,omitempty" gorm:"type:jsonb"`
+
+    // Indica se a partida tinha timeline de wards no processamento; sem ela
+    // não há heatmap, visão por zona nem setup de objetivos
+    TimelineAvailable bool `json:"timeline_available" gorm:"not null;default:false"`
+
+    // Grupos de métricas da partida (GameStats), como a visão por zona do mapa
     GameStats   json.RawMessage `json:"game_stats,omitempty" gorm:"type:jsonb"`
     Insights    json.RawMessage `json:"insights,omitempty" gorm:"type:jsonb"`
     Suggestions json.RawMessage `json:"suggestions,omitempty" gorm:"type:jsonb"`
@@ -31,7 +46,7 @@ type Analysis struct {
     // Relacionamentos com ponteiros
     UserID   uint    `json:"user_id" gorm:"not null;index"`
     User     *User   `json:"user,omitempty" gorm:"foreignKey:UserID"`
-    ReplayID uint    `json:"replay_id" gorm:"uniqueIndex;not null"`
+    ReplayID uint    `json:"replay_id" gorm:"uniqueIndex:idx_analyses_replay_version,priority:1,where:deleted_at IS NULL;not null"`
     Replay   *Replay `json:"replay,omitempty" gorm:"foreignKey:ReplayID"`
 }
 
@@ -39,23 +54,9 @@ func (Analysis) TableName() string {
     return "analyses"
 }
 
+// GetRankFromScore converte o WardScore no rank (faixas em scoring.RankThresholds)
 func (a *Analysis) GetRankFromScore() string {
-    switch {
-    case a.WardScore >= 95:
-        return "S+"
-    case a.WardScore >= 90:
-        return "S"
-    case a.WardScore >= 85:
-        return "A+"
-    case a.WardScore >= 80:
-        return "A"
-    case a.WardScore >= 70:
-        return "B+"
-    case a.WardScore >= 60:
-        return "B"
-    default:
-        return "C"
-    }
+    return scoring.Rank(a.WardScore)
 }
 
 func (a *Analysis) BeforeCreate(tx *gorm.DB) error {
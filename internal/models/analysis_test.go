@@ -0,0 +1,32 @@
+package models
+
+import "testing"
+
+func TestGetRankFromScore(t *testing.T) {
+	tests := []struct {
+		score float64
+		want  string
+	}{
+		{100, "S+"},
+		{95, "S+"},
+		{94.9, "S"},
+		{90, "S"},
+		{89.9, "A+"},
+		{85, "A+"},
+		{84.9, "A"},
+		{80, "A"},
+		{79.9, "B+"},
+		{70, "B+"},
+		{69.9, "B"},
+		{60, "B"},
+		{59.9, "C"},
+		{0, "C"},
+	}
+
+	for _, tt := range tests {
+		analysis := &Analysis{WardScore: tt.score}
+		if got := analysis.GetRankFromScore(); got != tt.want {
+			t.Errorf("GetRankFromScore(%v) = %q, esperado %q", tt.score, got, tt.want)
+		}
+	}
+}
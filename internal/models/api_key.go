@@ -0,0 +1,63 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APIKeyPrefix identifica tokens que são API keys pessoais
+const APIKeyPrefix = "wsk_"
+
+// APIKey é uma chave pessoal para scripts e ferramentas de terceiros.
+// Apenas o hash SHA-256 da chave é armazenado.
+type APIKey struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	Name      string          `json:"name" gorm:"not null"`
+	Prefix    string          `json:"prefix" gorm:"uniqueIndex;not null"` // parte pública, usada na busca
+	KeyHash   string          `json:"-" gorm:"not null"`
+	Scopes    json.RawMessage `json:"scopes" gorm:"type:jsonb;not null"` // ["replays:write", "analysis:read"]
+	ExpiresAt *time.Time      `json:"expires_at,omitempty"`
+	RevokedAt *time.Time      `json:"revoked_at,omitempty"`
+
+	// Uso
+	LastUsedAt   *time.Time `json:"last_used_at,omitempty"`
+	RequestCount int64      `json:"request_count" gorm:"default:0"`
+
+	UserID uint  `json:"user_id" gorm:"not null;index"`
+	User   *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// ScopeList decodifica os escopos da chave
+func (k *APIKey) ScopeList() []Permission {
+	var scopes []Permission
+	json.Unmarshal(k.Scopes, &scopes)
+	return scopes
+}
+
+// HasScope verifica se a chave concede a permissão
+func (k *APIKey) HasScope(permission Permission) bool {
+	for _, scope := range k.ScopeList() {
+		if scope == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive verifica se a chave não foi revogada nem expirou
+func (k *APIKey) IsActive() bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || time.Now().Before(*k.ExpiresAt)
+}
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"wardscore-api/internal/objectives"
+	"wardscore-api/internal/zones"
+)
+
+// GameStats são os grupos de métricas da partida guardados em
+// Analysis.GameStats. Grupos que dependem da timeline de wards ficam vazios
+// quando a partida não tem timeline.
+type GameStats struct {
+	Zones      *zones.Report      `json:"zones,omitempty"`
+	Objectives *objectives.Report `json:"objectives,omitempty"`
+}
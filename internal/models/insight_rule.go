@@ -0,0 +1,21 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// InsightRule é uma regra de insight salva por um admin (insights.Rule em
+// Definition). Substitui a regra embutida de mesma key ou adiciona uma nova.
+type InsightRule struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Key        string          `json:"key" gorm:"uniqueIndex;not null"`
+	Definition json.RawMessage `json:"definition" gorm:"type:jsonb;not null"`
+}
+
+func (InsightRule) TableName() string {
+	return "insight_rules"
+}
@@ -13,6 +13,7 @@ const (
     StatusProcessing ReplayStatus = "processing"
     StatusCompleted  ReplayStatus = "completed"
     StatusFailed     ReplayStatus = "failed"
+    StatusDeadLetter ReplayStatus = "dead_letter" // tentativas esgotadas, aguarda ação de um admin
 )
 
 type Replay struct {
@@ -25,8 +26,11 @@ type Replay struct {
     OriginalName string `json:"original_name" gorm:"not null"`
     FilePath     string `json:"file_path" gorm:"not null"`
     FileSize     int64  `json:"file_size"`
+    ContentHash  string `json:"content_hash" gorm:"index"` // SHA-256 do arquivo
 
-    MatchID     string `json:"match_id" gorm:"uniqueIndex;not null"`
+    // Match ID é único por usuário: companheiros de time enviam a mesma partida
+    MatchID     string `json:"match_id" gorm:"uniqueIndex:idx_replays_match_user,where:deleted_at IS NULL;not null"`
+    GameID      uint64 `json:"game_id" gorm:"index"` // gameId gravado no próprio .rofl
     GameMode    string `json:"game_mode"`
     GameVersion string `json:"game_version"`
     Duration    int    `json:"duration"`
@@ -34,13 +38,18 @@ type Replay struct {
     Role        string `json:"role"`
     Queue       string `json:"queue"`
 
-    Status ReplayStatus `json:"status" gorm:"default:'uploaded'"`
+    Status ReplayStatus `json:"status" gorm:"default:'uploaded';index"`
+
+    // Tentativas de processamento
+    Attempts    int        `json:"attempts" gorm:"default:0"`
+    LastError   string     `json:"last_error,omitempty"`
+    NextRetryAt *time.Time `json:"next_retry_at,omitempty"`
 
     UploadedAt  time.Time  `json:"uploaded_at"`
     ProcessedAt *time.Time `json:"processed_at,omitempty"`
 
     // Relacionamentos com ponteiros
-    UserID   uint      `json:"user_id" gorm:"not null;index"`
+    UserID   uint      `json:"user_id" gorm:"not null;index;uniqueIndex:idx_replays_match_user,where:deleted_at IS NULL"`
     User     *User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
     Analysis *Analysis `json:"analysis,omitempty" gorm:"foreignKey:ReplayID"`
 }
@@ -72,8 +81,28 @@ func (r *Replay) MarkAsCompleted() {
     r.Status = StatusCompleted
     now := time.Now()
     r.ProcessedAt = &now
+    r.LastError = ""
+    r.NextRetryAt = nil
 }
 
 func (r *Replay) MarkAsFailed() {
     r.Status = StatusFailed
+}
+
+// MarkAsRetrying registra a falha e agenda nova tentativa
+func (r *Replay) MarkAsRetrying(cause error, retryAt time.Time) {
+    r.Status = StatusFailed
+    r.LastError = cause.Error()
+    r.NextRetryAt = &retryAt
+}
+
+// MarkAsDeadLetter registra a falha definitiva
+func (r *Replay) MarkAsDeadLetter(cause error) {
+    r.Status = StatusDeadLetter
+    r.LastError = cause.Error()
+    r.NextRetryAt = nil
+}
+
+func (r *Replay) IsDeadLetter() bool {
+    return r.Status == StatusDeadLetter
 }
\ No newline at end of file
@@ -0,0 +1,63 @@
+package models
+
+// UserRole define o papel do usuário para autorização
+type UserRole string
+
+const (
+	RoleUser  UserRole = "user"
+	RolePro   UserRole = "pro"
+	RoleCoach UserRole = "coach"
+	RoleAdmin UserRole = "admin"
+)
+
+// Permission é uma ação autorizável na API
+type Permission string
+
+const (
+	PermReplaysRead      Permission = "replays:read"
+	PermReplaysWrite     Permission = "replays:write"
+	PermAnalysisRead     Permission = "analysis:read"
+	PermAnalysisWrite    Permission = "analysis:write"
+	PermUsersRead        Permission = "users:read"
+	PermUsersManage      Permission = "users:manage"
+	PermReplaysReprocess Permission = "replays:reprocess"
+	PermStatsRead        Permission = "stats:read"
+	PermScoringManage    Permission = "scoring:manage"
+)
+
+var basePermissions = []Permission{
+	PermReplaysRead,
+	PermReplaysWrite,
+	PermAnalysisRead,
+	PermAnalysisWrite,
+}
+
+// rolePermissions mapeia cada papel às suas permissões; admin tem todas
+var rolePermissions = map[UserRole][]Permission{
+	RoleUser:  basePermissions,
+	RolePro:   basePermissions,
+	RoleCoach: basePermissions,
+	RoleAdmin: append(append([]Permission{}, basePermissions...),
+		PermUsersRead,
+		PermUsersManage,
+		PermReplaysReprocess,
+		PermStatsRead,
+		PermScoringManage,
+	),
+}
+
+// IsValid verifica se o papel existe
+func (r UserRole) IsValid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// HasPermission verifica se o papel concede a permissão
+func (r UserRole) HasPermission(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ScoringBaseline são as metas do WardScore de uma role e fila, definidas por
+// um admin (custom) ou recalculadas a partir das análises salvas (computed).
+// Role ou fila vazia valem para todas.
+type ScoringBaseline struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Role       string          `json:"role" gorm:"uniqueIndex:idx_scoring_baselines_role_queue;not null;default:''"`
+	Queue      string          `json:"queue" gorm:"uniqueIndex:idx_scoring_baselines_role_queue;not null;default:''"`
+	Source     string          `json:"source" gorm:"not null"`
+	SampleSize int             `json:"sample_size"`
+	Targets    json.RawMessage `json:"targets" gorm:"type:jsonb;not null"` // {"vision_score": 2.8, ...}
+}
+
+func (ScoringBaseline) TableName() string {
+	return "scoring_baselines"
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TeamRole é o papel do membro no time
+type TeamRole string
+
+const (
+	TeamRoleOwner  TeamRole = "owner"
+	TeamRoleMember TeamRole = "member"
+)
+
+// Team agrupa usuários (um time ou uma comunidade) para que webhooks do time
+// recebam os eventos de todos os membros
+type Team struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	Name string `json:"name" gorm:"not null"`
+
+	OwnerID uint         `json:"owner_id" gorm:"not null;index"`
+	Owner   *User        `json:"owner,omitempty" gorm:"foreignKey:OwnerID"`
+	Members []TeamMember `json:"members,omitempty" gorm:"foreignKey:TeamID"`
+}
+
+func (Team) TableName() string {
+	return "teams"
+}
+
+// TeamMember é a participação de um usuário em um time
+type TeamMember struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Role TeamRole `json:"role" gorm:"not null;default:'member'"`
+
+	TeamID uint  `json:"team_id" gorm:"not null;uniqueIndex:idx_team_members_team_user,priority:1"`
+	UserID uint  `json:"user_id" gorm:"not null;index;uniqueIndex:idx_team_members_team_user,priority:2"`
+	User   *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+func (TeamMember) TableName() string {
+	return "team_members"
+}
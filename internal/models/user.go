@@ -12,14 +12,20 @@ type User struct {
     UpdatedAt time.Time      `json:"updated_at"`
     DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 
+    // Dados da conta Riot, preenchidos pelo login RSO
     RiotID   string `json:"riot_id" gorm:"uniqueIndex;not null"`
     GameName string `json:"game_name" gorm:"not null"`
     TagLine  string `json:"tag_line" gorm:"not null"`
     PUUID    string `json:"puuid" gorm:"uniqueIndex"`
 
-    Email     string `json:"email" gorm:"uniqueIndex;not null"`
+    Email     *string `json:"email,omitempty" gorm:"uniqueIndex"` // RSO não fornece email
     AvatarURL string `json:"avatar_url"`
     IsPro     bool   `json:"is_pro" gorm:"default:false"`
+    Role      UserRole `json:"role" gorm:"not null;default:'user'"`
+
+    // Banimento (usuários banidos não conseguem autenticar)
+    BannedAt  *time.Time `json:"banned_at,omitempty"`
+    BanReason string     `json:"ban_reason,omitempty"`
     Region    string `json:"region" gorm:"default:'BR1'"`
 
     // Relacionamentos com ponteiros para evitar referência circular
@@ -36,5 +42,28 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
     if u.Region == "" {
         u.Region = "BR1"
     }
+    if u.Role == "" {
+        u.Role = RoleUser
+    }
     return nil
+}
+
+// IsAdmin verifica se o usuário tem papel de administrador
+func (u *User) IsAdmin() bool {
+    return u.Role == RoleAdmin
+}
+
+// HasPermission verifica se o papel do usuário concede a permissão
+func (u *User) HasPermission(permission Permission) bool {
+    return u.Role.HasPermission(permission)
+}
+
+// IsBanned verifica se o usuário está banido
+func (u *User) IsBanned() bool {
+    return u.BannedAt != nil
+}
+
+// CanAccess verifica se o usuário pode ler/alterar recurso do dono informado
+func (u *User) CanAccess(ownerID uint) bool {
+    return u.ID == ownerID || u.IsAdmin()
 }
\ No newline at end of file
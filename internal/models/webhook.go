@@ -0,0 +1,114 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// WebhookEvent é um tipo de evento que pode ser assinado
+type WebhookEvent string
+
+const (
+	EventAnalysisCompleted   WebhookEvent = "analysis.completed"
+	EventReplayFailed        WebhookEvent = "replay.failed"
+	EventRankingTierChanged  WebhookEvent = "ranking.tier_changed"
+	EventAchievementUnlocked WebhookEvent = "achievement.unlocked"
+)
+
+// WebhookEvents lista os eventos suportados
+var WebhookEvents = []WebhookEvent{
+	EventAnalysisCompleted,
+	EventReplayFailed,
+	EventRankingTierChanged,
+	EventAchievementUnlocked,
+}
+
+// IsValid verifica se o evento é suportado
+func (e WebhookEvent) IsValid() bool {
+	for _, event := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhook é uma assinatura de eventos, entregue por POST na URL. Sem TeamID
+// recebe os eventos do próprio usuário; com TeamID, os de todos os membros do
+// time (UserID é o dono do time que o criou). O secret assina cada entrega
+// (HMAC-SHA256).
+type Webhook struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	URL         string          `json:"url" gorm:"not null"`
+	Description string          `json:"description"`
+	Secret      string          `json:"-" gorm:"not null"`
+	Events      json.RawMessage `json:"events" gorm:"type:jsonb;not null"` // ["analysis.completed"]
+	Active      bool            `json:"active" gorm:"default:true"`
+
+	UserID uint  `json:"user_id" gorm:"not null;index"`
+	User   *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	TeamID *uint `json:"team_id,omitempty" gorm:"index"`
+	Team   *Team `json:"team,omitempty" gorm:"foreignKey:TeamID"`
+}
+
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+// EventList decodifica os eventos assinados
+func (w *Webhook) EventList() []WebhookEvent {
+	var events []WebhookEvent
+	json.Unmarshal(w.Events, &events)
+	return events
+}
+
+// Subscribes verifica se o webhook assina o evento
+func (w *Webhook) Subscribes(event WebhookEvent) bool {
+	for _, subscribed := range w.EventList() {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// WebhookDelivery é o registro de uma entrega (log de tentativas e respostas)
+type WebhookDelivery struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	EventID string          `json:"event_id" gorm:"not null;index"` // igual em reenvios do mesmo evento
+	Event   WebhookEvent    `json:"event" gorm:"not null"`
+	Payload json.RawMessage `json:"payload" gorm:"type:jsonb;not null"`
+
+	Status         DeliveryStatus `json:"status" gorm:"default:'pending';index:idx_webhook_deliveries_due,priority:1"`
+	Attempts       int            `json:"attempts" gorm:"default:0"`
+	NextAttemptAt  *time.Time     `json:"next_attempt_at,omitempty" gorm:"index:idx_webhook_deliveries_due,priority:2"`
+	DeliveredAt    *time.Time     `json:"delivered_at,omitempty"`
+	ResponseStatus int            `json:"response_status,omitempty"`
+	ResponseBody   string         `json:"response_body,omitempty"`
+	Error          string         `json:"error,omitempty"`
+	DurationMs     int64          `json:"duration_ms"`
+
+	WebhookID uint     `json:"webhook_id" gorm:"not null;index"`
+	Webhook   *Webhook `json:"-" gorm:"foreignKey:WebhookID"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
@@ -0,0 +1,23 @@
+package models
+
+import "testing"
+
+func TestWebhookEventIsValid(t *testing.T) {
+	tests := []struct {
+		event WebhookEvent
+		want  bool
+	}{
+		{EventAnalysisCompleted, true},
+		{EventReplayFailed, true},
+		{EventRankingTierChanged, true},
+		{EventAchievementUnlocked, true},
+		{"analysis.deleted", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.event.IsValid(); got != tt.want {
+			t.Errorf("%q.IsValid() = %v, esperado %v", tt.event, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+// Package objectives mede o setup de visão antes dos objetivos épicos
+// (dragão, barão e arauto).
+//
+// Para cada nascimento ou abate de objetivo da timeline, conta as wards vivas
+// em volta do covil (zones.Pit) em algum momento da janela de setup, dos
+// SetupWindowStart antes do evento até o próprio evento. As wards são
+// separadas em do jogador, do time dele e do time inimigo, e também contadas
+// por PUUID. A cobertura (fração dos
+// objetivos com ao menos uma ward do jogador na janela) entra no WardScore
+// como o fator objective_setup.
+package objectives
+
+import (
+	"math"
+	"time"
+	"wardscore-api/internal/timeline"
+	"wardscore-api/internal/zones"
+)
+
+// Janela de setup: dos 90 segundos antes do objetivo nascer ou ser abatido
+// até o momento do evento (SetupWindowEnd antes dele)
+const (
+	SetupWindowStart = 90 * time.Second
+	SetupWindowEnd   = 0 * time.Second
+)
+
+// Relação do time que abateu o objetivo com o time do jogador
+const (
+	TakenByAlly  = "ally"
+	TakenByEnemy = "enemy"
+)
+
+// Setup é a visão em volta do covil antes de um objetivo
+type Setup struct {
+	Objective   string `json:"objective"`
+	Event       string `json:"event"`
+	At          int64  `json:"at"`
+	TakenBy     string `json:"taken_by,omitempty"`
+	PlayerWards int    `json:"player_wards"`
+	AllyWards   int    `json:"ally_wards"`
+	EnemyWards  int    `json:"enemy_wards"`
+
+	// WardsByPlayer conta as wards de cada jogador (PUUID), dos dois times
+	WardsByPlayer map[string]int `json:"wards_by_player"`
+}
+
+// Report é o setup de visão do jogador em todos os objetivos da partida
+type Report struct {
+	WindowStartSeconds float64 `json:"window_start_seconds"`
+	WindowEndSeconds   float64 `json:"window_end_seconds"`
+	Objectives         int     `json:"objectives"`
+	Covered            int     `json:"covered"`
+	Coverage           float64 `json:"coverage"`
+	AvgPlayerWards     float64 `json:"avg_player_wards"`
+	AvgAllyWards       float64 `json:"avg_ally_wards"`
+	AvgEnemyWards      float64 `json:"avg_enemy_wards"`
+	Setups             []Setup `json:"setups"`
+}
+
+// Analyze mede o setup de visão do jogador (puuid, do time team) nos
+// objetivos da timeline, numa partida de gameEnd ms. Objetivos cujo covil o
+// catálogo não conhece são ignorados.
+func Analyze(tl *timeline.Timeline, catalog *zones.Catalog, puuid, team string, gameEnd int64) *Report {
+	report := &Report{
+		WindowStartSeconds: SetupWindowStart.Seconds(),
+		WindowEndSeconds:   SetupWindowEnd.Seconds(),
+		Setups:             []Setup{},
+	}
+
+	var playerWards, allyWards, enemyWards int
+	for _, objective := range tl.Objectives {
+		pit := catalog.Pit(objective.Type)
+		if pit == nil {
+			continue
+		}
+
+		setup := Setup{
+			Objective: objective.Type,
+			Event:     objective.Event,
+			At:        objective.At,
+			TakenBy:   takenBy(objective, team),
+
+			WardsByPlayer: map[string]int{},
+		}
+
+		windowStart := objective.At - SetupWindowStart.Milliseconds()
+		windowEnd := objective.At - SetupWindowEnd.Milliseconds()
+		for _, ward := range tl.Wards {
+			if !nearPit(ward, pit) || !aliveBetween(ward, windowStart, windowEnd, gameEnd) {
+				continue
+			}
+			if ward.PUUID != "" {
+				setup.WardsByPlayer[ward.PUUID]++
+			}
+			switch {
+			case puuid != "" && ward.PUUID == puuid:
+				setup.PlayerWards++
+				setup.AllyWards++
+			case team != "" && ward.Team == team:
+				setup.AllyWards++
+			case ward.Team != "":
+				setup.EnemyWards++
+			}
+		}
+
+		report.Setups = append(report.Setups, setup)
+		report.Objectives++
+		if setup.PlayerWards > 0 {
+			report.Covered++
+		}
+		playerWards += setup.PlayerWards
+		allyWards += setup.AllyWards
+		enemyWards += setup.EnemyWards
+	}
+
+	if report.Objectives > 0 {
+		n := float64(report.Objectives)
+		report.Coverage = round(float64(report.Covered) / n)
+		report.AvgPlayerWards = round(float64(playerWards) / n)
+		report.AvgAllyWards = round(float64(allyWards) / n)
+		report.AvgEnemyWards = round(float64(enemyWards) / n)
+	}
+	return report
+}
+
+// takenBy indica se o objetivo abatido ficou com o time do jogador
+func takenBy(objective timeline.Objective, team string) string {
+	if objective.Event != timeline.ObjectiveKill || objective.Team == "" || team == "" {
+		return ""
+	}
+	if objective.Team == team {
+		return TakenByAlly
+	}
+	return TakenByEnemy
+}
+
+func nearPit(ward timeline.Ward, pit *zones.Pit) bool {
+	dx := float64(ward.X) - pit.Center.X
+	dy := float64(ward.Y) - pit.Center.Y
+	return math.Hypot(dx, dy) <= pit.SetupRadius
+}
+
+// aliveBetween indica se a ward esteve no mapa em algum momento entre start e end (ms)
+func aliveBetween(ward timeline.Ward, start, end, gameEnd int64) bool {
+	return ward.PlacedAt <= end && ward.PlacedAt+ward.Lifetime(gameEnd) >= start
+}
+
+func round(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
@@ -0,0 +1,151 @@
+package objectives
+
+import (
+	"testing"
+	"wardscore-api/internal/timeline"
+	"wardscore-api/internal/zones"
+)
+
+const (
+	player = "puuid-jungle"
+	ally   = "puuid-support"
+	enemy  = "puuid-enemy"
+
+	// Dragão abatido aos 10 minutos
+	dragonAt = int64(600000)
+	gameEnd  = int64(1800000)
+)
+
+// wardAt cria uma ward em volta do covil do dragão colocada offset ms
+// antes do abate
+func wardAt(puuid, team, wardType string, offset, removedOffset int64) timeline.Ward {
+	ward := timeline.Ward{PUUID: puuid, Team: team, Type: wardType, X: 9500, Y: 4200, PlacedAt: dragonAt - offset}
+	if removedOffset != 0 {
+		ward.RemovedAt = dragonAt - removedOffset
+	}
+	return ward
+}
+
+func TestAnalyzeSetupWindow(t *testing.T) {
+	tests := []struct {
+		name string
+		ward timeline.Ward
+		want int
+	}{
+		{"colocada 91 s antes e viva na janela", wardAt(player, "100", timeline.WardControl, 91000, 0), 1},
+		{"colocada 90 s antes", wardAt(player, "100", timeline.WardControl, 90000, 0), 1},
+		{"colocada 60 s antes", wardAt(player, "100", timeline.WardControl, 60000, 0), 1},
+		{"colocada 59 s antes", wardAt(player, "100", timeline.WardControl, 59000, 0), 1},
+		{"colocada no momento do objetivo", wardAt(player, "100", timeline.WardControl, 0, 0), 1},
+		{"colocada depois do objetivo", wardAt(player, "100", timeline.WardControl, -1000, 0), 0},
+		{"removida antes da janela", wardAt(player, "100", timeline.WardControl, 200000, 95000), 0},
+		{"removida no início da janela", wardAt(player, "100", timeline.WardControl, 200000, 90000), 1},
+		{"trinket expirada antes da janela", wardAt(player, "100", timeline.WardYellowTrinket, 200000, 0), 0},
+		{"trinket viva no início da janela", wardAt(player, "100", timeline.WardYellowTrinket, 150000, 0), 1},
+		{"longe do covil", timeline.Ward{PUUID: player, Team: "100", Type: timeline.WardControl, X: 2000, Y: 2000, PlacedAt: dragonAt - 80000}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := &timeline.Timeline{
+				Wards:      []timeline.Ward{tt.ward},
+				Objectives: []timeline.Objective{{Type: timeline.ObjectiveDragon, Event: timeline.ObjectiveKill, Team: "100", At: dragonAt}},
+			}
+			report := Analyze(tl, zones.Current(), player, "100", gameEnd)
+			if report.Objectives != 1 {
+				t.Fatalf("objetivos = %d, esperado 1", report.Objectives)
+			}
+			if got := report.Setups[0].PlayerWards; got != tt.want {
+				t.Errorf("wards do jogador = %d, esperado %d", got, tt.want)
+			}
+			if report.Covered != tt.want {
+				t.Errorf("objetivos cobertos = %d, esperado %d", report.Covered, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzePits(t *testing.T) {
+	catalog := zones.Current()
+	dragon := catalog.Pit(timeline.ObjectiveDragon).Center
+	baron := catalog.Pit(timeline.ObjectiveBaron).Center
+
+	wardNear := func(point zones.Point) timeline.Ward {
+		return timeline.Ward{PUUID: player, Team: "100", Type: timeline.WardControl, X: int(point.X), Y: int(point.Y), PlacedAt: dragonAt - 80000}
+	}
+
+	tests := []struct {
+		objective string
+		ward      timeline.Ward
+		want      int
+	}{
+		{timeline.ObjectiveDragon, wardNear(dragon), 1},
+		{timeline.ObjectiveDragon, wardNear(baron), 0},
+		{timeline.ObjectiveBaron, wardNear(baron), 1},
+		{timeline.ObjectiveBaron, wardNear(dragon), 0},
+		// O arauto nasce no covil do barão
+		{timeline.ObjectiveHerald, wardNear(baron), 1},
+		{timeline.ObjectiveHerald, wardNear(dragon), 0},
+	}
+
+	for _, tt := range tests {
+		tl := &timeline.Timeline{
+			Wards:      []timeline.Ward{tt.ward},
+			Objectives: []timeline.Objective{{Type: tt.objective, Event: timeline.ObjectiveSpawn, At: dragonAt}},
+		}
+		report := Analyze(tl, catalog, player, "100", gameEnd)
+		if got := report.Setups[0].PlayerWards; got != tt.want {
+			t.Errorf("%s com ward em (%d, %d): wards do jogador = %d, esperado %d", tt.objective, tt.ward.X, tt.ward.Y, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyzeReport(t *testing.T) {
+	tl := &timeline.Timeline{
+		Wards: []timeline.Ward{
+			wardAt(player, "100", timeline.WardControl, 80000, 0),
+			wardAt(ally, "100", timeline.WardSight, 70000, 0),
+			wardAt(enemy, "200", timeline.WardControl, 85000, 0),
+		},
+		Objectives: []timeline.Objective{
+			{Type: timeline.ObjectiveDragon, Event: timeline.ObjectiveKill, Team: "200", At: dragonAt},
+			{Type: timeline.ObjectiveBaron, Event: timeline.ObjectiveKill, Team: "100", At: 1500000},
+		},
+	}
+
+	report := Analyze(tl, zones.Current(), player, "100", gameEnd)
+	if report.Objectives != 2 || report.Covered != 1 || report.Coverage != 0.5 {
+		t.Errorf("objetivos = %d, cobertos = %d, cobertura = %v; esperado 2, 1 e 0.5", report.Objectives, report.Covered, report.Coverage)
+	}
+
+	dragon := report.Setups[0]
+	if dragon.PlayerWards != 1 || dragon.AllyWards != 2 || dragon.EnemyWards != 1 {
+		t.Errorf("dragão = %+v, esperado 1 do jogador, 2 do time e 1 inimiga", dragon)
+	}
+	if dragon.TakenBy != TakenByEnemy {
+		t.Errorf("dragão abatido por %q, esperado %q", dragon.TakenBy, TakenByEnemy)
+	}
+	wantByPlayer := map[string]int{player: 1, ally: 1, enemy: 1}
+	if len(dragon.WardsByPlayer) != len(wantByPlayer) {
+		t.Errorf("wards por jogador no dragão = %v, esperado %v", dragon.WardsByPlayer, wantByPlayer)
+	}
+	for puuid, want := range wantByPlayer {
+		if got := dragon.WardsByPlayer[puuid]; got != want {
+			t.Errorf("wards de %s no dragão = %d, esperado %d", puuid, got, want)
+		}
+	}
+	if baron := report.Setups[1]; baron.TakenBy != TakenByAlly || baron.PlayerWards != 0 || len(baron.WardsByPlayer) != 0 {
+		t.Errorf("barão = %+v", baron)
+	}
+	if report.WindowStartSeconds != 90 || report.WindowEndSeconds != 0 {
+		t.Errorf("janela = %v a %v s, esperado 90 a 0", report.WindowStartSeconds, report.WindowEndSeconds)
+	}
+}
+
+func TestAnalyzeWithoutObjectives(t *testing.T) {
+	tl := &timeline.Timeline{Wards: []timeline.Ward{wardAt(player, "100", timeline.WardControl, 80000, 0)}}
+	report := Analyze(tl, zones.Current(), player, "100", gameEnd)
+	if report.Objectives != 0 || report.Coverage != 0 || len(report.Setups) != 0 {
+		t.Errorf("relatório sem objetivos = %+v", report)
+	}
+}
@@ -0,0 +1,40 @@
+package rofl
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidMagic indica que o arquivo não começa com a assinatura ROFL
+var ErrInvalidMagic = errors.New("rofl: assinatura inválida, não é um arquivo .rofl")
+
+// TruncatedError indica que o arquivo termina antes de uma seção esperada
+type TruncatedError struct {
+	Section string
+	Offset  int64
+	Length  int64
+	Size    int64
+}
+
+func (e *TruncatedError) Error() string {
+	return fmt.Sprintf("rofl: arquivo truncado em %s (esperado %d bytes em %d, arquivo tem %d)",
+		e.Section, e.Length, e.Offset, e.Size)
+}
+
+// CorruptError indica que uma seção existe mas tem conteúdo inválido
+type CorruptError struct {
+	Section string
+	Reason  string
+	Err     error
+}
+
+func (e *CorruptError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("rofl: %s corrompido: %s: %v", e.Section, e.Reason, e.Err)
+	}
+	return fmt.Sprintf("rofl: %s corrompido: %s", e.Section, e.Reason)
+}
+
+func (e *CorruptError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,83 @@
+package rofl
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// PlayerStats são as estatísticas de um jogador no statsJson. Os valores
+// chegam como strings ("VISION_SCORE": "42"), então são guardados como tal.
+type PlayerStats map[string]string
+
+func newPlayerStats(raw map[string]interface{}) PlayerStats {
+	stats := make(PlayerStats, len(raw))
+	for key, value := range raw {
+		switch v := value.(type) {
+		case string:
+			stats[key] = v
+		case float64:
+			stats[key] = strconv.FormatFloat(v, 'f', -1, 64)
+		case nil:
+			stats[key] = ""
+		default:
+			stats[key] = fmt.Sprint(v)
+		}
+	}
+	return stats
+}
+
+// Int lê uma estatística numérica; ausente ou inválida vale 0
+func (p PlayerStats) Int(key string) int {
+	value, err := strconv.Atoi(p[key])
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// PUUID do jogador (presente a partir do patch 13.x)
+func (p PlayerStats) PUUID() string {
+	return p["PUUID"]
+}
+
+// Name retorna o gameName do Riot ID, com fallback para o nome de invocador
+func (p PlayerStats) Name() string {
+	if name := p["RIOT_ID_GAME_NAME"]; name != "" {
+		return name
+	}
+	return p["NAME"]
+}
+
+// Champion retorna o campeão jogado (campo SKIN)
+func (p PlayerStats) Champion() string {
+	return p["SKIN"]
+}
+
+// Team retorna o lado do jogador ("100" azul, "200" vermelho)
+func (p PlayerStats) Team() string {
+	return p["TEAM"]
+}
+
+// Position retorna a posição (TOP, JUNGLE, MIDDLE, BOTTOM, UTILITY)
+func (p PlayerStats) Position() string {
+	if position := p["TEAM_POSITION"]; position != "" {
+		return position
+	}
+	return p["INDIVIDUAL_POSITION"]
+}
+
+func (p PlayerStats) VisionScore() int {
+	return p.Int("VISION_SCORE")
+}
+
+func (p PlayerStats) WardsPlaced() int {
+	return p.Int("WARD_PLACED")
+}
+
+func (p PlayerStats) WardsKilled() int {
+	return p.Int("WARD_KILLED")
+}
+
+func (p PlayerStats) ControlWardsBought() int {
+	return p.Int("VISION_WARDS_BOUGHT_IN_GAME")
+}
@@ -0,0 +1,277 @@
+// Package rofl lê arquivos de replay do League of Legends (.rofl).
+//
+// Layout (todos os inteiros little-endian):
+//
+//	0x000  magic          "RIOT\x00\x00" (6 bytes)
+//	0x006  signature      256 bytes
+//	0x106  header         headerLength u16, fileLength u32,
+//	                      metadataOffset u32, metadataLength u32,
+//	                      payloadHeaderOffset u32, payloadHeaderLength u32,
+//	                      payloadOffset u32
+//	...    metadata       JSON (gameLength, gameVersion, statsJson, ...)
+//	...    payloadHeader  gameId u64, gameLength u32, keyframeCount u32,
+//	                      chunkCount u32, endStartupChunkId u32,
+//	                      startGameChunkId u32, keyframeInterval u32,
+//	                      encryptionKeyLength u16, encryptionKey
+//
+// Apenas header, metadados e payload header são lidos; os chunks do jogo
+// (a maior parte do arquivo) não são carregados.
+package rofl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+)
+
+const (
+	magicLength     = 6
+	signatureLength = 256
+	headerOffset    = magicLength + signatureLength
+	headerLength    = 26
+	payloadMinSize  = 8 + 4*6 + 2
+
+	// Limite defensivo para o JSON de metadados
+	maxMetadataLength = 4 * 1024 * 1024
+
+	// O payload header tem tamanho fixo mais a chave de criptografia, cujo
+	// tamanho é um u16
+	maxPayloadHeaderLength = payloadMinSize + 0xFFFF
+)
+
+var magic = []byte{'R', 'I', 'O', 'T', 0, 0}
+
+// Header é o cabeçalho de tamanhos e offsets das seções
+type Header struct {
+	HeaderLength        uint16
+	FileLength          uint32
+	MetadataOffset      uint32
+	MetadataLength      uint32
+	PayloadHeaderOffset uint32
+	PayloadHeaderLength uint32
+	PayloadOffset       uint32
+}
+
+// PayloadHeader descreve os chunks do jogo
+type PayloadHeader struct {
+	GameID            uint64
+	GameLength        uint32 // ms
+	KeyframeCount     uint32
+	ChunkCount        uint32
+	EndStartupChunkID uint32
+	StartGameChunkID  uint32
+	KeyframeInterval  uint32
+	EncryptionKey     string
+}
+
+// Metadata é o JSON embutido com dados do jogo e estatísticas por jogador
+type Metadata struct {
+	GameLength      int64 // ms
+	GameVersion     string
+	LastGameChunkID int
+	LastKeyFrameID  int
+	Players         []PlayerStats
+}
+
+// File é o resultado da leitura de um .rofl
+type File struct {
+	Header        Header
+	Metadata      Metadata
+	PayloadHeader PayloadHeader
+}
+
+// Duration retorna a duração da partida
+func (f *File) Duration() time.Duration {
+	return time.Duration(f.Metadata.GameLength) * time.Millisecond
+}
+
+// GameID retorna o ID da partida no servidor da Riot
+func (f *File) GameID() uint64 {
+	return f.PayloadHeader.GameID
+}
+
+// FindPlayer busca o jogador pelo PUUID ou, em versões antigas sem PUUID, pelo nome
+func (f *File) FindPlayer(puuid, gameName string) *PlayerStats {
+	for i := range f.Metadata.Players {
+		player := &f.Metadata.Players[i]
+		if puuid != "" && player.PUUID() == puuid {
+			return player
+		}
+	}
+	for i := range f.Metadata.Players {
+		player := &f.Metadata.Players[i]
+		if gameName != "" && player.Name() == gameName {
+			return player
+		}
+	}
+	return nil
+}
+
+// Parse lê header, metadados e payload header de um .rofl
+func Parse(r io.ReadSeeker) (*File, error) {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, headerOffset+headerLength)
+	if err := readSection(r, size, "header", 0, int64(len(head)), head); err != nil {
+		var truncated *TruncatedError
+		if errors.As(err, &truncated) && size >= magicLength && !bytes.Equal(head[:magicLength], magic) {
+			return nil, ErrInvalidMagic
+		}
+		return nil, err
+	}
+	if !bytes.Equal(head[:magicLength], magic) {
+		return nil, ErrInvalidMagic
+	}
+
+	// Os tamanhos e offsets vêm do próprio arquivo: são validados contra
+	// limites fixos e o tamanho do arquivo antes de qualquer alocação
+	header := parseHeader(head[headerOffset:])
+	if err := validateHeader(header, size); err != nil {
+		return nil, err
+	}
+
+	metadataBuf := make([]byte, header.MetadataLength)
+	if err := readSection(r, size, "metadata", int64(header.MetadataOffset), int64(header.MetadataLength), metadataBuf); err != nil {
+		return nil, err
+	}
+	metadata, err := parseMetadata(metadataBuf)
+	if err != nil {
+		return nil, err
+	}
+
+	payloadBuf := make([]byte, header.PayloadHeaderLength)
+	if err := readSection(r, size, "payload header", int64(header.PayloadHeaderOffset), int64(header.PayloadHeaderLength), payloadBuf); err != nil {
+		return nil, err
+	}
+	payload, err := parsePayloadHeader(payloadBuf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &File{
+		Header:        header,
+		Metadata:      *metadata,
+		PayloadHeader: *payload,
+	}, nil
+}
+
+func readSection(r io.ReadSeeker, size int64, section string, offset, length int64, buf []byte) error {
+	if offset+length > size {
+		// Lê o que existir para permitir checar a assinatura em arquivos curtos
+		if _, err := r.Seek(offset, io.SeekStart); err == nil {
+			io.ReadFull(r, buf[:max(0, min(length, size-offset))])
+		}
+		return &TruncatedError{Section: section, Offset: offset, Length: length, Size: size}
+	}
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := io.ReadFull(r, buf); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+			return &TruncatedError{Section: section, Offset: offset, Length: length, Size: size}
+		}
+		return err
+	}
+	return nil
+}
+
+func parseHeader(buf []byte) Header {
+	return Header{
+		HeaderLength:        binary.LittleEndian.Uint16(buf[0:2]),
+		FileLength:          binary.LittleEndian.Uint32(buf[2:6]),
+		MetadataOffset:      binary.LittleEndian.Uint32(buf[6:10]),
+		MetadataLength:      binary.LittleEndian.Uint32(buf[10:14]),
+		PayloadHeaderOffset: binary.LittleEndian.Uint32(buf[14:18]),
+		PayloadHeaderLength: binary.LittleEndian.Uint32(buf[18:22]),
+		PayloadOffset:       binary.LittleEndian.Uint32(buf[22:26]),
+	}
+}
+
+func validateHeader(h Header, size int64) error {
+	if int64(h.FileLength) > size {
+		return &TruncatedError{Section: "arquivo", Offset: 0, Length: int64(h.FileLength), Size: size}
+	}
+	if h.MetadataOffset < headerOffset+headerLength {
+		return &CorruptError{Section: "header", Reason: "offset de metadados sobrepõe o cabeçalho"}
+	}
+	if h.MetadataLength == 0 || h.MetadataLength > maxMetadataLength {
+		return &CorruptError{Section: "header", Reason: "tamanho de metadados fora do intervalo"}
+	}
+	if h.PayloadHeaderLength < payloadMinSize || h.PayloadHeaderLength > maxPayloadHeaderLength {
+		return &CorruptError{Section: "header", Reason: "tamanho do payload header fora do intervalo"}
+	}
+	if end := int64(h.MetadataOffset) + int64(h.MetadataLength); end > size {
+		return &TruncatedError{Section: "metadata", Offset: int64(h.MetadataOffset), Length: int64(h.MetadataLength), Size: size}
+	}
+	if end := int64(h.PayloadHeaderOffset) + int64(h.PayloadHeaderLength); end > size {
+		return &TruncatedError{Section: "payload header", Offset: int64(h.PayloadHeaderOffset), Length: int64(h.PayloadHeaderLength), Size: size}
+	}
+	return nil
+}
+
+func parseMetadata(buf []byte) (*Metadata, error) {
+	var raw struct {
+		GameLength      int64  `json:"gameLength"`
+		GameVersion     string `json:"gameVersion"`
+		LastGameChunkID int    `json:"lastGameChunkId"`
+		LastKeyFrameID  int    `json:"lastKeyFrameId"`
+		StatsJSON       string `json:"statsJson"`
+	}
+	if err := json.Unmarshal(buf, &raw); err != nil {
+		return nil, &CorruptError{Section: "metadata", Reason: "JSON inválido", Err: err}
+	}
+
+	// statsJson é um JSON serializado dentro de uma string
+	var rawPlayers []map[string]interface{}
+	if raw.StatsJSON != "" {
+		if err := json.Unmarshal([]byte(raw.StatsJSON), &rawPlayers); err != nil {
+			return nil, &CorruptError{Section: "metadata", Reason: "statsJson inválido", Err: err}
+		}
+	}
+	if len(rawPlayers) == 0 {
+		return nil, &CorruptError{Section: "metadata", Reason: "sem estatísticas de jogadores"}
+	}
+
+	players := make([]PlayerStats, 0, len(rawPlayers))
+	for _, rp := range rawPlayers {
+		players = append(players, newPlayerStats(rp))
+	}
+
+	return &Metadata{
+		GameLength:      raw.GameLength,
+		GameVersion:     raw.GameVersion,
+		LastGameChunkID: raw.LastGameChunkID,
+		LastKeyFrameID:  raw.LastKeyFrameID,
+		Players:         players,
+	}, nil
+}
+
+func parsePayloadHeader(buf []byte) (*PayloadHeader, error) {
+	payload := &PayloadHeader{
+		GameID:            binary.LittleEndian.Uint64(buf[0:8]),
+		GameLength:        binary.LittleEndian.Uint32(buf[8:12]),
+		KeyframeCount:     binary.LittleEndian.Uint32(buf[12:16]),
+		ChunkCount:        binary.LittleEndian.Uint32(buf[16:20]),
+		EndStartupChunkID: binary.LittleEndian.Uint32(buf[20:24]),
+		StartGameChunkID:  binary.LittleEndian.Uint32(buf[24:28]),
+		KeyframeInterval:  binary.LittleEndian.Uint32(buf[28:32]),
+	}
+
+	keyLength := int(binary.LittleEndian.Uint16(buf[32:34]))
+	if 34+keyLength > len(buf) {
+		return nil, &CorruptError{Section: "payload header", Reason: "chave de criptografia excede a seção"}
+	}
+	payload.EncryptionKey = string(buf[34 : 34+keyLength])
+
+	if payload.GameID == 0 {
+		return nil, &CorruptError{Section: "payload header", Reason: "gameId ausente"}
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,242 @@
+package rofl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+// Offsets dos campos do header no arquivo
+const (
+	fileLengthOffset          = headerOffset + 2
+	metadataLengthOffset      = headerOffset + 10
+	payloadHeaderOffsetOffset = headerOffset + 14
+	payloadHeaderLengthOffset = headerOffset + 18
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("falha ao ler fixture %s: %v", name, err)
+	}
+	return data
+}
+
+func TestParseValid(t *testing.T) {
+	file, err := Parse(bytes.NewReader(readFixture(t, "valid.rofl")))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if got, want := file.Duration(), 1834*time.Second; got != want {
+		t.Errorf("Duration = %v, esperado %v", got, want)
+	}
+	if got, want := file.Metadata.GameVersion, "14.3.565.1234"; got != want {
+		t.Errorf("GameVersion = %q, esperado %q", got, want)
+	}
+	if got, want := file.GameID(), uint64(4912345678); got != want {
+		t.Errorf("GameID = %d, esperado %d", got, want)
+	}
+	if got, want := file.Metadata.LastGameChunkID, 62; got != want {
+		t.Errorf("LastGameChunkID = %d, esperado %d", got, want)
+	}
+	if got, want := len(file.Metadata.Players), 2; got != want {
+		t.Fatalf("jogadores = %d, esperado %d", got, want)
+	}
+
+	tests := []struct {
+		puuid, name  string
+		champion     string
+		team         string
+		position     string
+		visionScore  int
+		wardsPlaced  int
+		wardsKilled  int
+		controlWards int
+	}{
+		{"puuid-blue-support", "Vigia", "Thresh", "100", "UTILITY", 74, 31, 9, 7},
+		{"puuid-red-jungle", "Sombra", "LeeSin", "200", "JUNGLE", 38, 12, 6, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player := file.FindPlayer(tt.puuid, "")
+			if player == nil {
+				t.Fatalf("jogador %s não encontrado", tt.puuid)
+			}
+			if byName := file.FindPlayer("", tt.name); byName == nil || byName.PUUID() != tt.puuid {
+				t.Errorf("FindPlayer pelo nome %q não retornou %s", tt.name, tt.puuid)
+			}
+			if got := player.Champion(); got != tt.champion {
+				t.Errorf("Champion = %q, esperado %q", got, tt.champion)
+			}
+			if got := player.Team(); got != tt.team {
+				t.Errorf("Team = %q, esperado %q", got, tt.team)
+			}
+			if got := player.Position(); got != tt.position {
+				t.Errorf("Position = %q, esperado %q", got, tt.position)
+			}
+			if got := player.VisionScore(); got != tt.visionScore {
+				t.Errorf("VISION_SCORE = %d, esperado %d", got, tt.visionScore)
+			}
+			if got := player.WardsPlaced(); got != tt.wardsPlaced {
+				t.Errorf("WARD_PLACED = %d, esperado %d", got, tt.wardsPlaced)
+			}
+			if got := player.WardsKilled(); got != tt.wardsKilled {
+				t.Errorf("WARD_KILLED = %d, esperado %d", got, tt.wardsKilled)
+			}
+			if got := player.ControlWardsBought(); got != tt.controlWards {
+				t.Errorf("VISION_WARDS_BOUGHT_IN_GAME = %d, esperado %d", got, tt.controlWards)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	valid := readFixture(t, "valid.rofl")
+
+	tests := []struct {
+		name   string
+		mutate func(data []byte) []byte
+		check  func(t *testing.T, err error)
+	}{
+		{
+			name: "assinatura inválida",
+			mutate: func(data []byte) []byte {
+				data[0] = 'X'
+				return data
+			},
+			check: expectIs(ErrInvalidMagic),
+		},
+		{
+			name: "arquivo curto sem assinatura",
+			mutate: func(data []byte) []byte {
+				return []byte("PK\x03\x04 não é replay")
+			},
+			check: expectIs(ErrInvalidMagic),
+		},
+		{
+			name: "header truncado",
+			mutate: func(data []byte) []byte {
+				return data[:headerOffset+10]
+			},
+			check: expectTruncated("header"),
+		},
+		{
+			name: "fileLength maior que o arquivo",
+			mutate: func(data []byte) []byte {
+				return data[:len(data)-10]
+			},
+			check: expectTruncated("arquivo"),
+		},
+		{
+			name: "payload header depois do fim do arquivo",
+			mutate: func(data []byte) []byte {
+				binary.LittleEndian.PutUint32(data[payloadHeaderOffsetOffset:], uint32(len(data)))
+				return data
+			},
+			check: expectTruncated("payload header"),
+		},
+		{
+			name: "metadados depois do fim do arquivo",
+			mutate: func(data []byte) []byte {
+				binary.LittleEndian.PutUint32(data[metadataLengthOffset:], uint32(len(data)))
+				return data
+			},
+			check: expectTruncated("metadata"),
+		},
+		{
+			name: "metadados maiores que o limite",
+			mutate: func(data []byte) []byte {
+				binary.LittleEndian.PutUint32(data[metadataLengthOffset:], maxMetadataLength+1)
+				return data
+			},
+			check: expectCorrupt("header"),
+		},
+		{
+			name: "payload header maior que o limite",
+			mutate: func(data []byte) []byte {
+				binary.LittleEndian.PutUint32(data[payloadHeaderLengthOffset:], 0xE0000000)
+				return data
+			},
+			check: expectCorrupt("header"),
+		},
+		{
+			// Regressão: arquivo pequeno declarando payload header de ~3.8 GB
+			// não pode alocar o tamanho declarado
+			name: "payload header gigante em arquivo pequeno",
+			mutate: func(data []byte) []byte {
+				data = data[:headerOffset+headerLength+50]
+				binary.LittleEndian.PutUint32(data[fileLengthOffset:], uint32(len(data)))
+				binary.LittleEndian.PutUint32(data[metadataLengthOffset:], 50)
+				binary.LittleEndian.PutUint32(data[payloadHeaderLengthOffset:], 0xE0000000)
+				return data
+			},
+			check: expectCorrupt("header"),
+		},
+		{
+			name: "JSON de metadados corrompido",
+			mutate: func(data []byte) []byte {
+				data[headerOffset+headerLength] = 'x'
+				return data
+			},
+			check: func(t *testing.T, err error) {
+				expectCorrupt("metadata")(t, err)
+				var syntax *json.SyntaxError
+				if !errors.As(err, &syntax) {
+					t.Errorf("erro %v não encapsula *json.SyntaxError", err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := tt.mutate(append([]byte(nil), valid...))
+			file, err := Parse(bytes.NewReader(data))
+			if err == nil {
+				t.Fatalf("Parse aceitou arquivo inválido: %+v", file.Header)
+			}
+			tt.check(t, err)
+		})
+	}
+}
+
+func expectIs(target error) func(t *testing.T, err error) {
+	return func(t *testing.T, err error) {
+		t.Helper()
+		if !errors.Is(err, target) {
+			t.Errorf("erro = %v, esperado %v", err, target)
+		}
+	}
+}
+
+func expectTruncated(section string) func(t *testing.T, err error) {
+	return func(t *testing.T, err error) {
+		t.Helper()
+		var truncated *TruncatedError
+		if !errors.As(err, &truncated) {
+			t.Fatalf("erro = %v (%T), esperado *TruncatedError", err, err)
+		}
+		if truncated.Section != section {
+			t.Errorf("seção = %q, esperado %q", truncated.Section, section)
+		}
+	}
+}
+
+func expectCorrupt(section string) func(t *testing.T, err error) {
+	return func(t *testing.T, err error) {
+		t.Helper()
+		var corrupt *CorruptError
+		if !errors.As(err, &corrupt) {
+			t.Fatalf("erro = %v (%T), esperado *CorruptError", err, err)
+		}
+		if corrupt.Section != section {
+			t.Errorf("seção = %q, esperado %q", corrupt.Section, section)
+		}
+	}
+}
@@ -2,7 +2,10 @@ package routes
 
 import (
     "net/http"
+    "wardscore-api/internal/config"
     "wardscore-api/internal/controllers"
+    "wardscore-api/internal/middleware"
+    "wardscore-api/internal/models"
     "wardscore-api/internal/services"
     
     "github.com/gin-gonic/gin"
@@ -14,7 +17,7 @@ func SetupRoutes(r *gin.Engine) {
     r.Use(cors.New(cors.Config{
         AllowOrigins:     []string{"http://localhost:3000"},
         AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-        AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-User-ID"},
+        AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-API-Key"},
         ExposeHeaders:    []string{"Content-Length"},
         AllowCredentials: true,
     }))
@@ -28,6 +31,7 @@ func SetupRoutes(r *gin.Engine) {
             "message":   "WardScore API funcionando! 🚀",
             "version":   "1.0.0",
             "endpoints": gin.H{
+                "auth":     "/api/v1/auth/riot",
                 "users":    "/api/v1/users",
                 "replays":  "/api/v1/replays", 
                 "analysis": "/api/v1/analysis",
@@ -39,41 +43,172 @@ func SetupRoutes(r *gin.Engine) {
     userService := services.NewUserService()
     replayService := services.NewReplayService()
     analysisService := services.NewAnalysisService()
+    riotAuthService := services.NewRiotAuthService()
+    sessionService := services.NewSessionService()
+    adminService := services.NewAdminService()
+    apiKeyService := services.NewAPIKeyService()
+    jobService := services.NewJobService()
+    progressService := services.NewProgressService()
+    uploadService := services.NewUploadService(replayService)
+    importService := services.NewImportService(replayService, jobService)
+    webhookService := services.NewWebhookService()
+    teamService := services.NewTeamService()
+    baselineService := services.NewBaselineService()
+    insightService := services.NewInsightService()
+
+    // Limpeza periódica de uploads resumíveis abandonados
+    uploadService.StartGarbageCollector(config.AppConfig.UploadGCInterval)
 
     // Inicializar controllers
     userController := controllers.NewUserController(userService)
-    replayController := controllers.NewReplayController(replayService)
-    analysisController := controllers.NewAnalysisController(analysisService)
+    replayController := controllers.NewReplayController(replayService, importService, jobService, progressService)
+    analysisController := controllers.NewAnalysisController(analysisService, replayService, jobService)
+    authController := controllers.NewAuthController(riotAuthService, userService, sessionService)
+    adminController := controllers.NewAdminController(adminService, userService, sessionService, analysisService, replayService, jobService)
+    apiKeyController := controllers.NewAPIKeyController(apiKeyService)
+    uploadController := controllers.NewUploadController(uploadService, jobService)
+    jobController := controllers.NewJobController(jobService)
+    webhookController := controllers.NewWebhookController(webhookService)
+    teamController := controllers.NewTeamController(teamService, userService)
+    scoringController := controllers.NewScoringController(baselineService, analysisService, jobService)
+    insightController := controllers.NewInsightController(insightService)
 
     // Grupo de rotas da API
     api := r.Group("/api/v1")
+
+    // ===== ROTAS PÚBLICAS =====
+    auth := api.Group("/auth")
     {
+        auth.GET("/riot", authController.RiotLogin)             // Iniciar login Riot (RSO)
+        auth.GET("/riot/callback", authController.RiotCallback) // Callback OAuth (cria/vincula usuário)
+        auth.POST("/refresh", authController.Refresh)           // Renovar tokens (rotação do refresh token)
+    }
+
+    // Demais rotas exigem access token válido ou API key
+    api.Use(middleware.AuthMiddleware(userService, sessionService, apiKeyService))
+
+    // Permissões (também verificadas contra os escopos quando a requisição usa API key)
+    canReadReplays := middleware.RequirePermission(models.PermReplaysRead)
+    canWriteReplays := middleware.RequirePermission(models.PermReplaysWrite)
+    canReadAnalysis := middleware.RequirePermission(models.PermAnalysisRead)
+    canWriteAnalysis := middleware.RequirePermission(models.PermAnalysisWrite)
+    {
+        // ===== ROTAS DE SESSÃO =====
+        session := api.Group("/auth", middleware.RequireSession())
+        {
+            session.POST("/riot/link", authController.RiotLink)            // Vincular conta Riot ao usuário autenticado
+            session.POST("/logout", authController.Logout)                 // Encerrar sessão atual
+            session.GET("/sessions", authController.GetSessions)           // Listar dispositivos logados
+            session.DELETE("/sessions", authController.RevokeOtherSessions) // Revogar outras sessões
+            session.DELETE("/sessions/:id", authController.RevokeSession)   // Revogar sessão específica
+        }
+
         // ===== ROTAS DE USUÁRIO =====
         users := api.Group("/users")
         {
-            users.POST("", userController.CreateUser)           // Criar usuário
-            users.GET("", userController.GetAllUsers)           // Listar usuários
             users.GET("/profile", userController.GetProfile)    // Perfil do usuário
-            users.PUT("/profile", userController.UpdateProfile) // Atualizar perfil
-            users.DELETE("/:id", userController.DeleteUser)     // Deletar usuário
+            users.PUT("/profile", middleware.RequireSession(), userController.UpdateProfile) // Atualizar perfil
+            users.DELETE("/:id", middleware.RequireSession(), middleware.RequireSelfOrAdmin("id"), userController.DeleteUser) // Deletar usuário (próprio ou admin)
+        }
+
+        // ===== ROTAS DE API KEYS =====
+        apiKeys := api.Group("/api-keys", middleware.RequireSession())
+        {
+            apiKeys.POST("", apiKeyController.CreateAPIKey)       // Criar API key
+            apiKeys.GET("", apiKeyController.GetAPIKeys)          // Listar API keys (com uso)
+            apiKeys.DELETE("/:id", apiKeyController.RevokeAPIKey) // Revogar API key
+        }
+
+        // ===== ROTAS DE TIMES =====
+        teams := api.Group("/teams", middleware.RequireSession())
+        {
+            teams.POST("", teamController.CreateTeam)                            // Criar time (usuário vira dono)
+            teams.GET("", teamController.GetTeams)                               // Times dos quais o usuário é membro
+            teams.GET("/:id", teamController.GetTeam)                            // Buscar time com membros
+            teams.DELETE("/:id", teamController.DeleteTeam)                      // Remover time (dono)
+            teams.POST("/:id/members", teamController.AddMember)                 // Adicionar membro pelo Riot ID (dono)
+            teams.DELETE("/:id/members/:user_id", teamController.RemoveMember)   // Remover membro (dono) ou sair do time
+        }
+
+        // ===== ROTAS DE WEBHOOKS =====
+        webhooks := api.Group("/webhooks", middleware.RequireSession())
+        {
+            webhooks.POST("", webhookController.CreateWebhook)                                    // Criar webhook
+            webhooks.GET("", webhookController.GetWebhooks)                                       // Listar webhooks
+            webhooks.GET("/:id", webhookController.GetWebhook)                                    // Buscar webhook
+            webhooks.PUT("/:id", webhookController.UpdateWebhook)                                 // Atualizar (URL, eventos, ativo)
+            webhooks.DELETE("/:id", webhookController.DeleteWebhook)                              // Remover webhook
+            webhooks.POST("/:id/rotate-secret", webhookController.RotateWebhookSecret)            // Gerar novo secret
+            webhooks.GET("/:id/deliveries", webhookController.GetDeliveries)                      // Log de entregas
+            webhooks.GET("/:id/deliveries/:delivery_id", webhookController.GetDelivery)           // Detalhes da entrega
+            webhooks.POST("/:id/deliveries/:delivery_id/redeliver", webhookController.Redeliver) // Reenviar entrega
         }
 
         // ===== ROTAS DE REPLAY =====
         replays := api.Group("/replays")
         {
-            replays.POST("/upload", replayController.UploadReplay)  // Upload replay
-            replays.GET("", replayController.GetReplays)            // Listar replays
-            replays.GET("/:id", replayController.GetReplay)         // Buscar replay específico
-            replays.PUT("/:id", replayController.UpdateReplay)      // Atualizar replay
-            replays.DELETE("/:id", replayController.DeleteReplay)   // Deletar replay
+            replays.POST("/upload", canWriteReplays, replayController.UploadReplay)  // Upload replay
+            replays.POST("/import", canWriteReplays, replayController.ImportReplays) // Importar ZIP com vários replays
+
+            // Upload resumível em chunks
+            replays.POST("/uploads", canWriteReplays, uploadController.CreateUpload)                      // Criar sessão de upload
+            replays.GET("/uploads/:upload_id", canWriteReplays, uploadController.GetUpload)               // Chunks/intervalos recebidos
+            replays.PUT("/uploads/:upload_id/chunks/:index", canWriteReplays, uploadController.PutChunk)  // Enviar chunk
+            replays.POST("/uploads/:upload_id/complete", canWriteReplays, uploadController.CompleteUpload) // Finalizar (cria o replay)
+            replays.DELETE("/uploads/:upload_id", canWriteReplays, uploadController.AbortUpload)          // Cancelar upload
+
+            replays.GET("", canReadReplays, replayController.GetReplays)            // Listar replays
+            replays.GET("/:id", canReadReplays, replayController.GetReplay)         // Buscar replay específico
+            replays.GET("/:id/file", canReadReplays, replayController.DownloadReplay) // Baixar arquivo .rofl
+            replays.GET("/:id/status", canReadReplays, replayController.GetReplayStatus)   // Status do processamento (polling)
+            replays.GET("/:id/events", canReadReplays, replayController.StreamReplayEvents) // Status do processamento (SSE)
+            replays.PUT("/:id", canWriteReplays, replayController.UpdateReplay)      // Atualizar replay
+            replays.PUT("/:id/timeline", canWriteReplays, analysisController.PutReplayTimeline) // Enviar timeline de wards (reprocessa)
+            replays.DELETE("/:id", canWriteReplays, replayController.DeleteReplay)   // Deletar replay
         }
 
+        // ===== ROTAS DE JOBS =====
+        jobs := api.Group("/jobs")
+        {
+            jobs.GET("/:id", canReadReplays, jobController.GetJob) // Status do processamento
+        }
+
+        // ===== ROTAS DE SCORE =====
+        api.GET("/scoring/versions", canReadAnalysis, scoringController.GetVersions) // Versões do modelo de score
+        api.GET("/map/zones", canReadAnalysis, analysisController.GetZoneCatalog)     // Zonas do mapa (catálogo versionado)
+
         // ===== ROTAS DE ANÁLISE =====
         analysis := api.Group("/analysis")
         {
-            analysis.GET("/:id", analysisController.GetAnalysis)           // Buscar análise
-            analysis.POST("/process/:replay_id", analysisController.ProcessReplay) // Processar replay
-            analysis.GET("/user/:user_id", analysisController.GetUserAnalyses)     // Análises do usuário
+            analysis.GET("/:id", canReadAnalysis, analysisController.GetAnalysis)           // Buscar análise
+            analysis.GET("/:id/breakdown", canReadAnalysis, analysisController.GetAnalysisBreakdown)  // Detalhamento do WardScore por fator
+            analysis.GET("/:id/heatmap", canReadAnalysis, analysisController.GetAnalysisHeatmap)      // Heatmap das wards (json, svg ou png)
+            analysis.GET("/replay/:replay_id", canReadAnalysis, analysisController.GetReplayAnalysis) // Análise do replay (versão mais recente ou ?version=)
+            analysis.POST("/process/:replay_id", canWriteAnalysis, analysisController.ProcessReplay) // Processar replay
+            analysis.GET("/user/:user_id", canReadAnalysis, middleware.RequireSelfOrAdmin("user_id"), analysisController.GetUserAnalyses) // Análises do usuário (próprio ou admin)
+            analysis.GET("/user/:user_id/heatmap", canReadAnalysis, middleware.RequireSelfOrAdmin("user_id"), analysisController.GetUserHeatmap) // Heatmap de todas as partidas do usuário
+        }
+
+        // ===== ROTAS ADMINISTRATIVAS =====
+        admin := api.Group("/admin")
+        {
+            admin.GET("/users", middleware.RequirePermission(models.PermUsersRead), userController.GetAllUsers)            // Listar usuários
+            admin.POST("/users/:id/ban", middleware.RequirePermission(models.PermUsersManage), adminController.BanUser)     // Banir usuário
+            admin.DELETE("/users/:id/ban", middleware.RequirePermission(models.PermUsersManage), adminController.UnbanUser) // Remover banimento
+            admin.PUT("/users/:id/role", middleware.RequirePermission(models.PermUsersManage), adminController.SetUserRole) // Alterar papel
+            admin.POST("/replays/:id/reprocess", middleware.RequirePermission(models.PermReplaysReprocess), adminController.ReprocessReplay) // Forçar reprocessamento
+            admin.GET("/dead-letter", middleware.RequirePermission(models.PermReplaysReprocess), adminController.GetDeadLetters)                  // Replays com tentativas esgotadas
+            admin.GET("/dead-letter/:id", middleware.RequirePermission(models.PermReplaysReprocess), adminController.GetDeadLetter)               // Detalhes (tentativas, último erro)
+            admin.POST("/dead-letter/:id/requeue", middleware.RequirePermission(models.PermReplaysReprocess), adminController.RequeueDeadLetter)  // Reenfileirar
+            admin.POST("/scoring/reprocess", middleware.RequirePermission(models.PermScoringManage), scoringController.ReprocessAnalyses)               // Recalcular análises na versão atual do score
+            admin.GET("/scoring/baselines", middleware.RequirePermission(models.PermScoringManage), scoringController.GetBaselines)                   // Baselines do WardScore por role/fila
+            admin.PUT("/scoring/baselines", middleware.RequirePermission(models.PermScoringManage), scoringController.SetBaseline)                    // Definir metas de uma role/fila
+            admin.DELETE("/scoring/baselines/:id", middleware.RequirePermission(models.PermScoringManage), scoringController.DeleteBaseline)          // Remover baseline salvo
+            admin.POST("/scoring/baselines/recompute", middleware.RequirePermission(models.PermScoringManage), scoringController.RecomputeBaselines) // Recalcular a partir das análises
+            admin.GET("/insights/rules", middleware.RequirePermission(models.PermScoringManage), insightController.GetRules)            // Catálogo de regras de insight em uso
+            admin.PUT("/insights/rules/:key", middleware.RequirePermission(models.PermScoringManage), insightController.SetRule)       // Criar/substituir regra
+            admin.DELETE("/insights/rules/:key", middleware.RequirePermission(models.PermScoringManage), insightController.DeleteRule) // Remover regra salva
+            admin.GET("/stats", middleware.RequirePermission(models.PermStatsRead), adminController.GetStats)              // Estatísticas do sistema
         }
 
         // ===== ROTAS DE ESTATÍSTICAS =====
@@ -0,0 +1,238 @@
+package scoring
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// Roles normalizadas (Replay.Role)
+const (
+	RoleTop     = "TOP"
+	RoleJungle  = "JUNGLE"
+	RoleMiddle  = "MIDDLE"
+	RoleBottom  = "BOTTOM"
+	RoleSupport = "SUPPORT"
+)
+
+// Filas normalizadas (Replay.Queue)
+const (
+	QueueRankedSolo = "RANKED_SOLO"
+	QueueRankedFlex = "RANKED_FLEX"
+	QueueNormal     = "NORMAL"
+	QueueARAM       = "ARAM"
+)
+
+// Origem de um baseline
+const (
+	SourceDefault  = "default"  // tabela embutida no pacote
+	SourceCustom   = "custom"   // definido por um admin
+	SourceComputed = "computed" // recalculado a partir das análises salvas
+)
+
+// Baseline são as metas dos fatores para uma role e fila. Fatores ausentes
+// em Targets usam a meta padrão (Factors).
+type Baseline struct {
+	Role       string             `json:"role,omitempty"`
+	Queue      string             `json:"queue,omitempty"`
+	Source     string             `json:"source"`
+	SampleSize int                `json:"sample_size,omitempty"`
+	Targets    map[string]float64 `json:"targets"`
+}
+
+// Target retorna a meta do fator neste baseline
+func (b Baseline) Target(factor Factor) float64 {
+	if target, ok := b.Targets[factor.Name]; ok && target > 0 {
+		return target
+	}
+	return factor.Target
+}
+
+// defaultRoleTargets são as metas por role quando não há baseline configurado
+// ou calculado. Supports vivem de visão; carries colocam bem menos wards.
+var defaultRoleTargets = map[string]map[string]float64{
+	RoleTop: {
+		FactorVisionScore: 0.9, FactorWardsPlaced: 0.45, FactorWardsDestroyed: 0.15,
+		FactorControlWards: 0.07, FactorVisionControlRatio: 0.35, FactorObjectiveSetup: 0.4,
+	},
+	RoleJungle: {
+		FactorVisionScore: 1.4, FactorWardsPlaced: 0.55, FactorWardsDestroyed: 0.3,
+		FactorControlWards: 0.12, FactorVisionControlRatio: 0.55, FactorObjectiveSetup: 0.7,
+	},
+	RoleMiddle: {
+		FactorVisionScore: 1.0, FactorWardsPlaced: 0.45, FactorWardsDestroyed: 0.2,
+		FactorControlWards: 0.08, FactorVisionControlRatio: 0.45, FactorObjectiveSetup: 0.5,
+	},
+	RoleBottom: {
+		FactorVisionScore: 0.8, FactorWardsPlaced: 0.4, FactorWardsDestroyed: 0.15,
+		FactorControlWards: 0.06, FactorVisionControlRatio: 0.35, FactorObjectiveSetup: 0.35,
+	},
+	RoleSupport: {
+		FactorVisionScore: 2.8, FactorWardsPlaced: 1.2, FactorWardsDestroyed: 0.45,
+		FactorControlWards: 0.25, FactorVisionControlRatio: 0.4, FactorObjectiveSetup: 0.8,
+	},
+}
+
+// aramTargets são as metas do ARAM, iguais para todas as roles: no Howling
+// Abyss não há rotas nem objetivos e quase não se coloca ward
+var aramTargets = map[string]float64{
+	FactorVisionScore: 0.35, FactorWardsPlaced: 0.1, FactorWardsDestroyed: 0.05,
+	FactorControlWards: 0.02, FactorVisionControlRatio: 0.3, FactorObjectiveSetup: 0.3,
+}
+
+// defaultQueueMultipliers ajustam as metas de taxa por minuto da tabela
+// embutida à fila. A ranqueada solo é a referência; filas sem multiplicador
+// (ou desconhecidas) usam as metas da role sem ajuste.
+var defaultQueueMultipliers = map[string]float64{
+	QueueRankedFlex: 0.95,
+	QueueNormal:     0.85,
+}
+
+// rateFactors são os fatores medidos por minuto, ajustados pela fila. Razões
+// e frações (vision_control_ratio, objective_setup) não dependem do ritmo.
+var rateFactors = map[string]bool{
+	FactorVisionScore:    true,
+	FactorWardsPlaced:    true,
+	FactorWardsDestroyed: true,
+	FactorControlWards:   true,
+}
+
+// DefaultBaseline é o baseline embutido da role e fila. Sem role, parte das
+// metas de Factors; o ARAM tem metas próprias e as demais filas aplicam
+// defaultQueueMultipliers às metas da role.
+func DefaultBaseline(role, queue string) Baseline {
+	role = NormalizeRole(role)
+	queue = NormalizeQueue(queue)
+	baseline := Baseline{Role: role, Queue: queue, Source: SourceDefault, Targets: map[string]float64{}}
+
+	if queue == QueueARAM {
+		for name, target := range aramTargets {
+			baseline.Targets[name] = target
+		}
+		return baseline
+	}
+
+	targets, ok := defaultRoleTargets[role]
+	if !ok {
+		baseline.Role = ""
+		targets = map[string]float64{}
+		for _, factor := range Factors {
+			targets[factor.Name] = factor.Target
+		}
+	}
+
+	multiplier, ok := defaultQueueMultipliers[queue]
+	if !ok {
+		multiplier = 1
+	}
+	for name, target := range targets {
+		if rateFactors[name] {
+			target = round(target*multiplier, 3)
+		}
+		baseline.Targets[name] = target
+	}
+	return baseline
+}
+
+// NormalizeRole converte a posição para uma das roles conhecidas ("" se desconhecida)
+func NormalizeRole(role string) string {
+	switch strings.ToUpper(strings.TrimSpace(role)) {
+	case "TOP":
+		return RoleTop
+	case "JUNGLE", "JG":
+		return RoleJungle
+	case "MIDDLE", "MID":
+		return RoleMiddle
+	case "BOTTOM", "BOT", "ADC", "CARRY":
+		return RoleBottom
+	case "SUPPORT", "UTILITY", "SUP":
+		return RoleSupport
+	default:
+		return ""
+	}
+}
+
+// NormalizeQueue converte a fila (nome ou queueId da Riot) para uma das filas
+// conhecidas ("" se desconhecida)
+func NormalizeQueue(queue string) string {
+	switch strings.ToUpper(strings.TrimSpace(queue)) {
+	case "420", "RANKED_SOLO", "RANKED_SOLO_5X5", "SOLO", "SOLOQ", "SOLO/DUO":
+		return QueueRankedSolo
+	case "440", "RANKED_FLEX", "RANKED_FLEX_SR", "FLEX":
+		return QueueRankedFlex
+	case "400", "430", "490", "NORMAL", "NORMAL_DRAFT", "NORMAL_BLIND", "QUICKPLAY", "DRAFT", "BLIND":
+		return QueueNormal
+	case "450", "ARAM":
+		return QueueARAM
+	default:
+		return ""
+	}
+}
+
+// IsKnownFactor verifica se o nome é de um fator do WardScore
+func IsKnownFactor(name string) bool {
+	for _, factor := range Factors {
+		if factor.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// FactorValues calcula o valor de cada fator (taxas por minuto, razão de
+// controle e setup de objetivos) das estatísticas informadas. Fatores sem
+// dado na partida ficam fora do mapa.
+func FactorValues(in Input) map[string]float64 {
+	minutes := math.Max(float64(in.DurationSeconds)/60.0, MinGameMinutes)
+
+	values := map[string]float64{
+		FactorVisionScore:        float64(in.VisionScore) / minutes,
+		FactorWardsPlaced:        float64(in.WardsPlaced) / minutes,
+		FactorWardsDestroyed:     float64(in.WardsDestroyed) / minutes,
+		FactorControlWards:       float64(in.ControlWardsPlaced) / minutes,
+		FactorVisionControlRatio: visionControlRatio(in.WardsPlaced, in.WardsDestroyed),
+	}
+	if in.ObjectiveSetup != nil {
+		values[FactorObjectiveSetup] = *in.ObjectiveSetup
+	}
+	return values
+}
+
+// ComputeTargets calcula as metas de um grupo de partidas: a meta de cada
+// fator é o percentil informado (0 a 100) dos valores do grupo, ou seja,
+// quem fica nesse percentil ou acima recebe nota máxima no fator
+func ComputeTargets(samples []Input, percentile float64) map[string]float64 {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	values := map[string][]float64{}
+	for _, sample := range samples {
+		for name, value := range FactorValues(sample) {
+			values[name] = append(values[name], value)
+		}
+	}
+
+	targets := map[string]float64{}
+	for _, factor := range Factors {
+		target := percentileOf(values[factor.Name], percentile)
+		if target > 0 {
+			targets[factor.Name] = round(target, 3)
+		}
+	}
+	return targets
+}
+
+// percentileOf usa interpolação linear entre os valores ordenados
+func percentileOf(values []float64, percentile float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	rank := math.Max(0, math.Min(percentile, 100)) / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
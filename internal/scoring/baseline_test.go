@@ -0,0 +1,68 @@
+package scoring
+
+import "testing"
+
+func TestDefaultBaseline(t *testing.T) {
+	support := defaultRoleTargets[RoleSupport]
+
+	tests := []struct {
+		name      string
+		role      string
+		queue     string
+		wantRole  string
+		wantQueue string
+		want      map[string]float64
+	}{
+		{"support sem fila", RoleSupport, "", RoleSupport, "", support},
+		{"support na solo", "utility", "420", RoleSupport, QueueRankedSolo, support},
+		{"support na flex", RoleSupport, "RANKED_FLEX", RoleSupport, QueueRankedFlex, map[string]float64{
+			FactorVisionScore: 2.66, FactorWardsPlaced: 1.14, FactorWardsDestroyed: 0.428,
+			FactorControlWards: 0.238, FactorVisionControlRatio: 0.4, FactorObjectiveSetup: 0.8,
+		}},
+		{"support na normal", RoleSupport, "400", RoleSupport, QueueNormal, map[string]float64{
+			FactorVisionScore: 2.38, FactorWardsPlaced: 1.02, FactorWardsDestroyed: 0.383,
+			FactorControlWards: 0.213, FactorVisionControlRatio: 0.4, FactorObjectiveSetup: 0.8,
+		}},
+		{"support no ARAM", RoleSupport, "450", RoleSupport, QueueARAM, aramTargets},
+		{"jungle no ARAM", RoleJungle, QueueARAM, RoleJungle, QueueARAM, aramTargets},
+		{"sem role nem fila", "", "", "", "", map[string]float64{
+			FactorVisionScore: 2.0, FactorWardsPlaced: 1.0, FactorWardsDestroyed: 0.4,
+			FactorControlWards: 0.15, FactorVisionControlRatio: 0.5, FactorObjectiveSetup: 0.6,
+		}},
+		{"role desconhecida na normal", "coach", "NORMAL", "", QueueNormal, map[string]float64{
+			FactorVisionScore: 1.7, FactorWardsPlaced: 0.85, FactorWardsDestroyed: 0.34,
+			FactorControlWards: 0.128, FactorVisionControlRatio: 0.5, FactorObjectiveSetup: 0.6,
+		}},
+		{"fila desconhecida", RoleTop, "URF", RoleTop, "", defaultRoleTargets[RoleTop]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseline := DefaultBaseline(tt.role, tt.queue)
+			if baseline.Role != tt.wantRole || baseline.Queue != tt.wantQueue || baseline.Source != SourceDefault {
+				t.Errorf("baseline = %s/%s/%s, esperado %s/%s/%s", baseline.Role, baseline.Queue, baseline.Source, tt.wantRole, tt.wantQueue, SourceDefault)
+			}
+			if len(baseline.Targets) != len(tt.want) {
+				t.Errorf("metas = %v, esperado %v", baseline.Targets, tt.want)
+			}
+			for name, want := range tt.want {
+				if got := baseline.Targets[name]; got != want {
+					t.Errorf("meta de %s = %v, esperado %v", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+// As metas embutidas são copiadas: alterar um baseline não muda a tabela
+func TestDefaultBaselineCopiesTargets(t *testing.T) {
+	DefaultBaseline(RoleSupport, "").Targets[FactorVisionScore] = 99
+	DefaultBaseline("", QueueARAM).Targets[FactorVisionScore] = 99
+
+	if got := DefaultBaseline(RoleSupport, "").Targets[FactorVisionScore]; got != 2.8 {
+		t.Errorf("meta de support alterada para %v", got)
+	}
+	if got := DefaultBaseline("", QueueARAM).Targets[FactorVisionScore]; got != 0.35 {
+		t.Errorf("meta do ARAM alterada para %v", got)
+	}
+}
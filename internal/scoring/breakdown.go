@@ -0,0 +1,51 @@
+package scoring
+
+// Breakdown explica o WardScore de uma análise: a contribuição de cada fator,
+// o baseline usado e as faixas de rank
+type Breakdown struct {
+	Version    int             `json:"version"`
+	WardScore  float64         `json:"ward_score"`
+	Rank       string          `json:"rank"`
+	MaxScore   float64         `json:"max_score"`
+	LostPoints float64         `json:"lost_points"`
+	Baseline   Baseline        `json:"baseline"`
+	Components []Component     `json:"components"`
+	Ranks      []RankThreshold `json:"ranks"`
+	NextRank   *NextRank       `json:"next_rank,omitempty"`
+}
+
+// NextRank é o próximo rank acima do atual e quantos pontos faltam para ele
+type NextRank struct {
+	Rank         string  `json:"rank"`
+	MinScore     float64 `json:"min_score"`
+	PointsNeeded float64 `json:"points_needed"`
+}
+
+// NewBreakdown monta o detalhamento do resultado do cálculo
+func NewBreakdown(result Result) Breakdown {
+	breakdown := Breakdown{
+		Version:    result.Version,
+		WardScore:  result.WardScore,
+		Rank:       result.Rank,
+		MaxScore:   100,
+		LostPoints: round(100-result.WardScore, 1),
+		Baseline:   result.Baseline,
+		Components: result.Components,
+		Ranks:      RankThresholds,
+	}
+
+	// RankThresholds vai do maior para o menor: o próximo rank é o último
+	// com score mínimo acima do atual
+	for _, threshold := range RankThresholds {
+		if threshold.MinScore <= result.WardScore {
+			break
+		}
+		breakdown.NextRank = &NextRank{
+			Rank:         threshold.Rank,
+			MinScore:     threshold.MinScore,
+			PointsNeeded: round(threshold.MinScore-result.WardScore, 1),
+		}
+	}
+
+	return breakdown
+}
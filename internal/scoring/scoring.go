@@ -0,0 +1,213 @@
+// Package scoring calcula o WardScore de um jogador a partir das estatísticas
+// de visão da partida. O cálculo é determinístico: as mesmas entradas sempre
+// produzem o mesmo score.
+//
+// Cada fator é convertido em taxa por minuto (exceto a razão de controle),
+// comparado com a meta do fator no baseline da role/fila e limitado a 1:
+//
+//	nota = min(valor / meta, 1)
+//
+// O WardScore é a soma ponderada das notas, em escala de 0 a 100:
+//
+//	WardScore = 100 × Σ(peso × nota)
+//
+//	fator                 valor                               meta*  peso
+//	vision_score          vision score por minuto             2.0    0.35
+//	wards_placed          wards colocadas por minuto          1.0    0.17
+//	wards_destroyed       wards destruídas por minuto         0.4    0.13
+//	control_wards         control wards por minuto            0.15   0.08
+//	vision_control_ratio  wards destruídas / colocadas        0.5    0.12
+//	objective_setup       objetivos com ward antes (fração)   0.6    0.15
+//
+// objective_setup depende da timeline da partida (pacote objectives). Sem
+// ela o fator fica de fora e o peso dos demais é redistribuído
+// proporcionalmente, mantendo a escala de 0 a 100.
+//
+// * metas sem role conhecida. Cada role tem suas metas (DefaultBaseline), que
+// podem ser substituídas por baselines configurados ou recalculados a partir
+// das análises salvas (ComputeTargets).
+//
+// Partidas com menos de MinGameMinutes usam MinGameMinutes como duração, para
+// que remakes e jogos curtíssimos não gerem taxas infladas.
+package scoring
+
+import "math"
+
+// MinGameMinutes é a duração mínima considerada no cálculo das taxas
+const MinGameMinutes = 10.0
+
+// Nomes dos fatores do WardScore
+const (
+	FactorVisionScore        = "vision_score"
+	FactorWardsPlaced        = "wards_placed"
+	FactorWardsDestroyed     = "wards_destroyed"
+	FactorControlWards       = "control_wards"
+	FactorVisionControlRatio = "vision_control_ratio"
+	FactorObjectiveSetup     = "objective_setup"
+)
+
+// Factor é a meta e o peso de um fator do WardScore
+type Factor struct {
+	Name   string
+	Target float64
+	Weight float64
+}
+
+// Factors são os fatores do WardScore com as metas sem role; os pesos somam 1
+var Factors = []Factor{
+	{Name: FactorVisionScore, Target: 2.0, Weight: 0.35},
+	{Name: FactorWardsPlaced, Target: 1.0, Weight: 0.17},
+	{Name: FactorWardsDestroyed, Target: 0.4, Weight: 0.13},
+	{Name: FactorControlWards, Target: 0.15, Weight: 0.08},
+	{Name: FactorVisionControlRatio, Target: 0.5, Weight: 0.12},
+	{Name: FactorObjectiveSetup, Target: 0.6, Weight: 0.15},
+}
+
+// Input são as estatísticas do jogador usadas no cálculo
+type Input struct {
+	DurationSeconds    int
+	VisionScore        int
+	WardsPlaced        int
+	WardsDestroyed     int
+	ControlWardsPlaced int
+	// ObjectiveSetup é a fração dos objetivos com ward do jogador em volta
+	// do covil antes do evento; nil quando a partida não tem timeline
+	ObjectiveSetup *float64
+}
+
+// Component é o resultado de um fator: valor bruto da partida, valor
+// normalizado comparado com a meta, peso efetivo (após redistribuir os
+// fatores sem dado), nota (0 a 1) e pontos somados ao WardScore (de
+// MaxPoints possíveis)
+type Component struct {
+	Name       string  `json:"name"`
+	Raw        float64 `json:"raw"`
+	Value      float64 `json:"value"`
+	Target     float64 `json:"target"`
+	Weight     float64 `json:"weight"`
+	Rating     float64 `json:"rating"`
+	Points     float64 `json:"points"`
+	MaxPoints  float64 `json:"max_points"`
+	LostPoints float64 `json:"lost_points"`
+}
+
+// Result é o WardScore com as métricas derivadas e a contribuição de cada fator
+type Result struct {
+	Version            int         `json:"version"`
+	WardScore          float64     `json:"ward_score"`
+	Rank               string      `json:"rank"`
+	WardsPerMinute     float64     `json:"wards_per_minute"`
+	VisionControlRatio float64     `json:"vision_control_ratio"`
+	Baseline           Baseline    `json:"baseline"`
+	Components         []Component `json:"components"`
+}
+
+// Score calcula o WardScore das estatísticas informadas contra o baseline
+func Score(in Input, baseline Baseline) Result {
+	values := FactorValues(in)
+	raw := map[string]float64{
+		FactorVisionScore:        float64(in.VisionScore),
+		FactorWardsPlaced:        float64(in.WardsPlaced),
+		FactorWardsDestroyed:     float64(in.WardsDestroyed),
+		FactorControlWards:       float64(in.ControlWardsPlaced),
+		FactorVisionControlRatio: values[FactorVisionControlRatio],
+		FactorObjectiveSetup:     values[FactorObjectiveSetup],
+	}
+
+	result := Result{
+		WardsPerMinute:     values[FactorWardsPlaced],
+		VisionControlRatio: values[FactorVisionControlRatio],
+		Baseline:           baseline,
+		Version:            Version,
+	}
+
+	// Fatores sem dado na partida ficam de fora; os pesos dos demais são
+	// redistribuídos para somar 1
+	var weightSum float64
+	for _, factor := range Factors {
+		if _, ok := values[factor.Name]; ok {
+			weightSum += factor.Weight
+		}
+	}
+
+	var total float64
+	for _, factor := range Factors {
+		value, ok := values[factor.Name]
+		if !ok {
+			continue
+		}
+		weight := factor.Weight / weightSum
+		target := baseline.Target(factor)
+		rating := rate(value, target)
+		maxPoints := 100 * weight
+		points := maxPoints * rating
+		total += points
+
+		result.Components = append(result.Components, Component{
+			Name:       factor.Name,
+			Raw:        round(raw[factor.Name], 3),
+			Value:      round(value, 3),
+			Target:     target,
+			Weight:     round(weight, 4),
+			Rating:     round(rating, 3),
+			Points:     round(points, 2),
+			MaxPoints:  round(maxPoints, 2),
+			LostPoints: round(maxPoints-points, 2),
+		})
+	}
+
+	result.WardScore = round(total, 1)
+	result.Rank = Rank(result.WardScore)
+	return result
+}
+
+// RankThreshold é o score mínimo de um rank
+type RankThreshold struct {
+	Rank     string  `json:"rank"`
+	MinScore float64 `json:"min_score"`
+}
+
+// RankThresholds são os ranks do WardScore, do maior para o menor
+var RankThresholds = []RankThreshold{
+	{Rank: "S+", MinScore: 95},
+	{Rank: "S", MinScore: 90},
+	{Rank: "A+", MinScore: 85},
+	{Rank: "A", MinScore: 80},
+	{Rank: "B+", MinScore: 70},
+	{Rank: "B", MinScore: 60},
+	{Rank: "C", MinScore: 0},
+}
+
+// Rank converte o WardScore no rank exibido ao jogador
+func Rank(score float64) string {
+	for _, threshold := range RankThresholds {
+		if score >= threshold.MinScore {
+			return threshold.Rank
+		}
+	}
+	return RankThresholds[len(RankThresholds)-1].Rank
+}
+
+// visionControlRatio é a razão wards destruídas / colocadas. Sem wards
+// colocadas, destruir qualquer ward conta como razão 1.
+func visionControlRatio(placed, destroyed int) float64 {
+	if placed > 0 {
+		return float64(destroyed) / float64(placed)
+	}
+	if destroyed > 0 {
+		return 1
+	}
+	return 0
+}
+
+func rate(value, target float64) float64 {
+	if target <= 0 || value <= 0 {
+		return 0
+	}
+	return math.Min(value/target, 1)
+}
+
+func round(value float64, decimals int) float64 {
+	pow := math.Pow(10, float64(decimals))
+	return math.Round(value*pow) / pow
+}
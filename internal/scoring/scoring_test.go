@@ -0,0 +1,216 @@
+package scoring
+
+import (
+	"math"
+	"testing"
+)
+
+func ptr(value float64) *float64 {
+	return &value
+}
+
+func TestScore(t *testing.T) {
+	// 30 minutos: 1.4 de vision score, 0.6 wards, 0.2 destruídas e 0.1
+	// control wards por minuto; razão de controle 1/3
+	average := Input{DurationSeconds: 1800, VisionScore: 42, WardsPlaced: 18, WardsDestroyed: 6, ControlWardsPlaced: 3}
+	// Em 10 minutos: metas sem role em tudo, exceto control wards (0.1) e
+	// razão de controle (0.4)
+	short := Input{VisionScore: 20, WardsPlaced: 10, WardsDestroyed: 4, ControlWardsPlaced: 1}
+	perfect := Input{DurationSeconds: 1500, VisionScore: 200, WardsPlaced: 80, WardsDestroyed: 40, ControlWardsPlaced: 20, ObjectiveSetup: ptr(1)}
+
+	withDuration := func(in Input, seconds int) Input {
+		in.DurationSeconds = seconds
+		return in
+	}
+
+	tests := []struct {
+		name     string
+		in       Input
+		role     string
+		want     float64
+		wantRank string
+	}{
+		{"duração zero usa MinGameMinutes", withDuration(short, 0), "", 94.0, "S"},
+		{"partida curta usa MinGameMinutes", withDuration(short, 300), "", 94.0, "S"},
+		{"partida de MinGameMinutes", withDuration(short, 600), "", 94.0, "S"},
+		{"partida perfeita limitada a 100", perfect, "", 100, "S+"},
+		{"partida perfeita limitada a 100 como support", perfect, RoleSupport, 100, "S+"},
+		{"partida zerada", Input{DurationSeconds: 1800}, "", 0, "C"},
+		{"partida zerada sem duração", Input{}, "", 0, "C"},
+		{"sem role usa as metas de Factors", average, "", 64.2, "B"},
+		{"metas de support", average, RoleSupport, 52.9, "C"},
+		{"metas de top", average, RoleTop, 99.3, "S+"},
+		{"role em outro formato", average, "utility", 52.9, "C"},
+		{"destruir wards sem colocar nenhuma", Input{DurationSeconds: 1800, WardsDestroyed: 3}, "", 17.9, "C"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Score(tt.in, DefaultBaseline(tt.role, ""))
+			if result.WardScore != tt.want {
+				t.Errorf("WardScore = %v, esperado %v", result.WardScore, tt.want)
+			}
+			if result.Rank != tt.wantRank {
+				t.Errorf("Rank = %q, esperado %q", result.Rank, tt.wantRank)
+			}
+			if result.Version != Version {
+				t.Errorf("Version = %d, esperado %d", result.Version, Version)
+			}
+			if result.WardScore < 0 || result.WardScore > 100 {
+				t.Errorf("WardScore %v fora de 0 a 100", result.WardScore)
+			}
+		})
+	}
+}
+
+func TestScoreDeterministic(t *testing.T) {
+	in := Input{DurationSeconds: 1634, VisionScore: 57, WardsPlaced: 23, WardsDestroyed: 7, ControlWardsPlaced: 4, ObjectiveSetup: ptr(0.5)}
+	first := Score(in, DefaultBaseline(RoleJungle, ""))
+	for i := 0; i < 10; i++ {
+		if again := Score(in, DefaultBaseline(RoleJungle, "")); again.WardScore != first.WardScore {
+			t.Fatalf("WardScore mudou entre execuções: %v e %v", first.WardScore, again.WardScore)
+		}
+	}
+}
+
+// Análises sem timeline (sem objective_setup) continuam na mesma escala das
+// que têm o fator: os pesos dos demais fatores são redistribuídos, então um
+// objective_setup com a mesma nota média dos outros fatores não muda o score
+func TestScoreWithoutObjectiveSetup(t *testing.T) {
+	tests := []struct {
+		name string
+		in   Input
+		role string
+	}{
+		{"todas as metas atingidas", Input{DurationSeconds: 1200, VisionScore: 40, WardsPlaced: 20, WardsDestroyed: 8, ControlWardsPlaced: 3}, ""},
+		{"partida média", Input{DurationSeconds: 1800, VisionScore: 42, WardsPlaced: 18, WardsDestroyed: 6, ControlWardsPlaced: 3}, ""},
+		{"partida média de jungle", Input{DurationSeconds: 1800, VisionScore: 42, WardsPlaced: 18, WardsDestroyed: 6, ControlWardsPlaced: 3}, RoleJungle},
+		{"partida zerada", Input{DurationSeconds: 1200}, RoleSupport},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseline := DefaultBaseline(tt.role, "")
+			without := Score(tt.in, baseline)
+
+			var weights, maxPoints float64
+			for _, component := range without.Components {
+				if component.Name == FactorObjectiveSetup {
+					t.Fatalf("objective_setup presente sem dado na partida")
+				}
+				weights += component.Weight
+				maxPoints += component.MaxPoints
+			}
+			if math.Abs(weights-1) > 0.001 || math.Abs(maxPoints-100) > 0.05 {
+				t.Errorf("pesos somam %v e pontos máximos %v, esperado 1 e 100", weights, maxPoints)
+			}
+
+			with := tt.in
+			with.ObjectiveSetup = ptr(without.WardScore / 100 * baseline.Target(Factors[len(Factors)-1]))
+			withSetup := Score(with, baseline)
+			if math.Abs(withSetup.WardScore-without.WardScore) > 0.1 {
+				t.Errorf("WardScore com objective_setup na mesma nota = %v, sem o fator = %v", withSetup.WardScore, without.WardScore)
+			}
+			if len(withSetup.Components) != len(Factors) {
+				t.Errorf("componentes com objective_setup = %d, esperado %d", len(withSetup.Components), len(Factors))
+			}
+		})
+	}
+}
+
+func TestFactorValues(t *testing.T) {
+	tests := []struct {
+		name string
+		in   Input
+		want map[string]float64
+	}{
+		{
+			name: "taxas por minuto",
+			in:   Input{DurationSeconds: 1800, VisionScore: 60, WardsPlaced: 30, WardsDestroyed: 9, ControlWardsPlaced: 6},
+			want: map[string]float64{
+				FactorVisionScore: 2, FactorWardsPlaced: 1, FactorWardsDestroyed: 0.3,
+				FactorControlWards: 0.2, FactorVisionControlRatio: 0.3,
+			},
+		},
+		{
+			name: "duração abaixo do mínimo",
+			in:   Input{DurationSeconds: 120, VisionScore: 5, WardsPlaced: 2, WardsDestroyed: 1, ControlWardsPlaced: 1},
+			want: map[string]float64{
+				FactorVisionScore: 0.5, FactorWardsPlaced: 0.2, FactorWardsDestroyed: 0.1,
+				FactorControlWards: 0.1, FactorVisionControlRatio: 0.5,
+			},
+		},
+		{
+			name: "wards destruídas sem wards colocadas",
+			in:   Input{DurationSeconds: 1200, WardsDestroyed: 4},
+			want: map[string]float64{
+				FactorVisionScore: 0, FactorWardsPlaced: 0, FactorWardsDestroyed: 0.2,
+				FactorControlWards: 0, FactorVisionControlRatio: 1,
+			},
+		},
+		{
+			name: "nenhuma ward",
+			in:   Input{DurationSeconds: 1200},
+			want: map[string]float64{
+				FactorVisionScore: 0, FactorWardsPlaced: 0, FactorWardsDestroyed: 0,
+				FactorControlWards: 0, FactorVisionControlRatio: 0,
+			},
+		},
+		{
+			name: "com setup de objetivos",
+			in:   Input{DurationSeconds: 1200, VisionScore: 30, WardsPlaced: 12, WardsDestroyed: 3, ControlWardsPlaced: 2, ObjectiveSetup: ptr(0.75)},
+			want: map[string]float64{
+				FactorVisionScore: 1.5, FactorWardsPlaced: 0.6, FactorWardsDestroyed: 0.15,
+				FactorControlWards: 0.1, FactorVisionControlRatio: 0.25, FactorObjectiveSetup: 0.75,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FactorValues(tt.in)
+			if len(got) != len(tt.want) {
+				t.Errorf("fatores = %v, esperado %v", got, tt.want)
+			}
+			for name, want := range tt.want {
+				value, ok := got[name]
+				if !ok {
+					t.Errorf("fator %s ausente", name)
+					continue
+				}
+				if math.Abs(value-want) > 1e-9 {
+					t.Errorf("%s = %v, esperado %v", name, value, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRank(t *testing.T) {
+	tests := []struct {
+		score float64
+		want  string
+	}{
+		{100, "S+"},
+		{95, "S+"},
+		{94.9, "S"},
+		{90, "S"},
+		{89.9, "A+"},
+		{85, "A+"},
+		{84.9, "A"},
+		{80, "A"},
+		{79.9, "B+"},
+		{70, "B+"},
+		{69.9, "B"},
+		{60, "B"},
+		{59.9, "C"},
+		{0, "C"},
+		{-1, "C"},
+	}
+
+	for _, tt := range tests {
+		if got := Rank(tt.score); got != tt.want {
+			t.Errorf("Rank(%v) = %q, esperado %q", tt.score, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package scoring
+
+// Version é a versão do modelo de score usada nas análises novas. Incremente
+// ao mudar fórmula, fatores, pesos, tabela embutida de baselines ou ranks e
+// registre a mudança em Versions; análises de versões anteriores continuam
+// salvas e podem ser recalculadas pelo reprocessamento em massa.
+const Version = 3
+
+// VersionInfo descreve uma versão do modelo de score
+type VersionInfo struct {
+	Version     int    `json:"version"`
+	Description string `json:"description"`
+}
+
+// Versions é o histórico das versões do modelo de score
+var Versions = []VersionInfo{
+	{Version: 0, Description: "score simulado (aleatório), análises anteriores ao modelo determinístico"},
+	{Version: 1, Description: "fórmula determinística com metas únicas para todas as roles"},
+	{Version: 2, Description: "metas por role e fila (baselines)"},
+	{Version: 3, Description: "setup de visão antes de dragão, barão e arauto (objective_setup); pesos redistribuídos quando a partida não tem timeline"},
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"wardscore-api/internal/database"
+	"wardscore-api/internal/models"
+
+	"gorm.io/gorm/clause"
+)
+
+// AchievementService desbloqueia as conquistas do catálogo a partir das análises
+type AchievementService struct{}
+
+func NewAchievementService() *AchievementService {
+	return &AchievementService{}
+}
+
+// EvaluateForAnalysis confere o catálogo contra a análise recém-concluída e
+// desbloqueia as conquistas atingidas. Retorna só as desbloqueadas agora:
+// conquistas já obtidas (inclusive ao reprocessar o replay) não se repetem.
+func (as *AchievementService) EvaluateForAnalysis(analysis *models.Analysis) ([]models.UserAchievement, error) {
+	var achievements []models.Achievement
+	err := database.DB.
+		Where("id NOT IN (?)", database.DB.Model(&models.UserAchievement{}).Select("achievement_id").Where("user_id = ?", analysis.UserID)).
+		Find(&achievements).Error
+	if err != nil || len(achievements) == 0 {
+		return nil, err
+	}
+
+	// Partidas analisadas contam uma vez por replay, em qualquer versão do score
+	var games int64
+	err = database.DB.Model(&models.Analysis{}).
+		Where("user_id = ?", analysis.UserID).
+		Distinct("replay_id").
+		Count(&games).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var unlocked []models.UserAchievement
+	for i := range achievements {
+		if !achievementReached(achievements[i].RequirementSpec(), analysis, games) {
+			continue
+		}
+
+		userAchievement := models.UserAchievement{UserID: analysis.UserID, AchievementID: achievements[i].ID}
+		userAchievement.Unlock()
+
+		// Outro worker pode ter desbloqueado a mesma conquista ao mesmo tempo
+		result := database.DB.Omit(clause.Associations).
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&userAchievement)
+		if result.Error != nil {
+			return unlocked, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		userAchievement.Achievement = achievements[i]
+		unlocked = append(unlocked, userAchievement)
+	}
+
+	return unlocked, nil
+}
+
+// achievementReached verifica o critério contra a análise e o total de
+// partidas analisadas do usuário. Tipos desconhecidos nunca são atingidos.
+func achievementReached(requirement models.AchievementRequirement, analysis *models.Analysis, games int64) bool {
+	var value float64
+	switch requirement.Type {
+	case models.RequirementScore:
+		value = analysis.WardScore
+	case models.RequirementVisionScore:
+		value = float64(analysis.VisionScore)
+	case models.RequirementWardsPlaced:
+		value = float64(analysis.WardsPlaced)
+	case models.RequirementControlWards:
+		value = float64(analysis.ControlWardsPlaced)
+	case models.RequirementGames:
+		value = float64(games)
+	default:
+		return false
+	}
+
+	return requirement.Value > 0 && value >= requirement.Value
+}
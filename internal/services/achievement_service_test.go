@@ -0,0 +1,52 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"wardscore-api/internal/models"
+)
+
+func TestAchievementReached(t *testing.T) {
+	analysis := &models.Analysis{WardScore: 82.5, VisionScore: 64, WardsPlaced: 20, ControlWardsPlaced: 4}
+
+	tests := []struct {
+		requirement models.AchievementRequirement
+		games       int64
+		want        bool
+	}{
+		{models.AchievementRequirement{Type: models.RequirementScore, Value: 80}, 1, true},
+		{models.AchievementRequirement{Type: models.RequirementScore, Value: 95}, 1, false},
+		{models.AchievementRequirement{Type: models.RequirementVisionScore, Value: 64}, 1, true},
+		{models.AchievementRequirement{Type: models.RequirementVisionScore, Value: 80}, 1, false},
+		{models.AchievementRequirement{Type: models.RequirementWardsPlaced, Value: 20}, 1, true},
+		{models.AchievementRequirement{Type: models.RequirementControlWards, Value: 5}, 1, false},
+		{models.AchievementRequirement{Type: models.RequirementGames, Value: 25}, 24, false},
+		{models.AchievementRequirement{Type: models.RequirementGames, Value: 25}, 25, true},
+		// Critério sem valor ou de tipo desconhecido nunca é atingido
+		{models.AchievementRequirement{Type: models.RequirementScore}, 1, false},
+		{models.AchievementRequirement{Type: "pentakill", Value: 1}, 1, false},
+	}
+
+	for _, tt := range tests {
+		if got := achievementReached(tt.requirement, analysis, tt.games); got != tt.want {
+			t.Errorf("%s >= %v (partidas %d) = %v, esperado %v", tt.requirement.Type, tt.requirement.Value, tt.games, got, tt.want)
+		}
+	}
+}
+
+// Toda conquista do catálogo inicial pode ser desbloqueada
+func TestDefaultAchievementsAreReachable(t *testing.T) {
+	maxed := &models.Analysis{WardScore: 100, VisionScore: math.MaxInt32, WardsPlaced: math.MaxInt32, ControlWardsPlaced: math.MaxInt32}
+
+	titles := map[string]bool{}
+	for _, achievement := range models.DefaultAchievements() {
+		if titles[achievement.Title] {
+			t.Errorf("conquista %q repetida", achievement.Title)
+		}
+		titles[achievement.Title] = true
+
+		if !achievementReached(achievement.RequirementSpec(), maxed, math.MaxInt32) {
+			t.Errorf("conquista %q tem critério inalcançável: %s", achievement.Title, achievement.Requirement)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"wardscore-api/internal/database"
+	"wardscore-api/internal/models"
+)
+
+// SystemStats agrega contadores para o painel administrativo
+type SystemStats struct {
+	Users struct {
+		Total  int64            `json:"total"`
+		Banned int64            `json:"banned"`
+		ByRole map[string]int64 `json:"by_role"`
+	} `json:"users"`
+	Replays struct {
+		Total    int64            `json:"total"`
+		ByStatus map[string]int64 `json:"by_status"`
+	} `json:"replays"`
+	Analyses struct {
+		Total            int64   `json:"total"`
+		AverageWardScore float64 `json:"average_ward_score"`
+	} `json:"analyses"`
+}
+
+type AdminService struct{}
+
+func NewAdminService() *AdminService {
+	return &AdminService{}
+}
+
+// GetSystemStats calcula estatísticas gerais do sistema
+func (as *AdminService) GetSystemStats() (*SystemStats, error) {
+	stats := &SystemStats{}
+	stats.Users.ByRole = map[string]int64{}
+	stats.Replays.ByStatus = map[string]int64{}
+
+	if err := database.DB.Model(&models.User{}).Count(&stats.Users.Total).Error; err != nil {
+		return nil, err
+	}
+	database.DB.Model(&models.User{}).Where("banned_at IS NOT NULL").Count(&stats.Users.Banned)
+
+	var roleCounts []struct {
+		Role  string
+		Count int64
+	}
+	database.DB.Model(&models.User{}).Select("role, COUNT(*) AS count").Group("role").Scan(&roleCounts)
+	for _, rc := range roleCounts {
+		stats.Users.ByRole[rc.Role] = rc.Count
+	}
+
+	var statusCounts []struct {
+		Status string
+		Count  int64
+	}
+	database.DB.Model(&models.Replay{}).Select("status, COUNT(*) AS count").Group("status").Scan(&statusCounts)
+	for _, sc := range statusCounts {
+		stats.Replays.ByStatus[sc.Status] = sc.Count
+		stats.Replays.Total += sc.Count
+	}
+
+	// Apenas a versão mais recente do score de cada replay
+	database.DB.Model(&models.Analysis{}).Scopes(LatestAnalyses).Count(&stats.Analyses.Total)
+	database.DB.Model(&models.Analysis{}).Scopes(LatestAnalyses).Select("COALESCE(AVG(ward_score), 0)").Scan(&stats.Analyses.AverageWardScore)
+
+	return stats, nil
+}
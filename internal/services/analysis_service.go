@@ -1,16 +1,80 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
-	"math/rand"
+	"fmt"
+	"io"
+	"log"
+	"time"
 	"wardscore-api/internal/database"
+	"wardscore-api/internal/heatmap"
+	"wardscore-api/internal/insights"
 	"wardscore-api/internal/models"
+	"wardscore-api/internal/objectives"
+	"wardscore-api/internal/rofl"
+	"wardscore-api/internal/scoring"
+	"wardscore-api/internal/storage"
+	"wardscore-api/internal/timeline"
+	"wardscore-api/internal/zones"
+
+	"gorm.io/gorm"
+)
+
+var (
+    ErrReplayAlreadyProcessed = errors.New("replay já foi processado")
+    ErrPlayerNotFound         = errors.New("jogador não encontrado no replay")
+    ErrReplayWithoutFile      = errors.New("replay sem arquivo armazenado")
+    ErrBreakdownUnavailable   = errors.New("análise sem detalhamento do score; reprocesse o replay na versão atual")
+    ErrHeatmapUnavailable     = errors.New("análise sem posições de wards; a partida não tem timeline")
+    ErrReplayProcessing       = errors.New("replay em processamento; aguarde o fim antes de enviar a timeline")
 )
 
-type AnalysisService struct{}
+// IsPermanentError indica falhas em que uma nova tentativa daria o mesmo
+// resultado (replay removido, arquivo ausente ou inválido, jogador ausente)
+func IsPermanentError(err error) bool {
+    var truncatedErr *rofl.TruncatedError
+    var corruptErr *rofl.CorruptError
+    return errors.Is(err, ErrReplayNotFound) ||
+        errors.Is(err, ErrPlayerNotFound) ||
+        errors.Is(err, ErrReplayWithoutFile) ||
+        errors.Is(err, storage.ErrNotFound) ||
+        errors.Is(err, rofl.ErrInvalidMagic) ||
+        errors.As(err, &truncatedErr) ||
+        errors.As(err, &corruptErr)
+}
+
+// ScoringVersionLatest pede a análise da versão mais recente de cada replay
+const ScoringVersionLatest = -1
+
+// LatestAnalyses restringe a consulta à análise de versão mais recente de cada replay
+func LatestAnalyses(db *gorm.DB) *gorm.DB {
+    return db.Where("NOT EXISTS (SELECT 1 FROM analyses newer WHERE newer.replay_id = analyses.replay_id AND newer.scoring_version > analyses.scoring_version AND newer.deleted_at IS NULL)")
+}
+
+// analysesOfVersion filtra pela versão informada ou pela mais recente
+func analysesOfVersion(version int) func(*gorm.DB) *gorm.DB {
+    return func(db *gorm.DB) *gorm.DB {
+        if version == ScoringVersionLatest {
+            return LatestAnalyses(db)
+        }
+        return db.Where("analyses.scoring_version = ?", version)
+    }
+}
+
+type AnalysisService struct {
+    baselineService  *BaselineService
+    insightService   *InsightService
+    riotMatchService *RiotMatchService
+}
 
 func NewAnalysisService() *AnalysisService {
-    return &AnalysisService{}
+    return &AnalysisService{
+        baselineService:  NewBaselineService(),
+        insightService:   NewInsightService(),
+        riotMatchService: NewRiotMatchService(),
+    }
 }
 
 // GetByID busca análise por ID
@@ -23,10 +87,12 @@ func (as *AnalysisService) GetByID(id uint) (*models.Analysis, error) {
     return &analysis, nil
 }
 
-// GetByReplayID busca análise por replay ID
-func (as *AnalysisService) GetByReplayID(replayID uint) (*models.Analysis, error) {
+// GetByReplayID busca a análise do replay na versão informada
+// (ScoringVersionLatest para a mais recente)
+func (as *AnalysisService) GetByReplayID(replayID uint, version int) (*models.Analysis, error) {
     var analysis models.Analysis
     result := database.DB.Where("replay_id = ?", replayID).
+        Scopes(analysesOfVersion(version)).
         Preload("User").Preload("Replay").First(&analysis)
     if result.Error != nil {
         return nil, errors.New("análise não encontrada")
@@ -34,10 +100,25 @@ func (as *AnalysisService) GetByReplayID(replayID uint) (*models.Analysis, error
     return &analysis, nil
 }
 
-// GetByUserID busca análises do usuário
-func (as *AnalysisService) GetByUserID(userID uint) ([]models.Analysis, error) {
+// GetVersionsByReplayID lista as versões do modelo de score com análise salva para o replay
+func (as *AnalysisService) GetVersionsByReplayID(replayID uint) ([]int, error) {
+    var versions []int
+    result := database.DB.Model(&models.Analysis{}).
+        Where("replay_id = ?", replayID).
+        Order("scoring_version DESC").
+        Pluck("scoring_version", &versions)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    return versions, nil
+}
+
+// GetByUserID busca análises do usuário na versão informada
+// (ScoringVersionLatest para a mais recente de cada replay)
+func (as *AnalysisService) GetByUserID(userID uint, version int) ([]models.Analysis, error) {
     var analyses []models.Analysis
     result := database.DB.Where("user_id = ?", userID).
+        Scopes(analysesOfVersion(version)).
         Preload("Replay").
         Order("created_at DESC").
         Find(&analyses)
@@ -49,47 +130,177 @@ func (as *AnalysisService) GetByUserID(userID uint) ([]models.Analysis, error) {
     return analyses, nil
 }
 
-// ProcessReplay processa um replay e cria análise
-func (as *AnalysisService) ProcessReplay(replayID uint) (*models.Analysis, error) {
+// RankStanding é o rank do WardScore e o tier/divisão de ranking de uma análise
+type RankStanding struct {
+    AnalysisID uint    `json:"analysis_id"`
+    ReplayID   uint    `json:"replay_id"`
+    WardScore  float64 `json:"ward_score"`
+    Rank       string  `json:"rank"`
+    Tier       string  `json:"tier"`
+    Division   string  `json:"division"`
+}
+
+// RankChange é a mudança de rank ou tier entre a análise anterior do usuário
+// e a nova
+type RankChange struct {
+    Previous RankStanding `json:"previous"`
+    Current  RankStanding `json:"current"`
+}
+
+func newRankStanding(analysis *models.Analysis) RankStanding {
+    ranking := models.Ranking{WardScore: analysis.WardScore}
+    tier, division := ranking.GetTierFromScore()
+    return RankStanding{
+        AnalysisID: analysis.ID,
+        ReplayID:   analysis.ReplayID,
+        WardScore:  analysis.WardScore,
+        Rank:       analysis.Rank,
+        Tier:       tier,
+        Division:   division,
+    }
+}
+
+// GetRankChange compara a análise com a do replay anterior do usuário (versão
+// mais recente de cada um) e retorna a mudança de rank ou tier, ou nil se não
+// mudou. Só a partida mais recente do usuário muda o rank: reprocessar
+// replays antigos não gera mudança.
+func (as *AnalysisService) GetRankChange(analysis *models.Analysis) (*RankChange, error) {
+    var newer int64
+    err := database.DB.Model(&models.Analysis{}).
+        Where("user_id = ? AND replay_id > ?", analysis.UserID, analysis.ReplayID).
+        Scopes(LatestAnalyses).
+        Count(&newer).Error
+    if err != nil {
+        return nil, err
+    }
+    if newer > 0 {
+        return nil, nil
+    }
+
+    var previous models.Analysis
+    err = database.DB.Where("user_id = ? AND replay_id < ?", analysis.UserID, analysis.ReplayID).
+        Scopes(LatestAnalyses).
+        Order("replay_id DESC").
+        First(&previous).Error
+    if err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            return nil, nil
+        }
+        return nil, err
+    }
+
+    change := &RankChange{Previous: newRankStanding(&previous), Current: newRankStanding(analysis)}
+    if change.Previous.Rank == change.Current.Rank &&
+        change.Previous.Tier == change.Current.Tier &&
+        change.Previous.Division == change.Current.Division {
+        return nil, nil
+    }
+    return change, nil
+}
+
+// ProcessReplay processa um replay e cria análise, informando o andamento
+// em progress (opcional)
+func (as *AnalysisService) ProcessReplay(replayID uint, progress ProgressFunc) (*models.Analysis, error) {
+    if progress == nil {
+        progress = func(string, int) {}
+    }
+
     // Buscar replay
     var replay models.Replay
-    result := database.DB.First(&replay, replayID)
+    result := database.DB.Preload("User").First(&replay, replayID)
     if result.Error != nil {
-        return nil, errors.New("replay não encontrado")
+        return nil, ErrReplayNotFound
     }
 
-    // Verificar se já foi processado
+    // Verificar se já foi processado na versão atual do modelo de score
     var existingAnalysis models.Analysis
-    if database.DB.Where("replay_id = ?", replayID).First(&existingAnalysis).Error == nil {
-        return nil, errors.New("replay já foi processado")
+    if database.DB.Where("replay_id = ? AND scoring_version = ?", replayID, scoring.Version).First(&existingAnalysis).Error == nil {
+        return nil, ErrReplayAlreadyProcessed
     }
 
     // Marcar replay como processando
     replay.MarkAsProcessing()
     database.DB.Save(&replay)
+    progress("lendo arquivo .rofl", 10)
+
+    player, err := as.readPlayerStats(&replay)
+    if err != nil {
+        replay.MarkAsFailed()
+        database.DB.Save(&replay)
+        return nil, err
+    }
+    // Timeline de wards é opcional: sem ela a análise fica sem heatmap,
+    // visão por zona e setup de objetivos
+    tl, err := as.readTimeline(&replay)
+    if err != nil {
+        replay.MarkAsFailed()
+        database.DB.Save(&replay)
+        return nil, err
+    }
+    progress("calculando métricas", 60)
 
-    // SIMULAÇÃO: Criar análise com dados aleatórios
-    // Em produção, aqui seria o processamento real do arquivo .rofl
     analysis := &models.Analysis{
-        UserID:              replay.UserID,
-        ReplayID:            replayID,
-        WardScore:           50 + rand.Float64()*50, // 50-100
-        WardsPlaced:         10 + rand.Intn(20),     // 10-30
-        WardsDestroyed:      5 + rand.Intn(15),      // 5-20
-        VisionScore:         20 + rand.Intn(80),     // 20-100
-        ControlWardsPlaced:  3 + rand.Intn(7),       // 3-10
+        UserID:             replay.UserID,
+        ReplayID:           replayID,
+        WardsPlaced:        player.WardsPlaced(),
+        WardsDestroyed:     player.WardsKilled(),
+        VisionScore:        player.VisionScore(),
+        ControlWardsPlaced: player.ControlWardsBought(),
     }
 
-    // Calcular métricas derivadas
-    if replay.Duration > 0 {
-        analysis.WardsPerMinute = float64(analysis.WardsPlaced) / (float64(replay.Duration) / 60.0)
+    // Heatmap e visão por zona das wards do jogador, no lado do time dele, e
+    // setup de visão antes dos objetivos
+    var wards []timeline.Ward
+    var gameStats models.GameStats
+    side := heatmap.SideOf(player.Team())
+    if tl != nil {
+        analysis.TimelineAvailable = true
+        wards = tl.WardsOf(player.PUUID())
+        catalog := zones.ForGameVersion(replay.GameVersion)
+        gameEnd := int64(replay.Duration) * 1000
+        gameStats.Zones = catalog.Report(wards, side, gameEnd)
+        gameStats.Objectives = objectives.Analyze(tl, catalog, player.PUUID(), player.Team(), gameEnd)
+        analysis.GameStats, _ = json.Marshal(gameStats)
+        analysis.HeatmapData, _ = json.Marshal(heatmap.Build(wards, side))
     }
-    
-    if analysis.WardsPlaced > 0 {
-        analysis.VisionControlRatio = float64(analysis.WardsDestroyed) / float64(analysis.WardsPlaced)
+
+    // Calcular WardScore e métricas derivadas contra o baseline da role/fila
+    baseline, err := as.baselineService.Resolve(replay.Role, replay.Queue)
+    if err != nil {
+        replay.MarkAsFailed()
+        database.DB.Save(&replay)
+        return nil, fmt.Errorf("falha ao buscar baseline: %w", err)
     }
+    input := scoringInput(analysis, replay.Duration, &gameStats)
+    score := scoring.Score(input, baseline)
+    analysis.ScoringVersion = score.Version
+    analysis.WardScore = score.WardScore
+    analysis.Rank = score.Rank
+    analysis.WardsPerMinute = score.WardsPerMinute
+    analysis.VisionControlRatio = score.VisionControlRatio
+    analysis.Baseline, _ = json.Marshal(score.Baseline)
+    analysis.Breakdown, _ = json.Marshal(scoring.NewBreakdown(score))
+
+    // Gerar insights e sugestões com o catálogo de regras em uso
+    insightContext := insights.NewContext(input, score, replay.Role, replay.Queue, replay.Champion)
+    if tl != nil {
+        insightContext.Set(insights.MetricWardsFirst5Minutes, float64(timeline.CountBefore(wards, 5*time.Minute)))
+    }
+    if gameStats.Objectives != nil && gameStats.Objectives.Objectives > 0 {
+        insightContext.Set(insights.MetricObjectives, float64(gameStats.Objectives.Objectives))
+        insightContext.Set(insights.MetricObjectivesCovered, float64(gameStats.Objectives.Covered))
+    }
+    found, suggestions, err := as.insightService.Evaluate(insightContext)
+    if err != nil {
+        replay.MarkAsFailed()
+        database.DB.Save(&replay)
+        return nil, fmt.Errorf("falha ao buscar regras de insight: %w", err)
+    }
+    analysis.Insights, _ = json.Marshal(found)
+    analysis.Suggestions, _ = json.Marshal(suggestions)
 
     // Criar análise
+    progress("salvando análise", 90)
     result = database.DB.Create(analysis)
     if result.Error != nil {
         // Marcar replay como falhou
@@ -105,6 +316,249 @@ func (as *AnalysisService) ProcessReplay(replayID uint) (*models.Analysis, error
     return analysis, nil
 }
 
+// readPlayerStats lê o .rofl do storage, preenche os dados da partida no
+// replay e retorna as estatísticas do jogador que fez o upload
+func (as *AnalysisService) readPlayerStats(replay *models.Replay) (*rofl.PlayerStats, error) {
+    if replay.FilePath == "" {
+        return nil, ErrReplayWithoutFile
+    }
+
+    object, err := storage.Store.Get(context.Background(), replay.FilePath)
+    if err != nil {
+        return nil, fmt.Errorf("falha ao abrir arquivo do replay: %w", err)
+    }
+    defer object.Close()
+
+    parsed, err := rofl.Parse(object)
+    if err != nil {
+        return nil, err
+    }
+
+    var puuid, gameName string
+    if replay.User != nil {
+        puuid, gameName = replay.User.PUUID, replay.User.GameName
+    }
+
+    player := parsed.FindPlayer(puuid, gameName)
+    if player == nil {
+        return nil, ErrPlayerNotFound
+    }
+
+    replay.Duration = int(parsed.Duration().Seconds())
+    replay.GameVersion = parsed.Metadata.GameVersion
+    replay.Champion = player.Champion()
+    replay.Role = normalizeRole(player.Position())
+
+    return player, nil
+}
+
+// readTimeline lê a timeline de wards e objetivos do replay. Sem timeline
+// retorna nil; timeline inválida é ignorada para não impedir a análise.
+func (as *AnalysisService) readTimeline(replay *models.Replay) (*timeline.Timeline, error) {
+    parsed, err := timeline.Load(context.Background(), storage.Store, replay.FilePath)
+    if err != nil {
+        var invalidErr *timeline.InvalidError
+        if errors.Is(err, timeline.ErrUnavailable) {
+            return nil, nil
+        }
+        if errors.As(err, &invalidErr) {
+            log.Printf("⚠️ Timeline do replay %d ignorada: %v", replay.ID, err)
+            return nil, nil
+        }
+        return nil, err
+    }
+
+    return parsed, nil
+}
+
+// scoringInput monta a entrada do WardScore a partir das estatísticas da
+// análise e dos grupos de métricas da partida
+func scoringInput(analysis *models.Analysis, durationSeconds int, gameStats *models.GameStats) scoring.Input {
+    input := scoring.Input{
+        DurationSeconds:    durationSeconds,
+        VisionScore:        analysis.VisionScore,
+        WardsPlaced:        analysis.WardsPlaced,
+        WardsDestroyed:     analysis.WardsDestroyed,
+        ControlWardsPlaced: analysis.ControlWardsPlaced,
+    }
+    if gameStats.Objectives != nil && gameStats.Objectives.Objectives > 0 {
+        coverage := gameStats.Objectives.Coverage
+        input.ObjectiveSetup = &coverage
+    }
+    return input
+}
+
+// normalizeRole converte a posição do replay para o nome usado na API
+func normalizeRole(position string) string {
+    switch position {
+    case "UTILITY":
+        return "SUPPORT"
+    case "":
+        return ""
+    default:
+        return position
+    }
+}
+
+// ResetForReprocess descarta a análise da versão atual do modelo de score e
+// volta o replay para uploaded, pronto para ser enfileirado de novo. Análises
+// de versões anteriores são mantidas.
+func (as *AnalysisService) ResetForReprocess(replayID uint) (*models.Replay, error) {
+    var replay models.Replay
+    if database.DB.First(&replay, replayID).Error != nil {
+        return nil, errors.New("replay não encontrado")
+    }
+
+    result := database.DB.Unscoped().
+        Where("replay_id = ? AND scoring_version = ?", replayID, scoring.Version).
+        Delete(&models.Analysis{})
+    if result.Error != nil {
+        return nil, result.Error
+    }
+
+    replay.Status = models.StatusUploaded
+    replay.ProcessedAt = nil
+    replay.Attempts = 0
+    if err := database.DB.Save(&replay).Error; err != nil {
+        return nil, err
+    }
+
+    return &replay, nil
+}
+
+// AttachTimeline valida e grava a timeline de wards e objetivos do replay e
+// descarta a análise da versão atual, para que o replay seja processado de
+// novo com a timeline. Timelines sem objetivos são completadas com os abates
+// de dragão, barão e arauto da API de partidas da Riot, quando configurada.
+// Timelines inválidas retornam *timeline.InvalidError.
+func (as *AnalysisService) AttachTimeline(replay *models.Replay, r io.Reader) (*models.Replay, error) {
+    if replay.Status == models.StatusProcessing {
+        return nil, ErrReplayProcessing
+    }
+    if replay.FilePath == "" {
+        return nil, ErrReplayWithoutFile
+    }
+
+    parsed, err := timeline.Parse(r)
+    if err != nil {
+        return nil, err
+    }
+    if len(parsed.Objectives) == 0 {
+        found, err := as.riotMatchService.GetObjectives(replay.MatchID)
+        switch {
+        case err == nil:
+            parsed.Objectives = found
+        case !errors.Is(err, ErrRiotAPIKeyMissing):
+            log.Printf("⚠️ Objetivos da partida %s indisponíveis: %v", replay.MatchID, err)
+        }
+    }
+    if err := timeline.Save(context.Background(), storage.Store, replay.FilePath, parsed); err != nil {
+        return nil, err
+    }
+
+    return as.ResetForReprocess(replay.ID)
+}
+
+// GetBreakdown retorna o detalhamento do score salvo na análise. Análises da
+// versão atual salvas sem detalhamento são recalculadas a partir das
+// estatísticas e do baseline guardados; versões anteriores não.
+func (as *AnalysisService) GetBreakdown(analysis *models.Analysis) (*scoring.Breakdown, error) {
+    var breakdown scoring.Breakdown
+    if len(analysis.Breakdown) > 0 {
+        if err := json.Unmarshal(analysis.Breakdown, &breakdown); err != nil {
+            return nil, err
+        }
+        return &breakdown, nil
+    }
+
+    if analysis.ScoringVersion != scoring.Version || analysis.Replay == nil {
+        return nil, ErrBreakdownUnavailable
+    }
+
+    var baseline scoring.Baseline
+    if len(analysis.Baseline) == 0 || json.Unmarshal(analysis.Baseline, &baseline) != nil {
+        return nil, ErrBreakdownUnavailable
+    }
+
+    var gameStats models.GameStats
+    if len(analysis.GameStats) > 0 && json.Unmarshal(analysis.GameStats, &gameStats) != nil {
+        return nil, ErrBreakdownUnavailable
+    }
+
+    breakdown = scoring.NewBreakdown(scoring.Score(scoringInput(analysis, analysis.Replay.Duration, &gameStats), baseline))
+    return &breakdown, nil
+}
+
+// GetHeatmap retorna o heatmap de wards salvo na análise
+func (as *AnalysisService) GetHeatmap(analysis *models.Analysis) (*heatmap.Heatmap, error) {
+    if len(analysis.HeatmapData) == 0 {
+        return nil, ErrHeatmapUnavailable
+    }
+
+    var h heatmap.Heatmap
+    if err := json.Unmarshal(analysis.HeatmapData, &h); err != nil {
+        return nil, err
+    }
+    return &h, nil
+}
+
+// GetUserHeatmap soma os heatmaps das análises do usuário (a versão mais
+// recente de cada replay). Partidas sem timeline não entram na soma.
+func (as *AnalysisService) GetUserHeatmap(userID uint) (*heatmap.Heatmap, error) {
+    var rows []json.RawMessage
+    result := database.DB.Model(&models.Analysis{}).
+        Where("user_id = ? AND heatmap_data IS NOT NULL", userID).
+        Scopes(LatestAnalyses).
+        Pluck("heatmap_data", &rows)
+    if result.Error != nil {
+        return nil, result.Error
+    }
+
+    aggregate := heatmap.New()
+    for _, row := range rows {
+        var h heatmap.Heatmap
+        if err := json.Unmarshal(row, &h); err != nil {
+            continue
+        }
+        aggregate.Add(&h)
+    }
+    return aggregate, nil
+}
+
+// StartBulkReprocess seleciona até limit replays concluídos sem análise na
+// versão atual do modelo de score e zera as tentativas deles, para serem
+// enfileirados de novo. Retorna os replays e quantos faltavam no total.
+func (as *AnalysisService) StartBulkReprocess(limit int) ([]models.Replay, int64, error) {
+    query := database.DB.Model(&models.Replay{}).
+        Where("status = ?", models.StatusCompleted).
+        Where("NOT EXISTS (SELECT 1 FROM analyses WHERE analyses.replay_id = replays.id AND analyses.scoring_version = ? AND analyses.deleted_at IS NULL)", scoring.Version)
+
+    var total int64
+    if err := query.Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    var replays []models.Replay
+    if err := query.Order("id").Limit(limit).Find(&replays).Error; err != nil {
+        return nil, 0, err
+    }
+    if len(replays) == 0 {
+        return replays, total, nil
+    }
+
+    ids := make([]uint, len(replays))
+    for i := range replays {
+        ids[i] = replays[i].ID
+        replays[i].Attempts = 0
+    }
+    result := database.DB.Model(&models.Replay{}).Where("id IN ?", ids).UpdateColumn("attempts", 0)
+    if result.Error != nil {
+        return nil, 0, result.Error
+    }
+
+    return replays, total, nil
+}
+
 // Create cria nova análise
 func (as *AnalysisService) Create(analysis *models.Analysis) (*models.Analysis, error) {
     result := database.DB.Create(analysis)
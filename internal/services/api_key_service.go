@@ -0,0 +1,132 @@
+package services
+
+import (
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+	"wardscore-api/internal/database"
+	"wardscore-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var ErrAPIKeyInvalid = errors.New("API key inválida, revogada ou expirada")
+
+type APIKeyService struct{}
+
+func NewAPIKeyService() *APIKeyService {
+	return &APIKeyService{}
+}
+
+// Create gera uma nova API key para o usuário. A chave em texto puro só é
+// retornada aqui; depois disso apenas o hash fica salvo.
+func (aks *APIKeyService) Create(user *models.User, name string, scopes []models.Permission, expiresAt *time.Time) (*models.APIKey, string, error) {
+	if len(scopes) == 0 {
+		return nil, "", errors.New("informe ao menos um escopo")
+	}
+	for _, scope := range scopes {
+		if !user.HasPermission(scope) {
+			return nil, "", errors.New("escopo não permitido: " + string(scope))
+		}
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", errors.New("expires_at deve estar no futuro")
+	}
+
+	prefixBytes, err := randomBytes(6)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+
+	prefix := hex.EncodeToString(prefixBytes)
+	rawKey := models.APIKeyPrefix + prefix + "_" + secret
+
+	scopesJSON, err := json.Marshal(scopes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	apiKey := &models.APIKey{
+		UserID:    user.ID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   hashToken(rawKey),
+		Scopes:    scopesJSON,
+		ExpiresAt: expiresAt,
+	}
+
+	if result := database.DB.Create(apiKey); result.Error != nil {
+		return nil, "", result.Error
+	}
+
+	return apiKey, rawKey, nil
+}
+
+// GetByUserID lista as API keys do usuário
+func (aks *APIKeyService) GetByUserID(userID uint) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	result := database.DB.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&keys)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return keys, nil
+}
+
+// Revoke revoga uma API key do usuário
+func (aks *APIKeyService) Revoke(userID, id uint) error {
+	result := database.DB.Model(&models.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("API key não encontrada")
+	}
+
+	return nil
+}
+
+// Authenticate valida a chave em texto puro e registra o uso
+func (aks *APIKeyService) Authenticate(rawKey string) (*models.APIKey, error) {
+	parts := strings.SplitN(strings.TrimPrefix(rawKey, models.APIKeyPrefix), "_", 2)
+	if !strings.HasPrefix(rawKey, models.APIKeyPrefix) || len(parts) != 2 {
+		return nil, ErrAPIKeyInvalid
+	}
+
+	var apiKey models.APIKey
+	if database.DB.Where("prefix = ?", parts[0]).First(&apiKey).Error != nil {
+		return nil, ErrAPIKeyInvalid
+	}
+
+	if subtle.ConstantTimeCompare([]byte(apiKey.KeyHash), []byte(hashToken(rawKey))) != 1 {
+		return nil, ErrAPIKeyInvalid
+	}
+
+	if !apiKey.IsActive() {
+		return nil, ErrAPIKeyInvalid
+	}
+
+	// Incremento atômico para não perder contagem entre instâncias
+	now := time.Now()
+	database.DB.Model(&apiKey).UpdateColumns(map[string]interface{}{
+		"last_used_at":  now,
+		"request_count": gorm.Expr("request_count + 1"),
+	})
+	apiKey.LastUsedAt = &now
+	apiKey.RequestCount++
+
+	return &apiKey, nil
+}
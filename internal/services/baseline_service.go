@@ -0,0 +1,267 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+	"wardscore-api/internal/config"
+	"wardscore-api/internal/database"
+	"wardscore-api/internal/models"
+	"wardscore-api/internal/scoring"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrBaselineNotFound = errors.New("baseline não encontrado")
+
+// BaselineService resolve e mantém os baselines de role/fila do WardScore.
+//
+// Ordem de busca para uma análise: baseline salvo da role e fila, baseline
+// salvo da role (qualquer fila) e, por fim, a tabela embutida da role e fila
+// (scoring.DefaultBaseline). Partidas de ARAM pulam o baseline de qualquer
+// fila, que vem de partidas em Summoner's Rift. Metas ausentes num baseline
+// salvo vêm da tabela embutida.
+type BaselineService struct{}
+
+func NewBaselineService() *BaselineService {
+	return &BaselineService{}
+}
+
+// Resolve retorna o baseline usado para uma partida da role e fila informadas
+func (bs *BaselineService) Resolve(role, queue string) (scoring.Baseline, error) {
+	role = scoring.NormalizeRole(role)
+	queue = scoring.NormalizeQueue(queue)
+
+	var rows []models.ScoringBaseline
+	result := database.DB.Where("role = ? AND queue IN ?", role, []string{queue, ""}).Find(&rows)
+	if result.Error != nil {
+		return scoring.Baseline{}, result.Error
+	}
+
+	return resolveBaseline(rows, role, queue), nil
+}
+
+// resolveBaseline escolhe entre os baselines salvos da role o da fila, depois
+// o de qualquer fila (exceto no ARAM) e, sem nenhum, a tabela embutida
+func resolveBaseline(rows []models.ScoringBaseline, role, queue string) scoring.Baseline {
+	candidates := []string{queue}
+	if queue != "" && queue != scoring.QueueARAM {
+		candidates = append(candidates, "")
+	}
+
+	for _, candidate := range candidates {
+		for i := range rows {
+			if rows[i].Role == role && rows[i].Queue == candidate {
+				return toBaseline(&rows[i])
+			}
+		}
+	}
+	return scoring.DefaultBaseline(role, queue)
+}
+
+// List lista os baselines salvos
+func (bs *BaselineService) List() ([]models.ScoringBaseline, error) {
+	var baselines []models.ScoringBaseline
+	result := database.DB.Order("role, queue").Find(&baselines)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return baselines, nil
+}
+
+// Defaults lista a tabela embutida de cada role nas filas de Summoner's Rift
+// (sem fila equivale à ranqueada solo) e a do ARAM, igual para todas as roles
+func (bs *BaselineService) Defaults() []scoring.Baseline {
+	roles := []string{"", scoring.RoleTop, scoring.RoleJungle, scoring.RoleMiddle, scoring.RoleBottom, scoring.RoleSupport}
+	queues := []string{"", scoring.QueueRankedFlex, scoring.QueueNormal}
+	defaults := make([]scoring.Baseline, 0, len(roles)*len(queues)+1)
+	for _, queue := range queues {
+		for _, role := range roles {
+			defaults = append(defaults, scoring.DefaultBaseline(role, queue))
+		}
+	}
+	return append(defaults, scoring.DefaultBaseline("", scoring.QueueARAM))
+}
+
+// Set define manualmente as metas de uma role e fila (vazias valem para
+// todas). Baselines definidos assim não são sobrescritos pelo Recompute.
+func (bs *BaselineService) Set(role, queue string, targets map[string]float64) (*models.ScoringBaseline, error) {
+	normalizedRole := scoring.NormalizeRole(role)
+	if role != "" && normalizedRole == "" {
+		return nil, errors.New("role inválida: " + role)
+	}
+	normalizedQueue := scoring.NormalizeQueue(queue)
+	if queue != "" && normalizedQueue == "" {
+		return nil, errors.New("fila inválida: " + queue)
+	}
+
+	if len(targets) == 0 {
+		return nil, errors.New("informe ao menos uma meta")
+	}
+	for name, target := range targets {
+		if !scoring.IsKnownFactor(name) {
+			return nil, errors.New("fator desconhecido: " + name)
+		}
+		if target <= 0 {
+			return nil, errors.New("meta deve ser maior que zero: " + name)
+		}
+	}
+
+	baseline := &models.ScoringBaseline{
+		Role:   normalizedRole,
+		Queue:  normalizedQueue,
+		Source: scoring.SourceCustom,
+	}
+	if err := saveBaseline(database.DB, baseline, targets); err != nil {
+		return nil, err
+	}
+	return baseline, nil
+}
+
+// Delete remove um baseline salvo; a role volta a usar o próximo da ordem de busca
+func (bs *BaselineService) Delete(id uint) error {
+	result := database.DB.Delete(&models.ScoringBaseline{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrBaselineNotFound
+	}
+	return nil
+}
+
+// Recompute recalcula os baselines a partir das análises salvas, por role e
+// fila e por role (todas as filas). Grupos com menos de
+// ScoringBaselineMinSamples análises e baselines custom ficam como estão.
+func (bs *BaselineService) Recompute() ([]models.ScoringBaseline, error) {
+	var rows []baselineSampleRow
+	result := database.DB.Table("analyses").
+		Select("replays.role, replays.queue, replays.duration, analyses.vision_score, analyses.wards_placed, analyses.wards_destroyed, analyses.control_wards_placed, " +
+			"COALESCE((analyses.game_stats->'objectives'->>'objectives')::int, 0) AS objectives, " +
+			"COALESCE((analyses.game_stats->'objectives'->>'coverage')::float, 0) AS objective_coverage").
+		Joins("JOIN replays ON replays.id = analyses.replay_id AND replays.deleted_at IS NULL").
+		Where("analyses.deleted_at IS NULL").
+		Scopes(LatestAnalyses).
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	type groupKey struct{ role, queue string }
+	groups := map[groupKey][]scoring.Input{}
+	for _, row := range rows {
+		sample := row.input()
+		role := scoring.NormalizeRole(row.Role)
+		queue := scoring.NormalizeQueue(row.Queue)
+
+		// O ARAM tem metas próprias e fica fora do baseline de qualquer fila
+		if queue != scoring.QueueARAM {
+			groups[groupKey{role, ""}] = append(groups[groupKey{role, ""}], sample)
+		}
+		if queue != "" {
+			groups[groupKey{role, queue}] = append(groups[groupKey{role, queue}], sample)
+		}
+	}
+
+	var custom []models.ScoringBaseline
+	if err := database.DB.Where("source = ?", scoring.SourceCustom).Find(&custom).Error; err != nil {
+		return nil, err
+	}
+	isCustom := map[groupKey]bool{}
+	for _, baseline := range custom {
+		isCustom[groupKey{baseline.Role, baseline.Queue}] = true
+	}
+
+	var updated []models.ScoringBaseline
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		for key, samples := range groups {
+			if isCustom[key] || len(samples) < config.AppConfig.ScoringBaselineMinSamples {
+				continue
+			}
+
+			baseline := models.ScoringBaseline{
+				Role:       key.role,
+				Queue:      key.queue,
+				Source:     scoring.SourceComputed,
+				SampleSize: len(samples),
+			}
+			targets := scoring.ComputeTargets(samples, config.AppConfig.ScoringBaselinePercentile)
+			if err := saveBaseline(tx, &baseline, targets); err != nil {
+				return err
+			}
+			updated = append(updated, baseline)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// baselineSampleRow é uma análise usada no Recompute, com a cobertura de
+// setup de objetivos guardada em game_stats.objectives
+type baselineSampleRow struct {
+	Role               string
+	Queue              string
+	Duration           int
+	VisionScore        int
+	WardsPlaced        int
+	WardsDestroyed     int
+	ControlWardsPlaced int
+	Objectives         int
+	ObjectiveCoverage  float64
+}
+
+// input monta a entrada do score da análise. Como em scoringInput, o setup de
+// objetivos só conta em partidas com timeline e ao menos um objetivo.
+func (row baselineSampleRow) input() scoring.Input {
+	input := scoring.Input{
+		DurationSeconds:    row.Duration,
+		VisionScore:        row.VisionScore,
+		WardsPlaced:        row.WardsPlaced,
+		WardsDestroyed:     row.WardsDestroyed,
+		ControlWardsPlaced: row.ControlWardsPlaced,
+	}
+	if row.Objectives > 0 {
+		coverage := row.ObjectiveCoverage
+		input.ObjectiveSetup = &coverage
+	}
+	return input
+}
+
+// saveBaseline grava o baseline, substituindo o existente da mesma role e fila
+func saveBaseline(tx *gorm.DB, baseline *models.ScoringBaseline, targets map[string]float64) error {
+	targetsJSON, err := json.Marshal(targets)
+	if err != nil {
+		return err
+	}
+	baseline.Targets = targetsJSON
+	baseline.UpdatedAt = time.Now()
+
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "role"}, {Name: "queue"}},
+		DoUpdates: clause.AssignmentColumns([]string{"source", "sample_size", "targets", "updated_at"}),
+	}).Create(baseline).Error
+}
+
+// toBaseline converte o baseline salvo, completando metas ausentes com a
+// tabela embutida da role e fila
+func toBaseline(row *models.ScoringBaseline) scoring.Baseline {
+	baseline := scoring.DefaultBaseline(row.Role, row.Queue)
+	baseline.Role = row.Role
+	baseline.Queue = row.Queue
+	baseline.Source = row.Source
+	baseline.SampleSize = row.SampleSize
+
+	var targets map[string]float64
+	json.Unmarshal(row.Targets, &targets)
+	for name, target := range targets {
+		if target > 0 {
+			baseline.Targets[name] = target
+		}
+	}
+	return baseline
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"wardscore-api/internal/models"
+	"wardscore-api/internal/scoring"
+)
+
+func savedBaseline(role, queue, source string, visionScore float64) models.ScoringBaseline {
+	targets, _ := json.Marshal(map[string]float64{scoring.FactorVisionScore: visionScore})
+	return models.ScoringBaseline{Role: role, Queue: queue, Source: source, SampleSize: 50, Targets: targets}
+}
+
+func TestResolveBaseline(t *testing.T) {
+	supportSolo := savedBaseline(scoring.RoleSupport, scoring.QueueRankedSolo, scoring.SourceComputed, 3.1)
+	supportAny := savedBaseline(scoring.RoleSupport, "", scoring.SourceCustom, 2.5)
+	supportARAM := savedBaseline(scoring.RoleSupport, scoring.QueueARAM, scoring.SourceComputed, 0.4)
+
+	tests := []struct {
+		name        string
+		rows        []models.ScoringBaseline
+		queue       string
+		wantQueue   string
+		wantSource  string
+		wantVision  float64
+		wantSamples int
+	}{
+		{"role e fila", []models.ScoringBaseline{supportAny, supportSolo}, scoring.QueueRankedSolo, scoring.QueueRankedSolo, scoring.SourceComputed, 3.1, 50},
+		{"role em qualquer fila", []models.ScoringBaseline{supportAny, supportSolo}, scoring.QueueRankedFlex, "", scoring.SourceCustom, 2.5, 50},
+		{"partida sem fila", []models.ScoringBaseline{supportSolo, supportAny}, "", "", scoring.SourceCustom, 2.5, 50},
+		{"tabela embutida", nil, scoring.QueueNormal, scoring.QueueNormal, scoring.SourceDefault, 2.38, 0},
+		{"ARAM com baseline próprio", []models.ScoringBaseline{supportAny, supportARAM}, scoring.QueueARAM, scoring.QueueARAM, scoring.SourceComputed, 0.4, 50},
+		// O baseline de qualquer fila vem de Summoner's Rift
+		{"ARAM sem baseline próprio", []models.ScoringBaseline{supportAny, supportSolo}, scoring.QueueARAM, scoring.QueueARAM, scoring.SourceDefault, 0.35, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseline := resolveBaseline(tt.rows, scoring.RoleSupport, tt.queue)
+			if baseline.Role != scoring.RoleSupport || baseline.Queue != tt.wantQueue || baseline.Source != tt.wantSource {
+				t.Errorf("baseline = %s/%s/%s, esperado %s/%s/%s", baseline.Role, baseline.Queue, baseline.Source, scoring.RoleSupport, tt.wantQueue, tt.wantSource)
+			}
+			if baseline.SampleSize != tt.wantSamples {
+				t.Errorf("amostras = %d, esperado %d", baseline.SampleSize, tt.wantSamples)
+			}
+			if got := baseline.Targets[scoring.FactorVisionScore]; got != tt.wantVision {
+				t.Errorf("meta de vision_score = %v, esperado %v", got, tt.wantVision)
+			}
+		})
+	}
+}
+
+// Metas ausentes no baseline salvo vêm da tabela embutida da role e fila
+func TestResolveBaselineFillsMissingTargets(t *testing.T) {
+	rows := []models.ScoringBaseline{savedBaseline(scoring.RoleSupport, scoring.QueueNormal, scoring.SourceCustom, 3)}
+	baseline := resolveBaseline(rows, scoring.RoleSupport, scoring.QueueNormal)
+
+	defaults := scoring.DefaultBaseline(scoring.RoleSupport, scoring.QueueNormal)
+	for name, want := range defaults.Targets {
+		if name == scoring.FactorVisionScore {
+			want = 3
+		}
+		if got := baseline.Targets[name]; got != want {
+			t.Errorf("meta de %s = %v, esperado %v", name, got, want)
+		}
+	}
+}
+
+// O recálculo usa a cobertura de setup guardada em game_stats.objectives,
+// e só nas análises com objetivos na timeline
+func TestBaselineSampleRowInput(t *testing.T) {
+	withTimeline := baselineSampleRow{Duration: 1800, VisionScore: 60, WardsPlaced: 24, Objectives: 4, ObjectiveCoverage: 0.75}
+	withoutObjectives := baselineSampleRow{Duration: 1800, VisionScore: 60, WardsPlaced: 24, ObjectiveCoverage: 0.75}
+
+	input := withTimeline.input()
+	if input.ObjectiveSetup == nil || *input.ObjectiveSetup != 0.75 {
+		t.Fatalf("ObjectiveSetup = %v, esperado 0.75", input.ObjectiveSetup)
+	}
+	if input.DurationSeconds != 1800 || input.VisionScore != 60 || input.WardsPlaced != 24 {
+		t.Errorf("entrada = %+v", input)
+	}
+	if input := withoutObjectives.input(); input.ObjectiveSetup != nil {
+		t.Errorf("ObjectiveSetup = %v sem objetivos, esperado nil", *input.ObjectiveSetup)
+	}
+
+	samples := []scoring.Input{withTimeline.input(), withoutObjectives.input(), withTimeline.input()}
+	targets := scoring.ComputeTargets(samples, 50)
+	if got := targets[scoring.FactorObjectiveSetup]; got != 0.75 {
+		t.Errorf("meta de objective_setup = %v, esperado 0.75", got)
+	}
+	if got := scoring.ComputeTargets([]scoring.Input{withoutObjectives.input()}, 50); got[scoring.FactorObjectiveSetup] != 0 {
+		t.Errorf("meta de objective_setup sem timeline = %v, esperado ausente", got[scoring.FactorObjectiveSetup])
+	}
+}
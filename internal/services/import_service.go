@@ -0,0 +1,249 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"wardscore-api/internal/config"
+	"wardscore-api/internal/models"
+	"wardscore-api/internal/rofl"
+)
+
+var (
+	ErrImportTooLarge  = errors.New("arquivo ZIP excede o tamanho máximo")
+	ErrImportInvalid   = errors.New("arquivo ZIP inválido")
+	ErrImportTooMany   = errors.New("arquivo ZIP com replays demais")
+	ErrImportNoReplays = errors.New("arquivo ZIP sem replays .rofl")
+)
+
+// maxCompressionRatio limita a razão descompactado/compactado de cada entrada.
+// Replays .rofl já são compactados, então razões altas indicam zip bomb.
+const maxCompressionRatio = 20
+
+// ImportStatus é o resultado de cada arquivo do ZIP
+type ImportStatus string
+
+const (
+	ImportCreated   ImportStatus = "created"
+	ImportDuplicate ImportStatus = "duplicate"
+	ImportInvalid   ImportStatus = "invalid"
+	ImportFailed    ImportStatus = "failed"
+	ImportSkipped   ImportStatus = "skipped"
+)
+
+// ImportResult é a linha do relatório de um arquivo do ZIP
+type ImportResult struct {
+	File     string       `json:"file"`
+	Status   ImportStatus `json:"status"`
+	Reason   string       `json:"reason,omitempty"`
+	ReplayID uint         `json:"replay_id,omitempty"`
+	JobID    string       `json:"job_id,omitempty"`
+}
+
+// ImportReport é o relatório da importação, arquivo por arquivo
+type ImportReport struct {
+	Total   int                  `json:"total"`
+	Summary map[ImportStatus]int `json:"summary"`
+	Results []ImportResult       `json:"results"`
+}
+
+func (r *ImportReport) add(result ImportResult) {
+	r.Results = append(r.Results, result)
+	r.Summary[result.Status]++
+}
+
+// StagedArchive é o ZIP enviado, gravado em arquivo temporário
+type StagedArchive struct {
+	TempPath string
+	FileSize int64
+}
+
+// Remove apaga o arquivo temporário
+func (a *StagedArchive) Remove() {
+	if a != nil {
+		os.Remove(a.TempPath)
+	}
+}
+
+// ImportService importa vários replays de um ZIP pelo mesmo fluxo do upload
+// individual (validação, deduplicação e storage)
+type ImportService struct {
+	replayService *ReplayService
+	jobService    *JobService
+}
+
+func NewImportService(replayService *ReplayService, jobService *JobService) *ImportService {
+	return &ImportService{replayService: replayService, jobService: jobService}
+}
+
+// StageArchive grava o ZIP em arquivo temporário (o leitor de ZIP precisa de
+// acesso aleatório), respeitando MaxImportSize
+func (is *ImportService) StageArchive(src io.Reader) (*StagedArchive, error) {
+	tmp, err := os.CreateTemp("", "wardscore-import-*.zip")
+	if err != nil {
+		return nil, err
+	}
+	staged := &StagedArchive{TempPath: tmp.Name()}
+
+	maxSize := config.AppConfig.MaxImportSize
+	size, err := io.Copy(tmp, io.LimitReader(src, maxSize+1))
+	closeErr := tmp.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil && size > maxSize {
+		err = ErrImportTooLarge
+	}
+	if err != nil {
+		staged.Remove()
+		return nil, err
+	}
+
+	staged.FileSize = size
+	return staged, nil
+}
+
+// Import valida e cria cada replay do ZIP. Entradas são lidas uma de cada vez
+// direto para arquivo temporário; quando o prazo de ctx acaba, as restantes
+// ficam como skipped.
+func (is *ImportService) Import(ctx context.Context, user *models.User, archive *StagedArchive) (*ImportReport, error) {
+	reader, err := zip.OpenReader(archive.TempPath)
+	if err != nil {
+		return nil, ErrImportInvalid
+	}
+	defer reader.Close()
+
+	var entries []*zip.File
+	for _, entry := range reader.File {
+		if !entry.FileInfo().IsDir() {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		return nil, ErrImportNoReplays
+	}
+	if len(entries) > config.AppConfig.MaxImportEntries {
+		return nil, fmt.Errorf("%w (máximo %d)", ErrImportTooMany, config.AppConfig.MaxImportEntries)
+	}
+
+	report := &ImportReport{
+		Total:   len(entries),
+		Summary: map[ImportStatus]int{},
+		Results: make([]ImportResult, 0, len(entries)),
+	}
+
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			report.add(ImportResult{File: entry.Name, Status: ImportSkipped, Reason: "tempo limite da importação atingido"})
+			continue
+		}
+		report.add(is.importEntry(user, entry))
+	}
+
+	return report, nil
+}
+
+func (is *ImportService) importEntry(user *models.User, entry *zip.File) ImportResult {
+	result := ImportResult{File: entry.Name}
+
+	if reason := checkArchiveEntry(entry); reason != "" {
+		result.Status = ImportInvalid
+		result.Reason = reason
+		return result
+	}
+
+	src, err := entry.Open()
+	if err != nil {
+		result.Status = ImportInvalid
+		result.Reason = "entrada ilegível: " + err.Error()
+		return result
+	}
+
+	// StageFile limita o que é efetivamente descompactado a MaxReplaySize,
+	// mesmo que o tamanho declarado no ZIP seja falso
+	staged, err := is.replayService.StageFile(src)
+	src.Close()
+	if err != nil {
+		result.Status = ImportInvalid
+		result.Reason = describeStageError(err)
+		return result
+	}
+	defer staged.Remove()
+
+	replay, err := is.replayService.CreateFromUpload(user, path.Base(entry.Name), staged, UploadMetadata{})
+	if err != nil {
+		var duplicateErr *DuplicateReplayError
+		if errors.As(err, &duplicateErr) {
+			result.Status = ImportDuplicate
+			result.Reason = "replay já enviado"
+			result.ReplayID = duplicateErr.Existing.ID
+			return result
+		}
+		result.Status = ImportFailed
+		result.Reason = err.Error()
+		return result
+	}
+
+	result.Status = ImportCreated
+	result.ReplayID = replay.ID
+
+	// Sem fila disponível o replay fica uploaded e é enfileirado pela varredura dos workers
+	if job, err := is.jobService.EnqueueReplay(replay); err == nil {
+		result.JobID = job.ID
+	}
+	return result
+}
+
+// checkArchiveEntry rejeita caminhos perigosos, entradas que não são .rofl e
+// entradas com tamanho ou compressão suspeitos. Retorna o motivo ou "".
+func checkArchiveEntry(entry *zip.File) string {
+	name := entry.Name
+	if name == "" || strings.Contains(name, "\\") || strings.HasPrefix(name, "/") ||
+		strings.Contains(name, ":") || strings.Contains(name, "\x00") {
+		return "caminho inválido"
+	}
+	for _, segment := range strings.Split(name, "/") {
+		if segment == ".." {
+			return "caminho inválido"
+		}
+	}
+	if !entry.Mode().IsRegular() {
+		return "entrada não é um arquivo comum"
+	}
+	if !strings.EqualFold(path.Ext(name), ".rofl") {
+		return "arquivo deve ter extensão .rofl"
+	}
+	if entry.Flags&0x1 != 0 {
+		return "entrada criptografada"
+	}
+
+	maxSize := uint64(config.AppConfig.MaxReplaySize)
+	if entry.UncompressedSize64 > maxSize {
+		return fmt.Sprintf("arquivo excede o tamanho máximo de %d MB", maxSize/(1024*1024))
+	}
+	if entry.CompressedSize64 > 0 && entry.UncompressedSize64/entry.CompressedSize64 > maxCompressionRatio {
+		return "taxa de compressão suspeita"
+	}
+
+	return ""
+}
+
+func describeStageError(err error) string {
+	var truncatedErr *rofl.TruncatedError
+	var corruptErr *rofl.CorruptError
+	switch {
+	case errors.Is(err, ErrReplayTooLarge):
+		return fmt.Sprintf("arquivo excede o tamanho máximo de %d MB", config.AppConfig.MaxReplaySize/(1024*1024))
+	case errors.Is(err, zip.ErrChecksum), errors.Is(err, zip.ErrAlgorithm), errors.Is(err, zip.ErrFormat):
+		return "entrada corrompida: " + err.Error()
+	case errors.Is(err, rofl.ErrInvalidMagic), errors.As(err, &truncatedErr), errors.As(err, &corruptErr):
+		return "arquivo .rofl inválido: " + err.Error()
+	default:
+		return err.Error()
+	}
+}
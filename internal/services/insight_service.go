@@ -0,0 +1,131 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+	"wardscore-api/internal/database"
+	"wardscore-api/internal/insights"
+	"wardscore-api/internal/models"
+
+	"gorm.io/gorm/clause"
+)
+
+var ErrInsightRuleNotFound = errors.New("regra de insight não encontrada")
+
+// Origem de uma regra do catálogo efetivo
+const (
+	InsightRuleSourceDefault = "default"
+	InsightRuleSourceCustom  = "custom"
+)
+
+// EffectiveInsightRule é uma regra do catálogo em uso e de onde ela veio
+type EffectiveInsightRule struct {
+	insights.Rule
+	Source string `json:"source"`
+}
+
+// InsightService mantém o catálogo de regras de insight e avalia as análises.
+//
+// O catálogo em uso é o embutido (insights.DefaultRules) com as regras
+// salvas aplicadas por cima: a mesma key substitui a embutida e keys novas
+// são adicionadas. Alterações valem para as próximas análises, sem deploy.
+type InsightService struct{}
+
+func NewInsightService() *InsightService {
+	return &InsightService{}
+}
+
+// Rules retorna o catálogo em uso
+func (is *InsightService) Rules() ([]EffectiveInsightRule, error) {
+	var rows []models.InsightRule
+	if err := database.DB.Order("key").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	saved := map[string]insights.Rule{}
+	for _, row := range rows {
+		var rule insights.Rule
+		if err := json.Unmarshal(row.Definition, &rule); err != nil {
+			continue
+		}
+		rule.Key = row.Key
+		saved[row.Key] = rule
+	}
+
+	var rules []EffectiveInsightRule
+	for _, rule := range insights.DefaultRules() {
+		if custom, ok := saved[rule.Key]; ok {
+			rules = append(rules, EffectiveInsightRule{Rule: custom, Source: InsightRuleSourceCustom})
+			delete(saved, rule.Key)
+			continue
+		}
+		rules = append(rules, EffectiveInsightRule{Rule: rule, Source: InsightRuleSourceDefault})
+	}
+
+	keys := make([]string, 0, len(saved))
+	for key := range saved {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		rules = append(rules, EffectiveInsightRule{Rule: saved[key], Source: InsightRuleSourceCustom})
+	}
+
+	return rules, nil
+}
+
+// Set salva uma regra, substituindo a salva ou embutida de mesma key
+func (is *InsightService) Set(rule insights.Rule) (*models.InsightRule, error) {
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	definition, err := json.Marshal(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	row := &models.InsightRule{
+		Key:        rule.Key,
+		Definition: definition,
+		UpdatedAt:  time.Now(),
+	}
+	result := database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"definition", "updated_at"}),
+	}).Create(row)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return row, nil
+}
+
+// Delete remove a regra salva; se houver embutida com a mesma key, ela volta a valer
+func (is *InsightService) Delete(key string) error {
+	result := database.DB.Where("key = ?", key).Delete(&models.InsightRule{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInsightRuleNotFound
+	}
+	return nil
+}
+
+// Evaluate avalia o catálogo em uso sobre a partida
+func (is *InsightService) Evaluate(ctx *insights.Context) ([]insights.Insight, []insights.Suggestion, error) {
+	effective, err := is.Rules()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rules := make([]insights.Rule, len(effective))
+	for i := range effective {
+		rules[i] = effective[i].Rule
+	}
+
+	found, suggestions := insights.Evaluate(rules, ctx)
+	return found, suggestions, nil
+}
@@ -0,0 +1,342 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+	"wardscore-api/internal/config"
+	"wardscore-api/internal/database"
+	"wardscore-api/internal/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrJobNotFound = errors.New("job não encontrado")
+
+// jobRetention é por quanto tempo jobs finalizados continuam consultáveis
+const jobRetention = 7 * 24 * time.Hour
+
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobRetrying  JobStatus = "retrying"
+	JobCompleted JobStatus = "completed"
+	JobFailed    JobStatus = "failed"
+)
+
+// Job é um processamento de replay na fila
+type Job struct {
+	ID         string     `json:"id"`
+	ReplayID   uint       `json:"replay_id"`
+	UserID     uint       `json:"user_id"`
+	Status     JobStatus  `json:"status"`
+	Attempts   int        `json:"attempts"`
+	Error      string     `json:"error,omitempty"`
+	RetryAt    *time.Time `json:"retry_at,omitempty"`
+	AnalysisID uint       `json:"analysis_id,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// IsFinished indica se o job já terminou (com sucesso ou não)
+func (j *Job) IsFinished() bool {
+	return j.Status == JobCompleted || j.Status == JobFailed
+}
+
+// JobService é a fila durável de processamento de replays no Redis.
+//
+// Chaves:
+//
+//	job:<id>             JSON do job
+//	jobs:queue           lista de IDs aguardando worker (entra pela esquerda, sai pela direita)
+//	jobs:active          zset de IDs em execução, com o prazo do lease como score
+//	jobs:delayed         zset de IDs aguardando nova tentativa, com o horário como score
+//	job_replay:<replay>  job em aberto do replay (evita enfileirar o mesmo replay duas vezes)
+//
+// O worker que pega um job renova o lease enquanto processa; se o processo
+// morrer, o job volta para a fila quando o lease expira.
+type JobService struct {
+	progress *ProgressService
+}
+
+func NewJobService() *JobService {
+	return &JobService{progress: NewProgressService()}
+}
+
+const (
+	jobQueueKey   = "jobs:queue"
+	jobActiveKey  = "jobs:active"
+	jobDelayedKey = "jobs:delayed"
+)
+
+// dequeueScript libera as novas tentativas vencidas e move o próximo job da
+// fila para jobs:active de forma atômica
+var dequeueScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[3], '-inf', ARGV[2], 'LIMIT', 0, 100)
+for _, id in ipairs(due) do
+	redis.call('ZREM', KEYS[3], id)
+	redis.call('LPUSH', KEYS[1], id)
+end
+local id = redis.call('RPOP', KEYS[1])
+if not id then
+	return false
+end
+redis.call('ZADD', KEYS[2], ARGV[1], id)
+return id
+`)
+
+// requeueExpiredScript devolve para a fila os jobs com lease vencido
+var requeueExpiredScript = redis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[2], '-inf', ARGV[1])
+for _, id in ipairs(ids) do
+	redis.call('ZREM', KEYS[2], id)
+	redis.call('RPUSH', KEYS[1], id)
+end
+return ids
+`)
+
+// EnqueueReplay coloca o replay na fila. Se já houver job em aberto para o
+// replay, retorna esse job.
+func (js *JobService) EnqueueReplay(replay *models.Replay) (*Job, error) {
+	job, _, err := js.enqueue(replay)
+	return job, err
+}
+
+func (js *JobService) enqueue(replay *models.Replay) (*Job, bool, error) {
+	ctx := context.Background()
+
+	jobID, err := randomToken(12)
+	if err != nil {
+		return nil, false, err
+	}
+
+	job := &Job{
+		ID:        jobID,
+		ReplayID:  replay.ID,
+		UserID:    replay.UserID,
+		Status:    JobQueued,
+		CreatedAt: time.Now(),
+	}
+
+	replayKey := fmt.Sprintf("job_replay:%d", replay.ID)
+	acquired, err := database.RedisClient.SetNX(ctx, replayKey, job.ID, 0).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	if !acquired {
+		existingID, err := database.RedisClient.Get(ctx, replayKey).Result()
+		if err == nil {
+			if existing, err := js.Get(existingID); err == nil && !existing.IsFinished() {
+				return existing, false, nil
+			}
+		}
+		// Job antigo sumiu ou já terminou: o replay pode ser enfileirado de novo
+		if err := database.RedisClient.Set(ctx, replayKey, job.ID, 0).Err(); err != nil {
+			return nil, false, err
+		}
+	}
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		return nil, false, err
+	}
+
+	pipe := database.RedisClient.TxPipeline()
+	pipe.Set(ctx, "job:"+job.ID, data, 0)
+	pipe.LPush(ctx, jobQueueKey, job.ID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		database.RedisClient.Del(ctx, replayKey)
+		return nil, false, err
+	}
+
+	js.progress.Publish(&ReplayProgress{
+		ReplayID: replay.ID,
+		Status:   models.StatusUploaded,
+		Stage:    "na fila",
+		JobID:    job.ID,
+		Attempts: replay.Attempts,
+	})
+
+	return job, true, nil
+}
+
+// EnqueuePending enfileira os replays com status uploaded que ainda não têm
+// job em aberto (uploads anteriores à fila ou enfileiramentos que falharam)
+func (js *JobService) EnqueuePending(replayService *ReplayService) (int, error) {
+	replays, err := replayService.GetPendingReplays()
+	if err != nil {
+		return 0, err
+	}
+
+	enqueued := 0
+	for i := range replays {
+		_, created, err := js.enqueue(&replays[i])
+		if err != nil {
+			return enqueued, err
+		}
+		if created {
+			enqueued++
+		}
+	}
+
+	return enqueued, nil
+}
+
+// Get busca job por ID
+func (js *JobService) Get(id string) (*Job, error) {
+	data, err := database.RedisClient.Get(context.Background(), "job:"+id).Result()
+	if err != nil {
+		return nil, ErrJobNotFound
+	}
+
+	var job Job
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		return nil, ErrJobNotFound
+	}
+	return &job, nil
+}
+
+// Dequeue pega o próximo job da fila e marca como running. Retorna nil sem
+// erro quando a fila está vazia.
+func (js *JobService) Dequeue(ctx context.Context) (*Job, error) {
+	now := time.Now()
+	deadline := now.Add(config.AppConfig.JobLease).Unix()
+	keys := []string{jobQueueKey, jobActiveKey, jobDelayedKey}
+	id, err := dequeueScript.Run(ctx, database.RedisClient, keys, deadline, now.Unix()).Text()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := js.Get(id)
+	if err != nil {
+		// Job sem dados (expirado ou removido): descarta
+		database.RedisClient.ZRem(ctx, jobActiveKey, id)
+		return nil, nil
+	}
+
+	job.Status = JobRunning
+	job.Attempts++
+	job.StartedAt = &now
+	job.RetryAt = nil
+	if err := js.save(job, 0); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Heartbeat renova o lease do job em execução
+func (js *JobService) Heartbeat(job *Job) error {
+	deadline := float64(time.Now().Add(config.AppConfig.JobLease).Unix())
+	return database.RedisClient.ZAddXX(context.Background(), jobActiveKey, redis.Z{Score: deadline, Member: job.ID}).Err()
+}
+
+// Complete marca o job como concluído
+func (js *JobService) Complete(job *Job, analysis *models.Analysis) error {
+	job.Status = JobCompleted
+	job.Error = ""
+	if analysis != nil {
+		job.AnalysisID = analysis.ID
+	}
+	return js.finish(job)
+}
+
+// Fail marca o job como falho
+func (js *JobService) Fail(job *Job, cause error) error {
+	job.Status = JobFailed
+	job.Error = cause.Error()
+	return js.finish(job)
+}
+
+// Retry agenda nova tentativa do job para retryAt
+func (js *JobService) Retry(job *Job, cause error, retryAt time.Time) error {
+	job.Status = JobRetrying
+	job.Error = cause.Error()
+	job.RetryAt = &retryAt
+	if err := js.save(job, 0); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	pipe := database.RedisClient.TxPipeline()
+	pipe.ZRem(ctx, jobActiveKey, job.ID)
+	pipe.ZAdd(ctx, jobDelayedKey, redis.Z{Score: float64(retryAt.Unix()), Member: job.ID})
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// RetryDelay é o backoff da tentativa informada (1 = primeira falha) para jobs
+func RetryDelay(attempt int) time.Duration {
+	return backoffDelay(attempt, config.AppConfig.JobRetryBaseDelay, config.AppConfig.JobRetryMaxDelay)
+}
+
+// backoffDelay é o backoff exponencial com jitter: base * 2^(attempt-1),
+// limitado a maxDelay, com metade fixa e metade aleatória
+func backoffDelay(attempt int, base, maxDelay time.Duration) time.Duration {
+	delay := maxDelay
+	if attempt < 1 {
+		attempt = 1
+	}
+	if attempt <= 30 {
+		if exp := base << (attempt - 1); exp > 0 && exp < delay {
+			delay = exp
+		}
+	}
+
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// RequeueExpired devolve para a fila os jobs cujo worker parou de renovar o lease
+func (js *JobService) RequeueExpired() (int, error) {
+	ctx := context.Background()
+	ids, err := requeueExpiredScript.Run(ctx, database.RedisClient, []string{jobQueueKey, jobActiveKey}, time.Now().Unix()).StringSlice()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		if job, err := js.Get(id); err == nil {
+			job.Status = JobQueued
+			js.save(job, 0)
+		}
+	}
+
+	return len(ids), nil
+}
+
+func (js *JobService) finish(job *Job) error {
+	ctx := context.Background()
+	now := time.Now()
+	job.FinishedAt = &now
+
+	if err := js.save(job, jobRetention); err != nil {
+		return err
+	}
+
+	pipe := database.RedisClient.TxPipeline()
+	pipe.ZRem(ctx, jobActiveKey, job.ID)
+	pipe.Del(ctx, fmt.Sprintf("job_replay:%d", job.ReplayID))
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (js *JobService) save(job *Job, ttl time.Duration) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return database.RedisClient.Set(context.Background(), "job:"+job.ID, data, ttl).Err()
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+	"wardscore-api/internal/database"
+	"wardscore-api/internal/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// progressTTL é por quanto tempo o último progresso publicado fica guardado
+const progressTTL = 24 * time.Hour
+
+// ProgressFunc recebe o andamento do processamento (etapa e percentual)
+type ProgressFunc func(stage string, percent int)
+
+// ReplayProgress é o documento de status do processamento de um replay,
+// enviado pelo SSE e pelo endpoint de polling
+type ReplayProgress struct {
+	ReplayID    uint                `json:"replay_id"`
+	Status      models.ReplayStatus `json:"status"`
+	Progress    int                 `json:"progress"`
+	Stage       string              `json:"stage,omitempty"`
+	JobID       string              `json:"job_id,omitempty"`
+	Attempts    int                 `json:"attempts"`
+	LastError   string              `json:"last_error,omitempty"`
+	NextRetryAt *time.Time          `json:"next_retry_at,omitempty"`
+	AnalysisID  uint                `json:"analysis_id,omitempty"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+}
+
+// IsFinal indica que o replay não muda mais de status sem ação externa
+func (p *ReplayProgress) IsFinal() bool {
+	return p.Status == models.StatusCompleted || p.Status == models.StatusDeadLetter
+}
+
+// ProgressService publica o andamento do processamento via Redis pub/sub,
+// para que API e workers possam rodar em processos diferentes.
+//
+// Chaves:
+//
+//	replay_progress:<id>  último documento publicado (TTL 24h)
+//	replay_events:<id>    canal pub/sub com cada atualização
+type ProgressService struct{}
+
+func NewProgressService() *ProgressService {
+	return &ProgressService{}
+}
+
+// FromReplay monta o documento de status a partir do estado do replay no banco
+func (ps *ProgressService) FromReplay(replay *models.Replay) *ReplayProgress {
+	progress := &ReplayProgress{
+		ReplayID:    replay.ID,
+		Status:      replay.Status,
+		Attempts:    replay.Attempts,
+		LastError:   replay.LastError,
+		NextRetryAt: replay.NextRetryAt,
+		UpdatedAt:   replay.UpdatedAt,
+	}
+	if replay.Status == models.StatusCompleted {
+		progress.Progress = 100
+	}
+	if replay.Analysis != nil {
+		progress.AnalysisID = replay.Analysis.ID
+	}
+	return progress
+}
+
+// Get retorna o status atual do replay: o banco define o status e o último
+// progresso publicado completa etapa, percentual e job
+func (ps *ProgressService) Get(replay *models.Replay) *ReplayProgress {
+	progress := ps.FromReplay(replay)
+
+	data, err := database.RedisClient.Get(context.Background(), progressKey(replay.ID)).Result()
+	if err != nil {
+		return progress
+	}
+
+	var published ReplayProgress
+	if json.Unmarshal([]byte(data), &published) != nil || published.Status != progress.Status {
+		return progress
+	}
+
+	progress.Progress = published.Progress
+	progress.Stage = published.Stage
+	progress.JobID = published.JobID
+	if progress.AnalysisID == 0 {
+		progress.AnalysisID = published.AnalysisID
+	}
+	if published.UpdatedAt.After(progress.UpdatedAt) {
+		progress.UpdatedAt = published.UpdatedAt
+	}
+	return progress
+}
+
+// Publish guarda o documento e avisa os inscritos do replay
+func (ps *ProgressService) Publish(progress *ReplayProgress) error {
+	progress.UpdatedAt = time.Now()
+
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	pipe := database.RedisClient.Pipeline()
+	pipe.Set(ctx, progressKey(progress.ReplayID), data, progressTTL)
+	pipe.Publish(ctx, eventsChannel(progress.ReplayID), data)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// Subscribe se inscreve nas atualizações do replay; feche o PubSub ao terminar
+func (ps *ProgressService) Subscribe(ctx context.Context, replayID uint) (*redis.PubSub, error) {
+	pubsub := database.RedisClient.Subscribe(ctx, eventsChannel(replayID))
+
+	// Aguarda a confirmação para não perder eventos publicados logo em seguida
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+	return pubsub, nil
+}
+
+func progressKey(replayID uint) string {
+	return fmt.Sprintf("replay_progress:%d", replayID)
+}
+
+func eventsChannel(replayID uint) string {
+	return fmt.Sprintf("replay_events:%d", replayID)
+}
@@ -1,11 +1,53 @@
 package services
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+	"wardscore-api/internal/config"
 	"wardscore-api/internal/database"
 	"wardscore-api/internal/models"
+	"wardscore-api/internal/rofl"
+	"wardscore-api/internal/storage"
+
+	"gorm.io/gorm"
+)
+
+var (
+    ErrReplayTooLarge      = errors.New("arquivo de replay excede o tamanho máximo")
+    ErrReplayNotFound      = errors.New("replay não encontrado")
+    ErrReplayNotDeadLetter = errors.New("replay não está em dead letter")
 )
 
+// StagedReplayFile é um upload gravado em arquivo temporário, com tamanho e
+// hash já calculados, aguardando ir para o storage junto com a linha do banco
+type StagedReplayFile struct {
+    TempPath    string
+    FileSize    int64
+    ContentHash string
+    ROFL        *rofl.File // cabeçalho e metadados já validados
+}
+
+// Remove apaga o arquivo temporário
+func (f *StagedReplayFile) Remove() {
+    if f != nil {
+        os.Remove(f.TempPath)
+    }
+}
+
+// UploadMetadata são os campos opcionais enviados junto com o arquivo
+type UploadMetadata struct {
+    GameID   string `json:"game_id"`
+    Champion string `json:"champion"`
+    Queue    string `json:"queue"`
+}
+
 type ReplayService struct{}
 
 func NewReplayService() *ReplayService {
@@ -15,9 +57,9 @@ func NewReplayService() *ReplayService {
 // GetByID busca replay por ID
 func (rs *ReplayService) GetByID(id uint) (*models.Replay, error) {
     var replay models.Replay
-    result := database.DB.Preload("User").Preload("Analysis").First(&replay, id)
+    result := database.DB.Preload("User").Preload("Analysis", LatestAnalyses).First(&replay, id)
     if result.Error != nil {
-        return nil, errors.New("replay não encontrado")
+        return nil, ErrReplayNotFound
     }
     return &replay, nil
 }
@@ -26,7 +68,7 @@ func (rs *ReplayService) GetByID(id uint) (*models.Replay, error) {
 func (rs *ReplayService) GetByUserID(userID uint) ([]models.Replay, error) {
     var replays []models.Replay
     result := database.DB.Where("user_id = ?", userID).
-        Preload("Analysis").
+        Preload("Analysis", LatestAnalyses).
         Order("created_at DESC").
         Find(&replays)
     
@@ -37,23 +79,228 @@ func (rs *ReplayService) GetByUserID(userID uint) ([]models.Replay, error) {
     return replays, nil
 }
 
-// Create cria novo replay
-func (rs *ReplayService) Create(replay *models.Replay) (*models.Replay, error) {
-    // Verificar se já existe replay com mesmo Match ID
-    var count int64
-    database.DB.Model(&models.Replay{}).Where("match_id = ?", replay.MatchID).Count(&count)
-    if count > 0 {
-        return nil, errors.New("replay com este Match ID já existe")
+// StageFile grava o conteúdo do replay em arquivo temporário sem carregá-lo
+// inteiro na memória, calculando tamanho e SHA-256 durante a cópia, e valida
+// o formato .rofl. Arquivos inválidos retornam os erros tipados do pacote rofl.
+func (rs *ReplayService) StageFile(src io.Reader) (*StagedReplayFile, error) {
+    tmp, err := os.CreateTemp("", "wardscore-upload-*.rofl")
+    if err != nil {
+        return nil, err
+    }
+    staged := &StagedReplayFile{TempPath: tmp.Name()}
+
+    hasher := sha256.New()
+    maxSize := config.AppConfig.MaxReplaySize
+    size, err := io.Copy(io.MultiWriter(tmp, hasher), io.LimitReader(src, maxSize+1))
+    closeErr := tmp.Close()
+    if err == nil {
+        err = closeErr
+    }
+    if err == nil && size > maxSize {
+        err = ErrReplayTooLarge
+    }
+    if err == nil && size == 0 {
+        err = errors.New("arquivo de replay vazio")
+    }
+    if err != nil {
+        staged.Remove()
+        return nil, err
+    }
+
+    staged.FileSize = size
+    staged.ContentHash = hex.EncodeToString(hasher.Sum(nil))
+
+    parsed, err := parseROFLFile(staged.TempPath)
+    if err != nil {
+        staged.Remove()
+        return nil, err
+    }
+    staged.ROFL = parsed
+
+    return staged, nil
+}
+
+// CreateFromUpload monta o replay a partir de um upload validado e o cria
+// (arquivo no storage + linha no banco)
+func (rs *ReplayService) CreateFromUpload(user *models.User, originalName string, staged *StagedReplayFile, meta UploadMetadata) (*models.Replay, error) {
+    // O Match ID vem do gameId gravado no próprio replay; o game_id enviado
+    // pelo cliente só é usado quando o arquivo não traz essa informação
+    gameID := staged.ROFL.GameID()
+    matchID := meta.GameID
+    if gameID != 0 {
+        matchID = fmt.Sprintf("%s_%d", user.Region, gameID)
+    }
+    if matchID == "" {
+        return nil, errors.New("replay sem ID de partida, informe game_id")
+    }
+
+    replay := &models.Replay{
+        UserID:       user.ID,
+        OriginalName: originalName,
+        MatchID:      matchID, // GameID vira MatchID
+        GameID:       gameID,
+        GameVersion:  staged.ROFL.Metadata.GameVersion,
+        Duration:     int(staged.ROFL.Duration().Seconds()),
+        Champion:     meta.Champion,
+        Queue:        meta.Queue,
+        Status:       models.StatusUploaded,
+    }
+
+    return rs.Create(replay, staged)
+}
+
+// Create grava o arquivo no storage e cria o replay. Uploads repetidos do
+// mesmo usuário são rejeitados; se outro usuário já enviou o mesmo arquivo
+// ou a mesma partida, o replay novo reaproveita o arquivo armazenado.
+func (rs *ReplayService) Create(replay *models.Replay, file *StagedReplayFile) (*models.Replay, error) {
+    if file != nil {
+        replay.ContentHash = file.ContentHash
+    }
+
+    if existing := rs.findDuplicate(replay.UserID, replay); existing != nil {
+        return nil, &DuplicateReplayError{Existing: existing}
+    }
+
+    ctx := context.Background()
+    storedNew := false
+    if file != nil {
+        if stored := rs.findStoredCopy(ctx, replay); stored != nil {
+            replay.FileName = stored.FileName
+            replay.FilePath = stored.FilePath
+            replay.FileSize = stored.FileSize
+            replay.ContentHash = stored.ContentHash
+        } else {
+            // Chave endereçada pelo conteúdo: arquivos idênticos compartilham o mesmo objeto
+            key := fmt.Sprintf("replays/%s.rofl", file.ContentHash)
+            if err := rs.putFile(ctx, key, file); err != nil {
+                return nil, fmt.Errorf("falha ao gravar arquivo: %w", err)
+            }
+            storedNew = true
+
+            replay.FileName = path.Base(key)
+            replay.FilePath = key
+            replay.FileSize = file.FileSize
+        }
     }
 
     result := database.DB.Create(replay)
     if result.Error != nil {
+        if storedNew {
+            rs.deleteFileIfUnused(ctx, database.DB, replay.FilePath, 0)
+        }
         return nil, result.Error
     }
 
     return replay, nil
 }
 
+// DuplicateReplayError indica que o usuário já enviou esse arquivo ou essa partida
+type DuplicateReplayError struct {
+    Existing *models.Replay
+}
+
+func (e *DuplicateReplayError) Error() string {
+    return fmt.Sprintf("replay já enviado (replay %d)", e.Existing.ID)
+}
+
+// findDuplicate busca replay do próprio usuário com o mesmo Match ID, o mesmo
+// conteúdo ou a mesma partida
+func (rs *ReplayService) findDuplicate(userID uint, replay *models.Replay) *models.Replay {
+    query := database.DB.Where("user_id = ?", userID)
+    match := database.DB.Where("match_id = ?", replay.MatchID)
+    if replay.ContentHash != "" {
+        match = match.Or("content_hash = ?", replay.ContentHash)
+    }
+    if replay.GameID != 0 {
+        match = match.Or("game_id = ? AND game_version = ?", replay.GameID, replay.GameVersion)
+    }
+
+    var existing models.Replay
+    if query.Where(match).First(&existing).Error != nil {
+        return nil
+    }
+    return &existing
+}
+
+// findStoredCopy busca replay de qualquer usuário com o mesmo conteúdo ou a
+// mesma partida (o .rofl traz as estatísticas dos 10 jogadores, então um único
+// arquivo serve a todos) cujo arquivo ainda esteja no storage
+func (rs *ReplayService) findStoredCopy(ctx context.Context, replay *models.Replay) *models.Replay {
+    query := database.DB.Where("file_path <> ''")
+    match := database.DB.Where("content_hash = ?", replay.ContentHash)
+    if replay.GameID != 0 {
+        match = match.Or("game_id = ? AND game_version = ?", replay.GameID, replay.GameVersion)
+    }
+
+    var candidates []models.Replay
+    if query.Where(match).Order("id").Limit(5).Find(&candidates).Error != nil {
+        return nil
+    }
+    for i := range candidates {
+        if _, err := storage.Store.Stat(ctx, candidates[i].FilePath); err == nil {
+            return &candidates[i]
+        }
+    }
+    return nil
+}
+
+// deleteFileIfUnused remove o arquivo do storage quando nenhum outro replay
+// (além de exceptID) o referencia
+func (rs *ReplayService) deleteFileIfUnused(ctx context.Context, tx *gorm.DB, filePath string, exceptID uint) error {
+    var refs int64
+    if err := tx.Model(&models.Replay{}).
+        Where("file_path = ? AND id <> ?", filePath, exceptID).
+        Count(&refs).Error; err != nil {
+        return err
+    }
+    if refs > 0 {
+        return nil
+    }
+
+    err := storage.Store.Delete(ctx, filePath)
+    if errors.Is(err, storage.ErrNotFound) {
+        return nil
+    }
+    return err
+}
+
+// OpenFile abre o arquivo do replay no storage
+func (rs *ReplayService) OpenFile(replay *models.Replay) (storage.Object, error) {
+    if replay.FilePath == "" {
+        return nil, errors.New("replay sem arquivo armazenado")
+    }
+    return storage.Store.Get(context.Background(), replay.FilePath)
+}
+
+// DownloadURL gera URL pré-assinada de curta duração para baixar o arquivo
+// direto do storage. Retorna storage.ErrPresignNotSupported no backend local.
+func (rs *ReplayService) DownloadURL(replay *models.Replay, contentDisposition string) (string, error) {
+    if replay.FilePath == "" {
+        return "", errors.New("replay sem arquivo armazenado")
+    }
+    return storage.Store.PresignedURL(context.Background(), replay.FilePath, config.AppConfig.DownloadURLTTL, contentDisposition)
+}
+
+func parseROFLFile(filePath string) (*rofl.File, error) {
+    f, err := os.Open(filePath)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+
+    return rofl.Parse(f)
+}
+
+func (rs *ReplayService) putFile(ctx context.Context, key string, file *StagedReplayFile) error {
+    f, err := os.Open(file.TempPath)
+    if err != nil {
+        return err
+    }
+    defer f.Close()
+
+    return storage.Store.Put(ctx, key, f, file.FileSize, "application/octet-stream")
+}
+
 // Update atualiza replay
 func (rs *ReplayService) Update(replay *models.Replay) (*models.Replay, error) {
     result := database.DB.Save(replay)
@@ -63,18 +310,28 @@ func (rs *ReplayService) Update(replay *models.Replay) (*models.Replay, error) {
     return replay, nil
 }
 
-// Delete remove replay
+// Delete remove o replay e, se nenhum outro replay o usa, seu arquivo no
+// storage; se o arquivo não puder ser removido a exclusão da linha é desfeita
 func (rs *ReplayService) Delete(id uint) error {
-    result := database.DB.Delete(&models.Replay{}, id)
-    if result.Error != nil {
-        return result.Error
-    }
-    
-    if result.RowsAffected == 0 {
+    var replay models.Replay
+    if database.DB.First(&replay, id).Error != nil {
         return errors.New("replay não encontrado")
     }
-    
-    return nil
+
+    return database.DB.Transaction(func(tx *gorm.DB) error {
+        if err := tx.Delete(&replay).Error; err != nil {
+            return err
+        }
+
+        // O arquivo pode ser compartilhado com replays de outros usuários
+        if replay.FilePath != "" {
+            if err := rs.deleteFileIfUnused(context.Background(), tx, replay.FilePath, replay.ID); err != nil {
+                return fmt.Errorf("falha ao remover arquivo: %w", err)
+            }
+        }
+
+        return nil
+    })
 }
 
 // GetPendingReplays busca replays para processar
@@ -91,6 +348,89 @@ func (rs *ReplayService) GetPendingReplays() ([]models.Replay, error) {
     return replays, nil
 }
 
+// StartAttempt conta mais uma tentativa de processamento do replay
+func (rs *ReplayService) StartAttempt(id uint) (*models.Replay, error) {
+    result := database.DB.Model(&models.Replay{}).Where("id = ?", id).
+        UpdateColumn("attempts", gorm.Expr("attempts + 1"))
+    if result.Error != nil {
+        return nil, result.Error
+    }
+    if result.RowsAffected == 0 {
+        return nil, ErrReplayNotFound
+    }
+
+    var replay models.Replay
+    if err := database.DB.First(&replay, id).Error; err != nil {
+        return nil, err
+    }
+    return &replay, nil
+}
+
+// RecordFailure registra o erro da tentativa. Falhas transitórias ganham nova
+// tentativa com backoff; falhas permanentes ou tentativas esgotadas levam o
+// replay para dead letter. Retorna o horário da próxima tentativa ou nil.
+func (rs *ReplayService) RecordFailure(replay *models.Replay, cause error, permanent bool) (*time.Time, error) {
+    if permanent || replay.Attempts >= config.AppConfig.JobMaxAttempts {
+        replay.MarkAsDeadLetter(cause)
+    } else {
+        replay.MarkAsRetrying(cause, time.Now().Add(RetryDelay(replay.Attempts)))
+    }
+
+    // Só os campos de tentativa: o processamento pode ter atualizado o resto da linha
+    err := database.DB.Model(&models.Replay{}).Where("id = ?", replay.ID).Updates(map[string]interface{}{
+        "status":        replay.Status,
+        "last_error":    replay.LastError,
+        "next_retry_at": replay.NextRetryAt,
+    }).Error
+    if err != nil {
+        return nil, err
+    }
+
+    return replay.NextRetryAt, nil
+}
+
+// GetDeadLetters lista replays em dead letter, os mais recentes primeiro
+func (rs *ReplayService) GetDeadLetters(page, limit int) ([]models.Replay, int64, error) {
+    var total int64
+    query := database.DB.Model(&models.Replay{}).Where("status = ?", models.StatusDeadLetter)
+    if err := query.Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    var replays []models.Replay
+    offset := (page - 1) * limit
+    result := query.Preload("User").
+        Order("updated_at DESC").
+        Offset(offset).Limit(limit).
+        Find(&replays)
+    if result.Error != nil {
+        return nil, 0, result.Error
+    }
+
+    return replays, total, nil
+}
+
+// ResetForRetry zera as tentativas de um replay em dead letter e o volta para
+// uploaded, pronto para ser enfileirado de novo
+func (rs *ReplayService) ResetForRetry(id uint) (*models.Replay, error) {
+    var replay models.Replay
+    if database.DB.First(&replay, id).Error != nil {
+        return nil, ErrReplayNotFound
+    }
+    if !replay.IsDeadLetter() {
+        return nil, ErrReplayNotDeadLetter
+    }
+
+    replay.Status = models.StatusUploaded
+    replay.Attempts = 0
+    replay.NextRetryAt = nil
+    if err := database.DB.Save(&replay).Error; err != nil {
+        return nil, err
+    }
+
+    return &replay, nil
+}
+
 // UpdateStatus atualiza status do replay
 func (rs *ReplayService) UpdateStatus(id uint, status models.ReplayStatus) error {
     result := database.DB.Model(&models.Replay{}).Where("id = ?", id).Update("status", status)
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"wardscore-api/internal/config"
+	"wardscore-api/internal/database"
+)
+
+// OAuthStateTTL é a validade do state gerado por AuthorizeURL
+const OAuthStateTTL = 10 * time.Minute
+
+// RiotTokenResponse é a resposta do endpoint de token do RSO
+type RiotTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	IDToken      string `json:"id_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	Scope        string `json:"scope"`
+}
+
+// RiotAccount representa a conta Riot retornada pelo account-v1
+type RiotAccount struct {
+	PUUID    string `json:"puuid"`
+	GameName string `json:"gameName"`
+	TagLine  string `json:"tagLine"`
+}
+
+// RiotID retorna o Riot ID no formato gameName#tagLine
+func (a *RiotAccount) RiotID() string {
+	return a.GameName + "#" + a.TagLine
+}
+
+// RiotAuthService implementa o fluxo authorization-code do Riot Sign-On
+type RiotAuthService struct {
+	httpClient *http.Client
+}
+
+func NewRiotAuthService() *RiotAuthService {
+	return &RiotAuthService{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// AuthorizeURL gera um state e monta a URL de autorização do RSO. O state
+// também é retornado para ser amarrado ao navegador (cookie) pelo controller.
+// Se linkUserID for diferente de zero, o callback vincula a conta Riot a esse usuário.
+func (ras *RiotAuthService) AuthorizeURL(linkUserID uint) (string, string, error) {
+	if config.AppConfig.RiotClientID == "" {
+		return "", "", errors.New("RIOT_CLIENT_ID não configurado")
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	state := hex.EncodeToString(buf)
+
+	cacheKey := fmt.Sprintf("oauth_state:%s", state)
+	if err := database.SetCache(cacheKey, strconv.FormatUint(uint64(linkUserID), 10), OAuthStateTTL); err != nil {
+		return "", "", err
+	}
+
+	params := url.Values{}
+	params.Set("client_id", config.AppConfig.RiotClientID)
+	params.Set("redirect_uri", config.AppConfig.RiotRedirectURI)
+	params.Set("response_type", "code")
+	params.Set("scope", "openid")
+	params.Set("state", state)
+
+	return config.AppConfig.RiotAuthorizeURL + "?" + params.Encode(), state, nil
+}
+
+// ConsumeState valida o state do callback (uso único) e retorna o usuário a vincular, se houver
+func (ras *RiotAuthService) ConsumeState(state string) (uint, error) {
+	if state == "" {
+		return 0, errors.New("state ausente")
+	}
+
+	// GETDEL garante o uso único mesmo com callbacks simultâneos
+	cacheKey := fmt.Sprintf("oauth_state:%s", state)
+	value, err := database.RedisClient.GetDel(context.Background(), cacheKey).Result()
+	if err != nil {
+		return 0, errors.New("state inválido ou expirado")
+	}
+
+	linkUserID, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, errors.New("state inválido ou expirado")
+	}
+
+	return uint(linkUserID), nil
+}
+
+// ExchangeCode troca o authorization code por tokens no endpoint de token
+func (ras *RiotAuthService) ExchangeCode(code string) (*RiotTokenResponse, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", config.AppConfig.RiotRedirectURI)
+
+	req, err := http.NewRequest(http.MethodPost, config.AppConfig.RiotTokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(config.AppConfig.RiotClientID, config.AppConfig.RiotClientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := ras.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao contatar endpoint de token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("endpoint de token retornou %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var token RiotTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("resposta de token inválida: %w", err)
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New("resposta de token sem access_token")
+	}
+
+	return &token, nil
+}
+
+// GetAccount resolve PUUID, gameName e tagLine da conta autenticada
+func (ras *RiotAuthService) GetAccount(accessToken string) (*RiotAccount, error) {
+	req, err := http.NewRequest(http.MethodGet, config.AppConfig.RiotAccountURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := ras.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao contatar account-v1: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("account-v1 retornou %d", resp.StatusCode)
+	}
+
+	var account RiotAccount
+	if err := json.NewDecoder(resp.Body).Decode(&account); err != nil {
+		return nil, fmt.Errorf("resposta de conta inválida: %w", err)
+	}
+	if account.PUUID == "" || account.GameName == "" || account.TagLine == "" {
+		return nil, errors.New("conta Riot incompleta")
+	}
+
+	return &account, nil
+}
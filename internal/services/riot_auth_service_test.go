@@ -0,0 +1,194 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+	"wardscore-api/internal/config"
+	"wardscore-api/internal/database"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// useTestRedis troca database.RedisClient por um Redis em memória durante o teste
+func useTestRedis(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+
+	previous := database.RedisClient
+	database.RedisClient = client
+	t.Cleanup(func() {
+		database.RedisClient = previous
+		client.Close()
+	})
+	return server
+}
+
+// useTestConfig aplica alterações em config.AppConfig e as desfaz no fim do teste
+func useTestConfig(t *testing.T, apply func(cfg *config.Config)) {
+	t.Helper()
+	previous := config.AppConfig
+	apply(&config.AppConfig)
+	t.Cleanup(func() { config.AppConfig = previous })
+}
+
+// fakeRSO simula os endpoints de token e de conta do Riot Sign-On
+type fakeRSO struct {
+	mu    sync.Mutex
+	codes map[string]string // authorization code -> access token (uso único)
+	forms []url.Values
+}
+
+func newFakeRSO(t *testing.T) *fakeRSO {
+	t.Helper()
+	fake := &fakeRSO{codes: map[string]string{"code-valido": "access-token"}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", fake.token)
+	mux.HandleFunc("/accounts/me", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(RiotAccount{PUUID: "puuid-1", GameName: "Sentinela", TagLine: "BR1"})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	useTestConfig(t, func(cfg *config.Config) {
+		cfg.RiotClientID = "wardscore"
+		cfg.RiotClientSecret = "segredo"
+		cfg.RiotRedirectURI = "http://localhost:8080/api/v1/auth/riot/callback"
+		cfg.RiotAuthorizeURL = server.URL + "/authorize"
+		cfg.RiotTokenURL = server.URL + "/token"
+		cfg.RiotAccountURL = server.URL + "/accounts/me"
+	})
+	return fake
+}
+
+func (f *fakeRSO) token(w http.ResponseWriter, r *http.Request) {
+	clientID, secret, ok := r.BasicAuth()
+	if r.Method != http.MethodPost || !ok || clientID != "wardscore" || secret != "segredo" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.forms = append(f.forms, r.PostForm)
+
+	accessToken, ok := f.codes[r.PostForm.Get("code")]
+	if !ok || r.PostForm.Get("grant_type") != "authorization_code" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant"}`))
+		return
+	}
+	delete(f.codes, r.PostForm.Get("code"))
+
+	json.NewEncoder(w).Encode(RiotTokenResponse{AccessToken: accessToken, TokenType: "Bearer", ExpiresIn: 3600})
+}
+
+func TestRiotAuthorizeURLAndConsumeState(t *testing.T) {
+	redisServer := useTestRedis(t)
+	newFakeRSO(t)
+	ras := NewRiotAuthService()
+
+	authorizeURL, state, err := ras.AuthorizeURL(42)
+	if err != nil {
+		t.Fatalf("AuthorizeURL: %v", err)
+	}
+	parsed, err := url.Parse(authorizeURL)
+	if err != nil {
+		t.Fatalf("URL inválida: %v", err)
+	}
+	query := parsed.Query()
+	if query.Get("state") != state || query.Get("client_id") != "wardscore" || query.Get("response_type") != "code" || query.Get("redirect_uri") != config.AppConfig.RiotRedirectURI {
+		t.Errorf("URL de autorização = %s", authorizeURL)
+	}
+	if ttl := redisServer.TTL("oauth_state:" + state); ttl != OAuthStateTTL {
+		t.Errorf("TTL do state = %v, esperado %v", ttl, OAuthStateTTL)
+	}
+
+	linkUserID, err := ras.ConsumeState(state)
+	if err != nil || linkUserID != 42 {
+		t.Fatalf("ConsumeState = %d, %v; esperado 42", linkUserID, err)
+	}
+	// O state vale uma única vez
+	if _, err := ras.ConsumeState(state); err == nil {
+		t.Errorf("state reutilizado aceito")
+	}
+	if _, err := ras.ConsumeState(""); err == nil {
+		t.Errorf("state vazio aceito")
+	}
+	if _, err := ras.ConsumeState("desconhecido"); err == nil {
+		t.Errorf("state desconhecido aceito")
+	}
+
+	// Login sem vínculo guarda o usuário zero
+	_, loginState, _ := ras.AuthorizeURL(0)
+	redisServer.FastForward(OAuthStateTTL + time.Second)
+	if _, err := ras.ConsumeState(loginState); err == nil {
+		t.Errorf("state expirado aceito")
+	}
+}
+
+func TestRiotAuthorizeURLRequiresClientID(t *testing.T) {
+	useTestRedis(t)
+	useTestConfig(t, func(cfg *config.Config) { cfg.RiotClientID = "" })
+
+	if _, _, err := NewRiotAuthService().AuthorizeURL(0); err == nil {
+		t.Errorf("AuthorizeURL sem RIOT_CLIENT_ID aceito")
+	}
+}
+
+func TestRiotExchangeCodeAndGetAccount(t *testing.T) {
+	fake := newFakeRSO(t)
+	ras := NewRiotAuthService()
+
+	token, err := ras.ExchangeCode("code-valido")
+	if err != nil {
+		t.Fatalf("ExchangeCode: %v", err)
+	}
+	if token.AccessToken != "access-token" {
+		t.Errorf("access token = %q", token.AccessToken)
+	}
+	form := fake.forms[0]
+	if form.Get("code") != "code-valido" || form.Get("redirect_uri") != config.AppConfig.RiotRedirectURI {
+		t.Errorf("formulário enviado = %v", form)
+	}
+
+	// O code é de uso único no servidor de autorização
+	if _, err := ras.ExchangeCode("code-valido"); err == nil {
+		t.Errorf("code reutilizado aceito")
+	}
+
+	account, err := ras.GetAccount(token.AccessToken)
+	if err != nil {
+		t.Fatalf("GetAccount: %v", err)
+	}
+	if account.PUUID != "puuid-1" || account.RiotID() != "Sentinela#BR1" {
+		t.Errorf("conta = %+v", account)
+	}
+	if _, err := ras.GetAccount("token-invalido"); err == nil {
+		t.Errorf("GetAccount com token inválido aceito")
+	}
+}
+
+func TestRiotExchangeCodeRejectsWrongClientSecret(t *testing.T) {
+	newFakeRSO(t)
+	useTestConfig(t, func(cfg *config.Config) { cfg.RiotClientSecret = "errado" })
+
+	if _, err := NewRiotAuthService().ExchangeCode("code-valido"); err == nil {
+		t.Errorf("ExchangeCode com segredo errado aceito")
+	}
+}
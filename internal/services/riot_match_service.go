@@ -0,0 +1,115 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"wardscore-api/internal/config"
+	"wardscore-api/internal/timeline"
+)
+
+// ErrRiotAPIKeyMissing indica que RIOT_API_KEY não foi configurada
+var ErrRiotAPIKeyMissing = errors.New("RIOT_API_KEY não configurada")
+
+// Roteamento regional do match-v5 por plataforma (prefixo do Match ID)
+var matchRegions = map[string]string{
+	"NA1": "americas", "BR1": "americas", "LA1": "americas", "LA2": "americas",
+	"EUW1": "europe", "EUN1": "europe", "TR1": "europe", "RU": "europe", "ME1": "europe",
+	"KR": "asia", "JP1": "asia",
+	"OC1": "sea", "PH2": "sea", "SG2": "sea", "TH2": "sea", "TW2": "sea", "VN2": "sea",
+}
+
+// Monstros do match-v5 que são objetivos da análise de setup de visão
+var eliteMonsters = map[string]string{
+	"DRAGON":       timeline.ObjectiveDragon,
+	"BARON_NASHOR": timeline.ObjectiveBaron,
+	"RIFTHERALD":   timeline.ObjectiveHerald,
+}
+
+// Limite defensivo para a resposta da timeline do match-v5
+const maxMatchTimelineLength = 32 * 1024 * 1024
+
+// RiotMatchService consulta a timeline de partidas (match-v5)
+type RiotMatchService struct {
+	httpClient *http.Client
+}
+
+func NewRiotMatchService() *RiotMatchService {
+	return &RiotMatchService{
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// GetObjectives busca os abates de dragão, barão e arauto da partida
+// (eventos ELITE_MONSTER_KILL). O match-v5 não informa os nascimentos, então
+// só abates são retornados.
+func (rms *RiotMatchService) GetObjectives(matchID string) ([]timeline.Objective, error) {
+	if config.AppConfig.RiotAPIKey == "" {
+		return nil, ErrRiotAPIKeyMissing
+	}
+
+	platform, _, ok := strings.Cut(matchID, "_")
+	region, known := matchRegions[strings.ToUpper(platform)]
+	if !ok || !known {
+		return nil, fmt.Errorf("match ID sem plataforma conhecida: %s", matchID)
+	}
+
+	endpoint := strings.NewReplacer(
+		"{region}", region,
+		"{match_id}", url.PathEscape(matchID),
+	).Replace(config.AppConfig.RiotMatchTimelineURL)
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Riot-Token", config.AppConfig.RiotAPIKey)
+
+	resp, err := rms.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao buscar timeline da partida: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("match-v5 respondeu %d para %s", resp.StatusCode, matchID)
+	}
+
+	var body struct {
+		Info struct {
+			Frames []struct {
+				Events []struct {
+					Type         string `json:"type"`
+					Timestamp    int64  `json:"timestamp"`
+					MonsterType  string `json:"monsterType"`
+					KillerTeamID int    `json:"killerTeamId"`
+				} `json:"events"`
+			} `json:"frames"`
+		} `json:"info"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxMatchTimelineLength)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("timeline da partida inválida: %w", err)
+	}
+
+	objectives := []timeline.Objective{}
+	for _, frame := range body.Info.Frames {
+		for _, event := range frame.Events {
+			objective, ok := eliteMonsters[event.MonsterType]
+			if event.Type != "ELITE_MONSTER_KILL" || !ok {
+				continue
+			}
+			killed := timeline.Objective{Type: objective, Event: timeline.ObjectiveKill, At: event.Timestamp}
+			if event.KillerTeamID == 100 || event.KillerTeamID == 200 {
+				killed.Team = strconv.Itoa(event.KillerTeamID)
+			}
+			objectives = append(objectives, killed)
+		}
+	}
+	return objectives, nil
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"wardscore-api/internal/config"
+	"wardscore-api/internal/timeline"
+)
+
+const matchTimelineFixture = `{
+  "info": {
+    "frames": [
+      {"events": [
+        {"type": "WARD_PLACED", "timestamp": 61000, "wardType": "YELLOW_TRINKET"},
+        {"type": "ELITE_MONSTER_KILL", "timestamp": 365000, "monsterType": "HORDE", "killerTeamId": 100}
+      ]},
+      {"events": [
+        {"type": "ELITE_MONSTER_KILL", "timestamp": 618000, "monsterType": "DRAGON", "monsterSubType": "FIRE_DRAGON", "killerTeamId": 200},
+        {"type": "ELITE_MONSTER_KILL", "timestamp": 840000, "monsterType": "RIFTHERALD", "killerTeamId": 100}
+      ]},
+      {"events": [
+        {"type": "ELITE_MONSTER_KILL", "timestamp": 1512000, "monsterType": "BARON_NASHOR", "killerTeamId": 100}
+      ]}
+    ]
+  }
+}`
+
+func TestRiotMatchServiceGetObjectives(t *testing.T) {
+	var gotPath, gotToken string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotToken = r.Header.Get("X-Riot-Token")
+		w.Write([]byte(matchTimelineFixture))
+	}))
+	defer server.Close()
+
+	useTestConfig(t, func(cfg *config.Config) {
+		cfg.RiotAPIKey = "RGAPI-teste"
+		cfg.RiotMatchTimelineURL = server.URL + "/{region}/lol/match/v5/matches/{match_id}/timeline"
+	})
+
+	objectives, err := NewRiotMatchService().GetObjectives("BR1_2912345678")
+	if err != nil {
+		t.Fatalf("GetObjectives: %v", err)
+	}
+	if gotPath != "/americas/lol/match/v5/matches/BR1_2912345678/timeline" {
+		t.Errorf("caminho = %s", gotPath)
+	}
+	if gotToken != "RGAPI-teste" {
+		t.Errorf("X-Riot-Token = %q", gotToken)
+	}
+
+	want := []timeline.Objective{
+		{Type: timeline.ObjectiveDragon, Event: timeline.ObjectiveKill, Team: "200", At: 618000},
+		{Type: timeline.ObjectiveHerald, Event: timeline.ObjectiveKill, Team: "100", At: 840000},
+		{Type: timeline.ObjectiveBaron, Event: timeline.ObjectiveKill, Team: "100", At: 1512000},
+	}
+	if len(objectives) != len(want) {
+		t.Fatalf("objetivos = %+v, esperado %+v", objectives, want)
+	}
+	for i := range want {
+		if objectives[i] != want[i] {
+			t.Errorf("objetivo %d = %+v, esperado %+v", i, objectives[i], want[i])
+		}
+	}
+}
+
+func TestRiotMatchServiceGetObjectivesErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	useTestConfig(t, func(cfg *config.Config) { cfg.RiotMatchTimelineURL = server.URL + "/{region}/{match_id}" })
+
+	config.AppConfig.RiotAPIKey = ""
+	if _, err := NewRiotMatchService().GetObjectives("BR1_1"); !errors.Is(err, ErrRiotAPIKeyMissing) {
+		t.Errorf("sem chave: erro = %v, esperado ErrRiotAPIKeyMissing", err)
+	}
+
+	config.AppConfig.RiotAPIKey = "RGAPI-teste"
+	for _, matchID := range []string{"2912345678", "XX9_1", ""} {
+		if _, err := NewRiotMatchService().GetObjectives(matchID); err == nil {
+			t.Errorf("match ID %q aceito sem plataforma conhecida", matchID)
+		}
+	}
+	if _, err := NewRiotMatchService().GetObjectives("EUW1_1"); err == nil {
+		t.Errorf("resposta 404 aceita")
+	}
+}
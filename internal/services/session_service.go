@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"wardscore-api/internal/config"
+	"wardscore-api/internal/database"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("refresh token inválido ou expirado")
+	ErrRefreshTokenReused  = errors.New("refresh token reutilizado, sessão revogada")
+	ErrSessionNotFound     = errors.New("sessão não encontrada")
+)
+
+// Session representa um dispositivo logado (uma família de refresh tokens)
+type Session struct {
+	ID         string    `json:"id"`
+	UserID     uint      `json:"user_id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+
+	// Hash do refresh token vigente; tokens anteriores ficam em refresh_used:<hash>
+	TokenHash string `json:"token_hash,omitempty"`
+}
+
+// SessionService guarda sessões e refresh tokens no Redis, compartilhado
+// entre todas as instâncias da API para que a revogação seja imediata.
+//
+// Chaves:
+//
+//	session:<sid>          JSON da sessão (TTL = validade do refresh token)
+//	user_sessions:<uid>    set com os IDs de sessão do usuário
+//	refresh:<hash>         sid do refresh token vigente
+//	refresh_used:<hash>    sid de refresh tokens já rotacionados (detecção de reuso)
+type SessionService struct{}
+
+func NewSessionService() *SessionService {
+	return &SessionService{}
+}
+
+// Create abre uma nova sessão e retorna o refresh token em texto puro
+func (ss *SessionService) Create(userID uint, userAgent, ip string) (*Session, string, error) {
+	sessionID, err := randomToken(16)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	session := &Session{
+		ID:         sessionID,
+		UserID:     userID,
+		UserAgent:  userAgent,
+		IP:         ip,
+		CreatedAt:  now,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(config.AppConfig.RefreshTokenExpiresIn),
+	}
+
+	refreshToken, err := ss.issueRefreshToken(session)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return session, refreshToken, nil
+}
+
+// Rotate troca um refresh token válido por um novo. Apresentar um token já
+// rotacionado revoga a sessão inteira (indício de token vazado).
+func (ss *SessionService) Rotate(refreshToken, userAgent, ip string) (*Session, string, error) {
+	ctx := context.Background()
+	hash := hashToken(refreshToken)
+
+	// GETDEL garante que só uma requisição concorrente consegue rotacionar o token
+	sessionID, err := database.RedisClient.GetDel(ctx, "refresh:"+hash).Result()
+	if err == redis.Nil {
+		if usedSessionID, usedErr := database.RedisClient.Get(ctx, "refresh_used:"+hash).Result(); usedErr == nil {
+			ss.Revoke(usedSessionID)
+			return nil, "", ErrRefreshTokenReused
+		}
+		return nil, "", ErrRefreshTokenInvalid
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	session, err := ss.Get(sessionID)
+	if err != nil || session.TokenHash != hash {
+		return nil, "", ErrRefreshTokenInvalid
+	}
+
+	ttl := time.Until(session.ExpiresAt)
+	if ttl <= 0 {
+		ss.Revoke(sessionID)
+		return nil, "", ErrRefreshTokenInvalid
+	}
+	database.RedisClient.Set(ctx, "refresh_used:"+hash, sessionID, ttl)
+
+	session.LastUsedAt = time.Now()
+	session.UserAgent = userAgent
+	session.IP = ip
+
+	newToken, err := ss.issueRefreshToken(session)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return session, newToken, nil
+}
+
+// Get busca sessão ativa por ID
+func (ss *SessionService) Get(sessionID string) (*Session, error) {
+	data, err := database.RedisClient.Get(context.Background(), "session:"+sessionID).Result()
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+
+	var session Session
+	if err := json.Unmarshal([]byte(data), &session); err != nil {
+		return nil, ErrSessionNotFound
+	}
+
+	return &session, nil
+}
+
+// IsActive verifica se a sessão existe e pertence ao usuário
+func (ss *SessionService) IsActive(sessionID string, userID uint) bool {
+	session, err := ss.Get(sessionID)
+	return err == nil && session.UserID == userID
+}
+
+// ListByUser lista as sessões ativas do usuário, removendo as expiradas do índice
+func (ss *SessionService) ListByUser(userID uint) ([]Session, error) {
+	ctx := context.Background()
+	indexKey := fmt.Sprintf("user_sessions:%d", userID)
+
+	sessionIDs, err := database.RedisClient.SMembers(ctx, indexKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := []Session{}
+	for _, sessionID := range sessionIDs {
+		session, err := ss.Get(sessionID)
+		if err != nil {
+			database.RedisClient.SRem(ctx, indexKey, sessionID)
+			continue
+		}
+		session.TokenHash = ""
+		sessions = append(sessions, *session)
+	}
+
+	return sessions, nil
+}
+
+// RevokeForUser revoga uma sessão do usuário
+func (ss *SessionService) RevokeForUser(userID uint, sessionID string) error {
+	if !ss.IsActive(sessionID, userID) {
+		return ErrSessionNotFound
+	}
+	return ss.Revoke(sessionID)
+}
+
+// RevokeAllForUser revoga todas as sessões do usuário, exceto keepSessionID (se informado)
+func (ss *SessionService) RevokeAllForUser(userID uint, keepSessionID string) (int, error) {
+	sessionIDs, err := database.RedisClient.SMembers(context.Background(), fmt.Sprintf("user_sessions:%d", userID)).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	revoked := 0
+	for _, sessionID := range sessionIDs {
+		if sessionID == keepSessionID {
+			continue
+		}
+		if err := ss.Revoke(sessionID); err == nil {
+			revoked++
+		}
+	}
+
+	return revoked, nil
+}
+
+// Revoke remove a sessão e seu refresh token vigente
+func (ss *SessionService) Revoke(sessionID string) error {
+	session, err := ss.Get(sessionID)
+	if err != nil {
+		return ErrSessionNotFound
+	}
+
+	ctx := context.Background()
+	pipe := database.RedisClient.TxPipeline()
+	pipe.Del(ctx, "session:"+sessionID)
+	pipe.Del(ctx, "refresh:"+session.TokenHash)
+	pipe.SRem(ctx, fmt.Sprintf("user_sessions:%d", session.UserID), sessionID)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// issueRefreshToken gera novo refresh token para a sessão e persiste tudo
+func (ss *SessionService) issueRefreshToken(session *Session) (string, error) {
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	session.TokenHash = hashToken(refreshToken)
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return "", err
+	}
+
+	ctx := context.Background()
+	ttl := time.Until(session.ExpiresAt)
+	indexKey := fmt.Sprintf("user_sessions:%d", session.UserID)
+
+	pipe := database.RedisClient.TxPipeline()
+	pipe.Set(ctx, "session:"+session.ID, data, ttl)
+	pipe.Set(ctx, "refresh:"+session.TokenHash, session.ID, ttl)
+	pipe.SAdd(ctx, indexKey, session.ID)
+	pipe.Expire(ctx, indexKey, config.AppConfig.RefreshTokenExpiresIn)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return "", err
+	}
+
+	return refreshToken, nil
+}
+
+func randomToken(size int) (string, error) {
+	buf, err := randomBytes(size)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func randomBytes(size int) ([]byte, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
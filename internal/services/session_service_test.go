@@ -0,0 +1,108 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"wardscore-api/internal/config"
+)
+
+func newTestSessionService(t *testing.T) *SessionService {
+	t.Helper()
+	useTestRedis(t)
+	useTestConfig(t, func(cfg *config.Config) { cfg.RefreshTokenExpiresIn = 24 * time.Hour })
+	return NewSessionService()
+}
+
+func TestSessionRotate(t *testing.T) {
+	ss := newTestSessionService(t)
+
+	session, refreshToken, err := ss.Create(7, "firefox", "10.0.0.1")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if !ss.IsActive(session.ID, 7) || ss.IsActive(session.ID, 8) {
+		t.Errorf("sessão não pertence só ao usuário 7")
+	}
+
+	rotated, newToken, err := ss.Rotate(refreshToken, "chrome", "10.0.0.2")
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	if rotated.ID != session.ID || newToken == refreshToken {
+		t.Errorf("rotação trocou a sessão (%s → %s) ou manteve o token", session.ID, rotated.ID)
+	}
+	if rotated.UserAgent != "chrome" || rotated.IP != "10.0.0.2" {
+		t.Errorf("sessão rotacionada = %+v", rotated)
+	}
+
+	// O token novo também pode ser rotacionado
+	if _, _, err := ss.Rotate(newToken, "chrome", "10.0.0.2"); err != nil {
+		t.Errorf("Rotate do token novo: %v", err)
+	}
+}
+
+func TestSessionRotateDetectsReuse(t *testing.T) {
+	ss := newTestSessionService(t)
+
+	session, refreshToken, _ := ss.Create(7, "firefox", "10.0.0.1")
+	_, newToken, err := ss.Rotate(refreshToken, "firefox", "10.0.0.1")
+	if err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+
+	// Reapresentar o token já trocado revoga a sessão inteira
+	if _, _, err := ss.Rotate(refreshToken, "curl", "203.0.113.9"); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("reuso: erro = %v, esperado ErrRefreshTokenReused", err)
+	}
+	if _, err := ss.Get(session.ID); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("sessão continua ativa após reuso")
+	}
+	if _, _, err := ss.Rotate(newToken, "firefox", "10.0.0.1"); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Errorf("token vigente após reuso: erro = %v, esperado ErrRefreshTokenInvalid", err)
+	}
+
+	if _, _, err := ss.Rotate("desconhecido", "", ""); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Errorf("token desconhecido: erro = %v, esperado ErrRefreshTokenInvalid", err)
+	}
+}
+
+func TestSessionRevoke(t *testing.T) {
+	ss := newTestSessionService(t)
+
+	current, _, _ := ss.Create(7, "firefox", "10.0.0.1")
+	other, otherToken, _ := ss.Create(7, "celular", "10.0.0.3")
+	ss.Create(8, "firefox", "10.0.0.4")
+
+	sessions, err := ss.ListByUser(7)
+	if err != nil || len(sessions) != 2 {
+		t.Fatalf("ListByUser = %d sessões (%v), esperado 2", len(sessions), err)
+	}
+	for _, session := range sessions {
+		if session.TokenHash != "" {
+			t.Errorf("ListByUser expôs o hash do token")
+		}
+	}
+
+	if err := ss.RevokeForUser(8, other.ID); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("revogar sessão de outro usuário: erro = %v, esperado ErrSessionNotFound", err)
+	}
+	if err := ss.RevokeForUser(7, other.ID); err != nil {
+		t.Fatalf("RevokeForUser: %v", err)
+	}
+	if _, _, err := ss.Rotate(otherToken, "", ""); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Errorf("token da sessão revogada: erro = %v, esperado ErrRefreshTokenInvalid", err)
+	}
+
+	ss.Create(7, "tablet", "10.0.0.5")
+	revoked, err := ss.RevokeAllForUser(7, current.ID)
+	if err != nil || revoked != 1 {
+		t.Errorf("RevokeAllForUser = %d (%v), esperado 1", revoked, err)
+	}
+	if sessions, _ := ss.ListByUser(7); len(sessions) != 1 || sessions[0].ID != current.ID {
+		t.Errorf("sessões restantes = %v, esperado só a atual", sessions)
+	}
+	if sessions, _ := ss.ListByUser(8); len(sessions) != 1 {
+		t.Errorf("sessões do usuário 8 = %d, esperado 1", len(sessions))
+	}
+}
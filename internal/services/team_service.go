@@ -0,0 +1,158 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"wardscore-api/internal/database"
+	"wardscore-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrTeamNotFound       = errors.New("time não encontrado")
+	ErrTeamForbidden      = errors.New("apenas o dono do time pode fazer isso")
+	ErrTeamMemberExists   = errors.New("usuário já é membro do time")
+	ErrTeamMemberNotFound = errors.New("membro não encontrado")
+	ErrTeamOwnerLeave     = errors.New("o dono não pode sair do time; remova o time")
+)
+
+// TeamService gerencia times e seus membros. O dono cria o time, adiciona e
+// remove membros e é o único que cria webhooks do time.
+type TeamService struct{}
+
+func NewTeamService() *TeamService {
+	return &TeamService{}
+}
+
+// Create cria o time com o usuário como dono (e primeiro membro)
+func (ts *TeamService) Create(ownerID uint, name string) (*models.Team, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("nome do time é obrigatório")
+	}
+
+	team := &models.Team{Name: name, OwnerID: ownerID}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(team).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.TeamMember{TeamID: team.ID, UserID: ownerID, Role: models.TeamRoleOwner}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ts.Get(ownerID, team.ID)
+}
+
+// GetByUserID lista os times dos quais o usuário é membro
+func (ts *TeamService) GetByUserID(userID uint) ([]models.Team, error) {
+	var teams []models.Team
+	result := database.DB.
+		Where("id IN (?)", memberTeamIDs(userID)).
+		Order("created_at DESC").
+		Find(&teams)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return teams, nil
+}
+
+// Get busca um time do qual o usuário é membro, com os membros
+func (ts *TeamService) Get(userID, id uint) (*models.Team, error) {
+	var team models.Team
+	result := database.DB.
+		Preload("Members", func(db *gorm.DB) *gorm.DB { return db.Order("created_at") }).
+		Preload("Members.User").
+		Where("id = ? AND id IN (?)", id, memberTeamIDs(userID)).
+		First(&team)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrTeamNotFound
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &team, nil
+}
+
+// AddMember adiciona um usuário ao time (só o dono)
+func (ts *TeamService) AddMember(ownerID, teamID, userID uint) (*models.TeamMember, error) {
+	if _, err := ts.getOwned(ownerID, teamID); err != nil {
+		return nil, err
+	}
+
+	member := &models.TeamMember{TeamID: teamID, UserID: userID, Role: models.TeamRoleMember}
+	result := database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(member)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrTeamMemberExists
+	}
+
+	return member, nil
+}
+
+// RemoveMember tira um membro do time. O dono remove qualquer membro; os
+// demais só podem sair (remover a si mesmos).
+func (ts *TeamService) RemoveMember(userID, teamID, memberID uint) error {
+	team, err := ts.Get(userID, teamID)
+	if err != nil {
+		return err
+	}
+	if memberID == team.OwnerID {
+		return ErrTeamOwnerLeave
+	}
+	if userID != team.OwnerID && userID != memberID {
+		return ErrTeamForbidden
+	}
+
+	result := database.DB.Where("team_id = ? AND user_id = ?", teamID, memberID).Delete(&models.TeamMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTeamMemberNotFound
+	}
+
+	return nil
+}
+
+// Delete remove o time (só o dono). Os webhooks do time são desativados.
+func (ts *TeamService) Delete(ownerID, id uint) error {
+	if _, err := ts.getOwned(ownerID, id); err != nil {
+		return err
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Webhook{}).Where("team_id = ?", id).Update("active", false).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("team_id = ?", id).Delete(&models.TeamMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Team{}, id).Error
+	})
+}
+
+// getOwned busca o time exigindo que o usuário seja o dono
+func (ts *TeamService) getOwned(ownerID, id uint) (*models.Team, error) {
+	team, err := ts.Get(ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	if team.OwnerID != ownerID {
+		return nil, ErrTeamForbidden
+	}
+	return team, nil
+}
+
+// memberTeamIDs é a subconsulta dos times dos quais o usuário é membro
+func memberTeamIDs(userID uint) *gorm.DB {
+	return database.DB.Model(&models.TeamMember{}).Select("team_id").Where("user_id = ?", userID)
+}
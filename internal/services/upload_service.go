@@ -0,0 +1,427 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+	"wardscore-api/internal/config"
+	"wardscore-api/internal/database"
+	"wardscore-api/internal/models"
+	"wardscore-api/internal/storage"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	ErrUploadNotFound      = errors.New("sessão de upload não encontrada ou expirada")
+	ErrUploadIncomplete    = errors.New("upload incompleto, há chunks faltando")
+	ErrUploadChecksum      = errors.New("checksum do arquivo montado não confere")
+	ErrUploadInProgress    = errors.New("upload já está sendo finalizado")
+	ErrChunkOutOfRange     = errors.New("chunk fora do intervalo do arquivo")
+	ErrChunkSizeMismatch   = errors.New("tamanho do chunk não confere")
+	ErrChunkChecksum       = errors.New("checksum do chunk não confere")
+	ErrChunkOffsetMismatch = errors.New("offset não corresponde ao índice do chunk")
+)
+
+// UploadSession é uma sessão de upload resumível guardada no Redis
+type UploadSession struct {
+	ID           string         `json:"id"`
+	UserID       uint           `json:"user_id"`
+	OriginalName string         `json:"original_name"`
+	FileSize     int64          `json:"file_size"`
+	Checksum     string         `json:"checksum"` // SHA-256 hex do arquivo completo
+	ChunkSize    int64          `json:"chunk_size"`
+	Metadata     UploadMetadata `json:"metadata"`
+	CreatedAt    time.Time      `json:"created_at"`
+	ExpiresAt    time.Time      `json:"expires_at"`
+}
+
+// TotalChunks retorna quantos chunks compõem o arquivo
+func (s *UploadSession) TotalChunks() int {
+	return int((s.FileSize + s.ChunkSize - 1) / s.ChunkSize)
+}
+
+// ChunkRange retorna offset e tamanho esperados do chunk
+func (s *UploadSession) ChunkRange(index int) (int64, int64) {
+	offset := int64(index) * s.ChunkSize
+	length := s.ChunkSize
+	if offset+length > s.FileSize {
+		length = s.FileSize - offset
+	}
+	return offset, length
+}
+
+// ByteRange é um intervalo [Start, End] inclusivo de bytes recebidos
+type ByteRange struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+}
+
+// UploadStatus descreve o progresso do upload
+type UploadStatus struct {
+	Session        *UploadSession `json:"session"`
+	TotalChunks    int            `json:"total_chunks"`
+	ReceivedChunks []int          `json:"received_chunks"`
+	MissingChunks  []int          `json:"missing_chunks"`
+	ReceivedRanges []ByteRange    `json:"received_ranges"`
+	ReceivedBytes  int64          `json:"received_bytes"`
+	Complete       bool           `json:"complete"`
+}
+
+// UploadService implementa uploads resumíveis em chunks.
+//
+// A sessão fica no Redis e os chunks no storage de replays, então qualquer
+// instância da API pode receber qualquer chunk. Chaves:
+//
+//	upload:<id>          JSON da sessão
+//	upload:<id>:chunks   set com os índices recebidos
+//	upload:<id>:lock     trava da finalização
+//	uploads:expiry       sorted set (score = expiração) usado pela limpeza
+//	uploads/<id>/<n>     objeto do chunk n no storage
+type UploadService struct {
+	replayService *ReplayService
+}
+
+func NewUploadService(replayService *ReplayService) *UploadService {
+	return &UploadService{replayService: replayService}
+}
+
+// Create abre uma sessão de upload
+func (us *UploadService) Create(userID uint, originalName string, fileSize int64, checksum string, meta UploadMetadata) (*UploadSession, error) {
+	if fileSize <= 0 {
+		return nil, errors.New("file_size deve ser maior que zero")
+	}
+	if fileSize > config.AppConfig.MaxReplaySize {
+		return nil, ErrReplayTooLarge
+	}
+
+	checksum = strings.ToLower(checksum)
+	if decoded, err := hex.DecodeString(checksum); err != nil || len(decoded) != sha256.Size {
+		return nil, errors.New("checksum deve ser SHA-256 em hexadecimal")
+	}
+
+	uploadID, err := randomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &UploadSession{
+		ID:           uploadID,
+		UserID:       userID,
+		OriginalName: originalName,
+		FileSize:     fileSize,
+		Checksum:     checksum,
+		ChunkSize:    config.AppConfig.UploadChunkSize,
+		Metadata:     meta,
+		CreatedAt:    now,
+	}
+
+	if err := us.save(session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// Get busca a sessão do usuário
+func (us *UploadService) Get(userID uint, uploadID string) (*UploadSession, error) {
+	data, err := database.RedisClient.Get(context.Background(), "upload:"+uploadID).Result()
+	if err != nil {
+		return nil, ErrUploadNotFound
+	}
+
+	var session UploadSession
+	if err := json.Unmarshal([]byte(data), &session); err != nil {
+		return nil, ErrUploadNotFound
+	}
+
+	if session.UserID != userID || time.Now().After(session.ExpiresAt) {
+		return nil, ErrUploadNotFound
+	}
+
+	return &session, nil
+}
+
+// PutChunk grava um chunk. O chunk pode ser reenviado (sobrescreve o anterior).
+// offset < 0 dispensa a checagem de offset; checksum vazio dispensa a de conteúdo.
+func (us *UploadService) PutChunk(session *UploadSession, index int, offset int64, body io.Reader, checksum string) error {
+	if index < 0 || index >= session.TotalChunks() {
+		return ErrChunkOutOfRange
+	}
+
+	expectedOffset, expectedLength := session.ChunkRange(index)
+	if offset >= 0 && offset != expectedOffset {
+		return ErrChunkOffsetMismatch
+	}
+
+	// Chunks são pequenos (UPLOAD_CHUNK_SIZE_MB), então cabem em memória
+	data, err := io.ReadAll(io.LimitReader(body, expectedLength+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != expectedLength {
+		return ErrChunkSizeMismatch
+	}
+
+	if checksum != "" {
+		sum := sha256.Sum256(data)
+		if !strings.EqualFold(hex.EncodeToString(sum[:]), checksum) {
+			return ErrChunkChecksum
+		}
+	}
+
+	ctx := context.Background()
+	if err := storage.Store.Put(ctx, chunkKey(session.ID, index), bytes.NewReader(data), expectedLength, "application/octet-stream"); err != nil {
+		return err
+	}
+
+	if err := database.RedisClient.SAdd(ctx, "upload:"+session.ID+":chunks", index).Err(); err != nil {
+		return err
+	}
+
+	// Atividade renova a validade da sessão
+	return us.save(session)
+}
+
+// Status calcula chunks e intervalos de bytes recebidos
+func (us *UploadService) Status(session *UploadSession) (*UploadStatus, error) {
+	members, err := database.RedisClient.SMembers(context.Background(), "upload:"+session.ID+":chunks").Result()
+	if err != nil {
+		return nil, err
+	}
+
+	total := session.TotalChunks()
+	received := make([]bool, total)
+	for _, member := range members {
+		if index, err := strconv.Atoi(member); err == nil && index >= 0 && index < total {
+			received[index] = true
+		}
+	}
+
+	status := &UploadStatus{
+		Session:        session,
+		TotalChunks:    total,
+		ReceivedChunks: []int{},
+		MissingChunks:  []int{},
+		ReceivedRanges: []ByteRange{},
+	}
+
+	for index, ok := range received {
+		if !ok {
+			status.MissingChunks = append(status.MissingChunks, index)
+			continue
+		}
+
+		status.ReceivedChunks = append(status.ReceivedChunks, index)
+		offset, length := session.ChunkRange(index)
+		status.ReceivedBytes += length
+
+		// Une chunks contíguos em um único intervalo
+		last := len(status.ReceivedRanges) - 1
+		if last >= 0 && status.ReceivedRanges[last].End+1 == offset {
+			status.ReceivedRanges[last].End = offset + length - 1
+		} else {
+			status.ReceivedRanges = append(status.ReceivedRanges, ByteRange{Start: offset, End: offset + length - 1})
+		}
+	}
+
+	status.Complete = len(status.MissingChunks) == 0
+	return status, nil
+}
+
+// Complete monta o arquivo, confere o checksum e cria o replay pelo ReplayService
+func (us *UploadService) Complete(session *UploadSession, user *models.User) (*models.Replay, error) {
+	ctx := context.Background()
+	lockKey := "upload:" + session.ID + ":lock"
+
+	locked, err := database.RedisClient.SetNX(ctx, lockKey, "1", 5*time.Minute).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !locked {
+		return nil, ErrUploadInProgress
+	}
+	defer database.RedisClient.Del(ctx, lockKey)
+
+	status, err := us.Status(session)
+	if err != nil {
+		return nil, err
+	}
+	if !status.Complete {
+		return nil, ErrUploadIncomplete
+	}
+
+	assembled := &chunkReader{session: session, total: status.TotalChunks}
+	staged, err := us.replayService.StageFile(assembled)
+	assembled.Close()
+	if err != nil {
+		return nil, err
+	}
+	defer staged.Remove()
+
+	if staged.FileSize != session.FileSize || staged.ContentHash != session.Checksum {
+		return nil, ErrUploadChecksum
+	}
+
+	replay, err := us.replayService.CreateFromUpload(user, session.OriginalName, staged, session.Metadata)
+	if err != nil {
+		// Replay duplicado nunca vai ser aceito: descarta os chunks
+		var duplicateErr *DuplicateReplayError
+		if errors.As(err, &duplicateErr) {
+			us.cleanup(session.ID)
+		}
+		return nil, err
+	}
+
+	us.cleanup(session.ID)
+	return replay, nil
+}
+
+// Abort cancela o upload e remove os chunks
+func (us *UploadService) Abort(session *UploadSession) {
+	us.cleanup(session.ID)
+}
+
+// StartGarbageCollector remove periodicamente as sessões expiradas e seus chunks
+func (us *UploadService) StartGarbageCollector(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if removed, err := us.CollectExpired(); err != nil {
+				log.Println("⚠️ Falha na limpeza de uploads expirados:", err)
+			} else if removed > 0 {
+				log.Printf("🧹 %d uploads expirados removidos", removed)
+			}
+		}
+	}()
+}
+
+// CollectExpired remove as sessões cuja validade passou. Sessões sendo
+// finalizadas são puladas: a limpeza pega a mesma trava do Complete e, com
+// ela, confere de novo a validade, que um chunk recente pode ter renovado.
+func (us *UploadService) CollectExpired() (int, error) {
+	ctx := context.Background()
+	now := time.Now().Unix()
+
+	uploadIDs, err := database.RedisClient.ZRangeByScore(ctx, "uploads:expiry", &redis.ZRangeBy{Min: "-inf", Max: strconv.FormatInt(now, 10)}).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, uploadID := range uploadIDs {
+		lockKey := "upload:" + uploadID + ":lock"
+		locked, err := database.RedisClient.SetNX(ctx, lockKey, "gc", time.Minute).Result()
+		if err != nil {
+			return removed, err
+		}
+		if !locked {
+			continue
+		}
+
+		expiresAt, err := database.RedisClient.ZScore(ctx, "uploads:expiry", uploadID).Result()
+		if err == nil && int64(expiresAt) <= now {
+			us.cleanup(uploadID)
+			removed++
+		}
+		database.RedisClient.Del(ctx, lockKey)
+	}
+
+	return removed, nil
+}
+
+// save grava a sessão renovando a validade
+func (us *UploadService) save(session *UploadSession) error {
+	session.ExpiresAt = time.Now().Add(config.AppConfig.UploadSessionTTL)
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	pipe := database.RedisClient.TxPipeline()
+	pipe.Set(ctx, "upload:"+session.ID, data, 0) // removida pela limpeza, que precisa dela para achar os chunks
+	pipe.ZAdd(ctx, "uploads:expiry", redis.Z{Score: float64(session.ExpiresAt.Unix()), Member: session.ID})
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// cleanup remove chunks, sessão e entrada de expiração
+func (us *UploadService) cleanup(uploadID string) {
+	ctx := context.Background()
+
+	if data, err := database.RedisClient.Get(ctx, "upload:"+uploadID).Result(); err == nil {
+		var session UploadSession
+		if json.Unmarshal([]byte(data), &session) == nil {
+			for index := 0; index < session.TotalChunks(); index++ {
+				storage.Store.Delete(ctx, chunkKey(uploadID, index))
+			}
+		}
+	}
+
+	pipe := database.RedisClient.TxPipeline()
+	pipe.Del(ctx, "upload:"+uploadID, "upload:"+uploadID+":chunks")
+	pipe.ZRem(ctx, "uploads:expiry", uploadID)
+	pipe.Exec(ctx)
+}
+
+func chunkKey(uploadID string, index int) string {
+	return fmt.Sprintf("uploads/%s/%d", uploadID, index)
+}
+
+// chunkReader lê os chunks do storage em sequência, como um único arquivo
+type chunkReader struct {
+	session *UploadSession
+	total   int
+	next    int
+	current storage.Object
+}
+
+func (cr *chunkReader) Read(p []byte) (int, error) {
+	for {
+		if cr.current == nil {
+			if cr.next >= cr.total {
+				return 0, io.EOF
+			}
+			object, err := storage.Store.Get(context.Background(), chunkKey(cr.session.ID, cr.next))
+			if err != nil {
+				return 0, fmt.Errorf("falha ao ler chunk %d: %w", cr.next, err)
+			}
+			cr.current = object
+			cr.next++
+		}
+
+		n, err := cr.current.Read(p)
+		if err == io.EOF {
+			cr.current.Close()
+			cr.current = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (cr *chunkReader) Close() error {
+	if cr.current != nil {
+		err := cr.current.Close()
+		cr.current = nil
+		return err
+	}
+	return nil
+}
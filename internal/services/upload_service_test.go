@@ -0,0 +1,233 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"wardscore-api/internal/config"
+	"wardscore-api/internal/database"
+	"wardscore-api/internal/models"
+	"wardscore-api/internal/storage"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// newTestUploadService usa Redis em memória, storage local temporário e chunks de 4 bytes
+func newTestUploadService(t *testing.T) (*UploadService, *miniredis.Miniredis) {
+	t.Helper()
+	redisServer := useTestRedis(t)
+
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := storage.Store
+	storage.Store = store
+	t.Cleanup(func() { storage.Store = previous })
+
+	useTestConfig(t, func(cfg *config.Config) {
+		cfg.MaxReplaySize = 1 << 20
+		cfg.UploadChunkSize = 4
+		cfg.UploadSessionTTL = time.Hour
+	})
+	return NewUploadService(NewReplayService()), redisServer
+}
+
+func newTestUpload(t *testing.T, us *UploadService, content []byte) *UploadSession {
+	t.Helper()
+	session, err := us.Create(7, "partida.rofl", int64(len(content)), sha256Hex(content), UploadMetadata{})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return session
+}
+
+func putChunks(t *testing.T, us *UploadService, session *UploadSession, content []byte, indexes ...int) {
+	t.Helper()
+	for _, index := range indexes {
+		offset, length := session.ChunkRange(index)
+		chunk := content[offset : offset+length]
+		if err := us.PutChunk(session, index, offset, bytes.NewReader(chunk), sha256Hex(chunk)); err != nil {
+			t.Fatalf("PutChunk(%d): %v", index, err)
+		}
+	}
+}
+
+func TestUploadStatusMergesRanges(t *testing.T) {
+	us, _ := newTestUploadService(t)
+	content := []byte("0123456789abcdefghij-") // 21 bytes: 5 chunks de 4 e um de 1
+	session := newTestUpload(t, us, content)
+
+	putChunks(t, us, session, content, 0, 1, 3, 5)
+
+	status, err := us.Status(session)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if status.TotalChunks != 6 || status.Complete {
+		t.Errorf("total = %d, completo = %v; esperado 6 e incompleto", status.TotalChunks, status.Complete)
+	}
+	if !reflect.DeepEqual(status.ReceivedChunks, []int{0, 1, 3, 5}) || !reflect.DeepEqual(status.MissingChunks, []int{2, 4}) {
+		t.Errorf("recebidos = %v, faltando = %v", status.ReceivedChunks, status.MissingChunks)
+	}
+	wantRanges := []ByteRange{{Start: 0, End: 7}, {Start: 12, End: 15}, {Start: 20, End: 20}}
+	if !reflect.DeepEqual(status.ReceivedRanges, wantRanges) {
+		t.Errorf("intervalos = %v, esperado %v", status.ReceivedRanges, wantRanges)
+	}
+	if status.ReceivedBytes != 13 {
+		t.Errorf("bytes recebidos = %d, esperado 13", status.ReceivedBytes)
+	}
+
+	// Reenviar um chunk não duplica a contagem
+	putChunks(t, us, session, content, 2, 4, 2)
+	status, _ = us.Status(session)
+	if !status.Complete || status.ReceivedBytes != 21 || !reflect.DeepEqual(status.ReceivedRanges, []ByteRange{{Start: 0, End: 20}}) {
+		t.Errorf("status completo = %+v", status)
+	}
+}
+
+func TestUploadPutChunkRejections(t *testing.T) {
+	us, _ := newTestUploadService(t)
+	content := []byte("0123456789")
+	session := newTestUpload(t, us, content)
+
+	tests := []struct {
+		name     string
+		index    int
+		offset   int64
+		body     string
+		checksum string
+		want     error
+	}{
+		{"índice negativo", -1, -1, "0123", "", ErrChunkOutOfRange},
+		{"índice além do arquivo", 3, -1, "x", "", ErrChunkOutOfRange},
+		{"offset de outro chunk", 1, 0, "4567", "", ErrChunkOffsetMismatch},
+		{"chunk curto", 0, 0, "012", "", ErrChunkSizeMismatch},
+		{"chunk longo", 0, 0, "01234", "", ErrChunkSizeMismatch},
+		{"último chunk longo", 2, 8, "89X", "", ErrChunkSizeMismatch},
+		{"checksum errado", 1, 4, "4567", sha256Hex([]byte("0123")), ErrChunkChecksum},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := us.PutChunk(session, tt.index, tt.offset, strings.NewReader(tt.body), tt.checksum)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("erro = %v, esperado %v", err, tt.want)
+			}
+		})
+	}
+
+	status, _ := us.Status(session)
+	if len(status.ReceivedChunks) != 0 {
+		t.Errorf("chunks rejeitados foram gravados: %v", status.ReceivedChunks)
+	}
+
+	// Offset negativo e checksum vazio dispensam as checagens opcionais
+	if err := us.PutChunk(session, 2, -1, strings.NewReader("89"), ""); err != nil {
+		t.Errorf("último chunk sem checagens opcionais: %v", err)
+	}
+}
+
+func TestUploadCompleteRejectsWholeFileChecksum(t *testing.T) {
+	us, redisServer := newTestUploadService(t)
+	content, err := os.ReadFile("../rofl/testdata/valid.rofl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Sessão aberta com o checksum de outro arquivo do mesmo tamanho
+	other := append([]byte{}, content...)
+	other[len(other)-1] ^= 0xff
+	session, err := us.Create(7, "partida.rofl", int64(len(content)), sha256Hex(other), UploadMetadata{})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if _, err := us.Complete(session, &models.User{}); !errors.Is(err, ErrUploadIncomplete) {
+		t.Errorf("Complete sem chunks: erro = %v, esperado ErrUploadIncomplete", err)
+	}
+
+	indexes := make([]int, session.TotalChunks())
+	for index := range indexes {
+		indexes[index] = index
+	}
+	putChunks(t, us, session, content, indexes...)
+
+	if _, err := us.Complete(session, &models.User{}); !errors.Is(err, ErrUploadChecksum) {
+		t.Fatalf("Complete: erro = %v, esperado ErrUploadChecksum", err)
+	}
+	// A trava é liberada e os chunks continuam lá para uma nova tentativa
+	if redisServer.Exists("upload:" + session.ID + ":lock") {
+		t.Errorf("trava da finalização não liberada")
+	}
+	if status, _ := us.Status(session); !status.Complete {
+		t.Errorf("chunks descartados após checksum errado")
+	}
+
+	redisServer.Set("upload:"+session.ID+":lock", "1")
+	if _, err := us.Complete(session, &models.User{}); !errors.Is(err, ErrUploadInProgress) {
+		t.Errorf("Complete concorrente: erro = %v, esperado ErrUploadInProgress", err)
+	}
+}
+
+func TestUploadCollectExpired(t *testing.T) {
+	us, redisServer := newTestUploadService(t)
+	content := []byte("0123456789")
+	ctx := context.Background()
+
+	expired := newTestUpload(t, us, content)
+	putChunks(t, us, expired, content, 0, 1)
+	finalizing := newTestUpload(t, us, content)
+	putChunks(t, us, finalizing, content, 0)
+	active := newTestUpload(t, us, content)
+
+	// Vence as duas primeiras sessões; a terceira continua válida
+	past := float64(time.Now().Add(-time.Minute).Unix())
+	redisServer.ZAdd("uploads:expiry", past, expired.ID)
+	redisServer.ZAdd("uploads:expiry", past, finalizing.ID)
+	redisServer.Set("upload:"+finalizing.ID+":lock", "1")
+
+	removed, err := us.CollectExpired()
+	if err != nil || removed != 1 {
+		t.Fatalf("CollectExpired = %d (%v), esperado 1", removed, err)
+	}
+
+	if redisServer.Exists("upload:"+expired.ID) || redisServer.Exists("upload:"+expired.ID+":chunks") {
+		t.Errorf("sessão expirada não removida do Redis")
+	}
+	if _, err := storage.Store.Stat(ctx, chunkKey(expired.ID, 0)); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("chunk da sessão expirada não removido: %v", err)
+	}
+	if redisServer.Exists("upload:" + expired.ID + ":lock") {
+		t.Errorf("trava da limpeza não liberada")
+	}
+
+	// A sessão em finalização fica intacta
+	if !redisServer.Exists("upload:"+finalizing.ID) || !redisServer.Exists("upload:"+finalizing.ID+":lock") {
+		t.Errorf("sessão em finalização removida")
+	}
+	if _, err := storage.Store.Stat(ctx, chunkKey(finalizing.ID, 0)); err != nil {
+		t.Errorf("chunk da sessão em finalização removido: %v", err)
+	}
+	if _, err := us.Get(7, active.ID); err != nil {
+		t.Errorf("sessão ativa removida: %v", err)
+	}
+
+	// Liberada a trava, a próxima limpeza remove a sessão
+	database.RedisClient.Del(ctx, "upload:"+finalizing.ID+":lock")
+	if removed, _ := us.CollectExpired(); removed != 1 || redisServer.Exists("upload:"+finalizing.ID) {
+		t.Errorf("sessão liberada não removida (removidas = %d)", removed)
+	}
+}
@@ -51,6 +51,63 @@ func (us *UserService) GetByRiotID(riotID string) (*models.User, error) {
     return &user, nil
 }
 
+// GetByPUUID busca usuário pelo PUUID da conta Riot
+func (us *UserService) GetByPUUID(puuid string) (*models.User, error) {
+    var user models.User
+    result := database.DB.Where("puuid = ?", puuid).First(&user)
+    if result.Error != nil {
+        return nil, errors.New("usuário não encontrado")
+    }
+    return &user, nil
+}
+
+// LoginWithRiotAccount cria ou vincula o usuário da conta Riot autenticada.
+// Com linkUserID != 0 a conta é vinculada ao usuário existente; caso contrário
+// o usuário é encontrado pelo PUUID ou criado. Retorna true quando um usuário foi criado.
+func (us *UserService) LoginWithRiotAccount(account *RiotAccount, linkUserID uint) (*models.User, bool, error) {
+    existing, _ := us.GetByPUUID(account.PUUID)
+
+    if linkUserID != 0 {
+        if existing != nil && existing.ID != linkUserID {
+            return nil, false, errors.New("conta Riot já vinculada a outro usuário")
+        }
+
+        user, err := us.GetByID(linkUserID)
+        if err != nil {
+            return nil, false, err
+        }
+        user.PUUID = account.PUUID
+        user.RiotID = account.RiotID()
+        user.GameName = account.GameName
+        user.TagLine = account.TagLine
+
+        user, err = us.Update(user)
+        return user, false, err
+    }
+
+    if existing != nil {
+        // Riot ID pode mudar; PUUID é estável
+        existing.RiotID = account.RiotID()
+        existing.GameName = account.GameName
+        existing.TagLine = account.TagLine
+
+        user, err := us.Update(existing)
+        return user, false, err
+    }
+
+    user, err := us.Create(&models.User{
+        PUUID:    account.PUUID,
+        RiotID:   account.RiotID(),
+        GameName: account.GameName,
+        TagLine:  account.TagLine,
+    })
+    if err != nil {
+        return nil, false, err
+    }
+
+    return user, true, nil
+}
+
 // Create cria novo usuário
 func (us *UserService) Create(user *models.User) (*models.User, error) {
     // Verificar duplicatas
@@ -59,10 +116,12 @@ func (us *UserService) Create(user *models.User) (*models.User, error) {
         return nil, errors.New("usuário com este Riot ID já existe")
     }
 
-    var count int64
-    database.DB.Model(&models.User{}).Where("email = ?", user.Email).Count(&count)
-    if count > 0 {
-        return nil, errors.New("usuário com este email já existe")
+    if user.Email != nil {
+        var count int64
+        database.DB.Model(&models.User{}).Where("email = ?", *user.Email).Count(&count)
+        if count > 0 {
+            return nil, errors.New("usuário com este email já existe")
+        }
     }
 
     result := database.DB.Create(user)
@@ -124,6 +183,50 @@ func (us *UserService) GetAll(page, limit int) ([]models.User, int64, error) {
     return users, total, nil
 }
 
+// Ban bane o usuário; as sessões devem ser revogadas pelo chamador
+func (us *UserService) Ban(id uint, reason string) (*models.User, error) {
+    user, err := us.GetByID(id)
+    if err != nil {
+        return nil, err
+    }
+
+    now := time.Now()
+    user.BannedAt = &now
+    user.BanReason = reason
+
+    return us.Update(user)
+}
+
+// Unban remove o banimento do usuário
+func (us *UserService) Unban(id uint) (*models.User, error) {
+    user, err := us.GetByID(id)
+    if err != nil {
+        return nil, err
+    }
+
+    user.BannedAt = nil
+    user.BanReason = ""
+
+    return us.Update(user)
+}
+
+// SetRole altera o papel do usuário
+func (us *UserService) SetRole(id uint, role models.UserRole) (*models.User, error) {
+    if !role.IsValid() {
+        return nil, errors.New("papel inválido")
+    }
+
+    user, err := us.GetByID(id)
+    if err != nil {
+        return nil, err
+    }
+
+    user.Role = role
+    user.IsPro = role == models.RolePro
+
+    return us.Update(user)
+}
+
 // GetUserWithReplays busca usuário com replays
 func (us *UserService) GetUserWithReplays(id uint) (*models.User, error) {
     var user models.User
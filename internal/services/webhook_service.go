@@ -0,0 +1,567 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"wardscore-api/internal/config"
+	"wardscore-api/internal/database"
+	"wardscore-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrWebhookNotFound  = errors.New("webhook não encontrado")
+	ErrDeliveryNotFound = errors.New("entrega não encontrada")
+	ErrWebhookURL       = errors.New("URL do webhook inválida")
+	errPrivateAddress   = errors.New("endereço de rede interna não permitido")
+)
+
+const (
+	// WebhookSecretPrefix identifica os secrets de assinatura
+	WebhookSecretPrefix = "whsec_"
+
+	webhookRetryBaseDelay = 30 * time.Second
+	webhookRetryMaxDelay  = 6 * time.Hour
+
+	// maxWebhookResponseBody é quanto da resposta do destino fica no log
+	maxWebhookResponseBody = 2048
+)
+
+// WebhookEnvelope é o corpo enviado em cada entrega. UserID é o usuário do
+// evento, que nos webhooks de time identifica o membro.
+type WebhookEnvelope struct {
+	ID        string              `json:"id"`
+	Type      models.WebhookEvent `json:"type"`
+	CreatedAt time.Time           `json:"created_at"`
+	UserID    uint                `json:"user_id"`
+	Data      interface{}         `json:"data"`
+}
+
+// WebhookUpdate são os campos alteráveis de um webhook (nil mantém o valor)
+type WebhookUpdate struct {
+	URL         *string               `json:"url"`
+	Description *string               `json:"description"`
+	Events      []models.WebhookEvent `json:"events"`
+	Active      *bool                 `json:"active"`
+}
+
+// WebhookService gerencia as assinaturas e entrega os eventos.
+//
+// Cada evento vira uma linha em webhook_deliveries por webhook assinante; os
+// workers pegam as entregas pendentes (SELECT ... FOR UPDATE SKIP LOCKED) e
+// fazem o POST assinado, com nova tentativa em backoff até WebhookMaxAttempts.
+type WebhookService struct {
+	client *http.Client
+}
+
+func NewWebhookService() *WebhookService {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: webhookDialControl,
+	}
+
+	return &WebhookService{
+		client: &http.Client{
+			Timeout: config.AppConfig.WebhookTimeout,
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: 5 * time.Second,
+				MaxIdleConnsPerHost: 2,
+			},
+			// Redirecionamentos não são seguidos; 3xx conta como falha
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Create cria o webhook do usuário ou, com teamID, de um time do qual ele é
+// dono. O secret de assinatura só é retornado aqui e na rotação.
+func (ws *WebhookService) Create(userID uint, teamID *uint, rawURL, description string, events []models.WebhookEvent) (*models.Webhook, error) {
+	if err := validateWebhookURL(rawURL); err != nil {
+		return nil, err
+	}
+	if teamID != nil {
+		var owned int64
+		if err := database.DB.Model(&models.Team{}).Where("id = ? AND owner_id = ?", *teamID, userID).Count(&owned).Error; err != nil {
+			return nil, err
+		}
+		if owned == 0 {
+			return nil, ErrTeamNotFound
+		}
+	}
+	eventsJSON, err := marshalWebhookEvents(events)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := &models.Webhook{
+		URL:         rawURL,
+		Description: description,
+		Secret:      secret,
+		Events:      eventsJSON,
+		Active:      true,
+		UserID:      userID,
+		TeamID:      teamID,
+	}
+
+	if result := database.DB.Create(webhook); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return webhook, nil
+}
+
+// GetByUserID lista os webhooks do usuário
+func (ws *WebhookService) GetByUserID(userID uint) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	result := database.DB.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&webhooks)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return webhooks, nil
+}
+
+// Get busca um webhook do usuário
+func (ws *WebhookService) Get(userID, id uint) (*models.Webhook, error) {
+	var webhook models.Webhook
+	result := database.DB.Where("id = ? AND user_id = ?", id, userID).First(&webhook)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrWebhookNotFound
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &webhook, nil
+}
+
+// Update altera URL, descrição, eventos ou se o webhook está ativo
+func (ws *WebhookService) Update(userID, id uint, input WebhookUpdate) (*models.Webhook, error) {
+	webhook, err := ws.Get(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if input.URL != nil {
+		if err := validateWebhookURL(*input.URL); err != nil {
+			return nil, err
+		}
+		updates["url"] = *input.URL
+	}
+	if input.Description != nil {
+		updates["description"] = *input.Description
+	}
+	if input.Events != nil {
+		eventsJSON, err := marshalWebhookEvents(input.Events)
+		if err != nil {
+			return nil, err
+		}
+		updates["events"] = eventsJSON
+	}
+	if input.Active != nil {
+		updates["active"] = *input.Active
+	}
+
+	if len(updates) > 0 {
+		if result := database.DB.Model(webhook).Updates(updates); result.Error != nil {
+			return nil, result.Error
+		}
+	}
+
+	return ws.Get(userID, id)
+}
+
+// RotateSecret gera um novo secret de assinatura para o webhook
+func (ws *WebhookService) RotateSecret(userID, id uint) (*models.Webhook, error) {
+	webhook, err := ws.Get(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if result := database.DB.Model(webhook).Update("secret", secret); result.Error != nil {
+		return nil, result.Error
+	}
+	return webhook, nil
+}
+
+// Delete remove o webhook; entregas pendentes são encerradas como falha
+func (ws *WebhookService) Delete(userID, id uint) error {
+	result := database.DB.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Webhook{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrWebhookNotFound
+	}
+
+	return database.DB.Model(&models.WebhookDelivery{}).
+		Where("webhook_id = ? AND status = ?", id, models.DeliveryPending).
+		Updates(map[string]interface{}{
+			"status":          models.DeliveryFailed,
+			"next_attempt_at": nil,
+			"error":           "webhook removido",
+		}).Error
+}
+
+// Dispatch registra uma entrega do evento para cada webhook ativo que o
+// assina: os do próprio usuário e os dos times dos quais ele é membro. A
+// entrega em si é feita pelos workers.
+func (ws *WebhookService) Dispatch(userID uint, event models.WebhookEvent, data interface{}) error {
+	var webhooks []models.Webhook
+	err := database.DB.
+		Where("active = ? AND ((user_id = ? AND team_id IS NULL) OR team_id IN (?))", true, userID, memberTeamIDs(userID)).
+		Find(&webhooks).Error
+	if err != nil {
+		return err
+	}
+
+	token, err := randomToken(16)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	envelope := WebhookEnvelope{
+		ID:        "evt_" + token,
+		Type:      event,
+		CreatedAt: now,
+		UserID:    userID,
+		Data:      data,
+	}
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	var deliveries []models.WebhookDelivery
+	for i := range webhooks {
+		if !webhooks[i].Subscribes(event) {
+			continue
+		}
+
+		deliveries = append(deliveries, models.WebhookDelivery{
+			EventID:       envelope.ID,
+			Event:         event,
+			Payload:       payload,
+			Status:        models.DeliveryPending,
+			NextAttemptAt: &now,
+			WebhookID:     webhooks[i].ID,
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return database.DB.Create(&deliveries).Error
+}
+
+// ClaimDue reserva até limit entregas vencidas para este worker. As linhas
+// reservadas têm next_attempt_at adiado pelo prazo do envio; se o worker
+// morrer, voltam a ficar disponíveis depois disso.
+func (ws *WebhookService) ClaimDue(limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	now := time.Now()
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.DeliveryPending, now).
+			Order("next_attempt_at").
+			Limit(limit).
+			Find(&deliveries)
+		if result.Error != nil || len(deliveries) == 0 {
+			return result.Error
+		}
+
+		ids := make([]uint, len(deliveries))
+		for i := range deliveries {
+			ids[i] = deliveries[i].ID
+		}
+		return tx.Model(&models.WebhookDelivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(ws.claimLease())).Error
+	})
+	if err != nil || len(deliveries) == 0 {
+		return nil, err
+	}
+
+	// Webhook removido (soft delete) fica nil e a entrega é encerrada no Deliver
+	for i := range deliveries {
+		var webhook models.Webhook
+		if database.DB.First(&webhook, deliveries[i].WebhookID).Error == nil {
+			deliveries[i].Webhook = &webhook
+		}
+	}
+
+	return deliveries, nil
+}
+
+// Deliver faz uma tentativa de entrega e registra o resultado no log
+func (ws *WebhookService) Deliver(delivery *models.WebhookDelivery) error {
+	if delivery.Webhook == nil || !delivery.Webhook.Active {
+		return ws.saveAttempt(delivery, models.DeliveryFailed, nil, "webhook removido ou desativado")
+	}
+
+	delivery.Attempts++
+	started := time.Now()
+	status, body, err := ws.send(delivery.Webhook, delivery)
+	delivery.DurationMs = time.Since(started).Milliseconds()
+	delivery.ResponseStatus = status
+	delivery.ResponseBody = body
+
+	if err == nil && status >= 200 && status < 300 {
+		now := time.Now()
+		delivery.DeliveredAt = &now
+		return ws.saveAttempt(delivery, models.DeliverySucceeded, nil, "")
+	}
+
+	message := fmt.Sprintf("destino respondeu HTTP %d", status)
+	if err != nil {
+		message = err.Error()
+	}
+
+	nextStatus, nextAttempt := nextDeliveryAttempt(delivery.Attempts, time.Now())
+	return ws.saveAttempt(delivery, nextStatus, nextAttempt, message)
+}
+
+// nextDeliveryAttempt decide o destino de uma entrega que falhou: nova
+// tentativa em backoff ou falha definitiva ao atingir WebhookMaxAttempts
+func nextDeliveryAttempt(attempts int, now time.Time) (models.DeliveryStatus, *time.Time) {
+	if attempts >= config.AppConfig.WebhookMaxAttempts {
+		return models.DeliveryFailed, nil
+	}
+
+	next := now.Add(backoffDelay(attempts, webhookRetryBaseDelay, webhookRetryMaxDelay))
+	return models.DeliveryPending, &next
+}
+
+// GetDeliveries lista o log de entregas de um webhook do usuário
+func (ws *WebhookService) GetDeliveries(userID, webhookID uint, status string, page, limit int) ([]models.WebhookDelivery, int64, error) {
+	if _, err := ws.Get(userID, webhookID); err != nil {
+		return nil, 0, err
+	}
+
+	query := database.DB.Model(&models.WebhookDelivery{}).Where("webhook_id = ?", webhookID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var deliveries []models.WebhookDelivery
+	offset := (page - 1) * limit
+	result := query.Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&deliveries)
+
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return deliveries, total, nil
+}
+
+// GetDelivery busca uma entrega de um webhook do usuário
+func (ws *WebhookService) GetDelivery(userID, webhookID, deliveryID uint) (*models.WebhookDelivery, error) {
+	if _, err := ws.Get(userID, webhookID); err != nil {
+		return nil, err
+	}
+
+	var delivery models.WebhookDelivery
+	result := database.DB.Where("id = ? AND webhook_id = ?", deliveryID, webhookID).First(&delivery)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, ErrDeliveryNotFound
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &delivery, nil
+}
+
+// Redeliver agenda o reenvio de uma entrega como uma nova entrega, com o
+// mesmo evento (mesmo id no corpo), para o destino deduplicar
+func (ws *WebhookService) Redeliver(userID, webhookID, deliveryID uint) (*models.WebhookDelivery, error) {
+	original, err := ws.GetDelivery(userID, webhookID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	delivery := &models.WebhookDelivery{
+		EventID:       original.EventID,
+		Event:         original.Event,
+		Payload:       original.Payload,
+		Status:        models.DeliveryPending,
+		NextAttemptAt: &now,
+		WebhookID:     original.WebhookID,
+	}
+
+	if result := database.DB.Create(delivery); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return delivery, nil
+}
+
+// send faz o POST assinado e retorna o status e o início da resposta
+func (ws *WebhookService) send(webhook *models.Webhook, delivery *models.WebhookDelivery) (int, string, error) {
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, "", err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "WardScore-Webhooks/1.0")
+	req.Header.Set("X-WardScore-Event", string(delivery.Event))
+	req.Header.Set("X-WardScore-Delivery", strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set("X-WardScore-Signature", "t="+timestamp+",v1="+SignWebhookPayload(webhook.Secret, timestamp, delivery.Payload))
+
+	resp, err := ws.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookResponseBody))
+	// Descarta o restante para reaproveitar a conexão
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	return resp.StatusCode, strings.ToValidUTF8(string(body), ""), nil
+}
+
+func (ws *WebhookService) saveAttempt(delivery *models.WebhookDelivery, status models.DeliveryStatus, nextAttempt *time.Time, message string) error {
+	delivery.Status = status
+	delivery.NextAttemptAt = nextAttempt
+	delivery.Error = message
+
+	return database.DB.Model(delivery).
+		Select("status", "attempts", "next_attempt_at", "delivered_at", "response_status", "response_body", "error", "duration_ms").
+		Updates(delivery).Error
+}
+
+// claimLease é o prazo de uma entrega reservada: tempo do envio com folga
+func (ws *WebhookService) claimLease() time.Duration {
+	return 2*config.AppConfig.WebhookTimeout + 30*time.Second
+}
+
+// SignWebhookPayload calcula a assinatura enviada em X-WardScore-Signature:
+// HMAC-SHA256 do secret sobre "<timestamp>.<corpo>", em hexadecimal
+func SignWebhookPayload(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newWebhookSecret() (string, error) {
+	secret, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	return WebhookSecretPrefix + secret, nil
+}
+
+func marshalWebhookEvents(events []models.WebhookEvent) (json.RawMessage, error) {
+	if len(events) == 0 {
+		return nil, errors.New("informe ao menos um evento")
+	}
+
+	seen := map[models.WebhookEvent]bool{}
+	unique := make([]models.WebhookEvent, 0, len(events))
+	for _, event := range events {
+		if !event.IsValid() {
+			return nil, errors.New("evento não suportado: " + string(event))
+		}
+		if !seen[event] {
+			seen[event] = true
+			unique = append(unique, event)
+		}
+	}
+
+	return json.Marshal(unique)
+}
+
+// validateWebhookURL exige URL http(s) absoluta, sem credenciais e, a menos
+// que WebhookAllowPrivateURLs esteja ligado, fora de localhost/rede interna
+func validateWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return fmt.Errorf("%w: use uma URL http ou https absoluta", ErrWebhookURL)
+	}
+	if parsed.User != nil {
+		return fmt.Errorf("%w: credenciais na URL não são permitidas", ErrWebhookURL)
+	}
+	if len(rawURL) > 2048 {
+		return fmt.Errorf("%w: URL muito longa", ErrWebhookURL)
+	}
+
+	if config.AppConfig.WebhookAllowPrivateURLs {
+		return nil
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") {
+		return fmt.Errorf("%w: %v", ErrWebhookURL, errPrivateAddress)
+	}
+	if ip := net.ParseIP(host); ip != nil && isPrivateIP(ip) {
+		return fmt.Errorf("%w: %v", ErrWebhookURL, errPrivateAddress)
+	}
+
+	return nil
+}
+
+// webhookDialControl recusa conexões com localhost e rede interna. Verifica o
+// IP já resolvido, o que cobre DNS apontando para rede interna.
+func webhookDialControl(network, address string, _ syscall.RawConn) error {
+	if config.AppConfig.WebhookAllowPrivateURLs {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || isPrivateIP(ip) {
+		return errPrivateAddress
+	}
+	return nil
+}
+
+func isPrivateIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast()
+}
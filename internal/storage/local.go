@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LocalStorage guarda objetos no sistema de arquivos, sob um diretório raiz
+type LocalStorage struct {
+	root string
+}
+
+func NewLocalStorage(root string) (*LocalStorage, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(absRoot, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalStorage{root: absRoot}, nil
+}
+
+// path resolve a chave dentro da raiz, rejeitando chaves que escapam dela
+func (ls *LocalStorage) path(key string) (string, error) {
+	full := filepath.Join(ls.root, filepath.FromSlash(key))
+	if full != ls.root && !strings.HasPrefix(full, ls.root+string(os.PathSeparator)) {
+		return "", fmt.Errorf("chave inválida: %s", key)
+	}
+	return full, nil
+}
+
+func (ls *LocalStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	dest, err := ls.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+
+	// Grava em arquivo temporário e renomeia para nunca expor objeto parcial
+	tmp, err := os.CreateTemp(filepath.Dir(dest), ".put-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, r)
+	closeErr := tmp.Close()
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+	if size >= 0 && written != size {
+		return fmt.Errorf("tamanho gravado (%d) difere do esperado (%d)", written, size)
+	}
+
+	return os.Rename(tmp.Name(), dest)
+}
+
+func (ls *LocalStorage) Get(ctx context.Context, key string) (Object, error) {
+	src, err := ls.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(src)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+func (ls *LocalStorage) Delete(ctx context.Context, key string) error {
+	target, err := ls.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (ls *LocalStorage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	target, err := ls.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(target)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &ObjectInfo{
+		Key:          key,
+		Size:         info.Size(),
+		ContentType:  "application/octet-stream",
+		LastModified: info.ModTime(),
+	}, nil
+}
+
+// PresignedURL não é suportado: arquivos locais são servidos pela própria API
+func (ls *LocalStorage) PresignedURL(ctx context.Context, key string, expiry time.Duration, contentDisposition string) (string, error) {
+	return "", ErrPresignNotSupported
+}
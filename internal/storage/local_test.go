@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalStorage(t *testing.T) {
+	root := t.TempDir()
+	store, err := NewLocalStorage(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	content := "conteúdo do replay"
+
+	if err := store.Put(ctx, "replays/ab/12.rofl", strings.NewReader(content), int64(len(content)), "application/octet-stream"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "replays", "ab", "12.rofl")); err != nil {
+		t.Errorf("arquivo não gravado sob a raiz: %v", err)
+	}
+
+	object, err := store.Get(ctx, "replays/ab/12.rofl")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if _, err := object.Seek(3, io.SeekStart); err != nil {
+		t.Errorf("Seek: %v", err)
+	}
+	data, _ := io.ReadAll(object)
+	object.Close()
+	if string(data) != content[3:] {
+		t.Errorf("conteúdo = %q, esperado %q", data, content[3:])
+	}
+
+	info, err := store.Stat(ctx, "replays/ab/12.rofl")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.Key != "replays/ab/12.rofl" || info.Size != int64(len(content)) || time.Since(info.LastModified) > time.Minute {
+		t.Errorf("Stat = %+v", info)
+	}
+
+	if err := store.Delete(ctx, "replays/ab/12.rofl"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, "replays/ab/12.rofl"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get após Delete = %v, esperado ErrNotFound", err)
+	}
+	if _, err := store.Stat(ctx, "replays/ab/12.rofl"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Stat após Delete = %v, esperado ErrNotFound", err)
+	}
+	if err := store.Delete(ctx, "replays/ab/12.rofl"); err != nil {
+		t.Errorf("Delete de objeto inexistente = %v, esperado nil", err)
+	}
+}
+
+func TestLocalStoragePutSizeMismatch(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "replays/curto.rofl", strings.NewReader("abc"), 10, ""); err == nil {
+		t.Fatalf("Put com tamanho diferente do informado aceito")
+	}
+	// O objeto parcial nunca fica visível
+	if _, err := store.Stat(ctx, "replays/curto.rofl"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Stat do objeto parcial = %v, esperado ErrNotFound", err)
+	}
+
+	// Tamanho desconhecido (-1) dispensa a conferência
+	if err := store.Put(ctx, "replays/sem-tamanho.rofl", strings.NewReader("abc"), -1, ""); err != nil {
+		t.Errorf("Put sem tamanho: %v", err)
+	}
+}
+
+func TestLocalStorageRejectsKeysOutsideRoot(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "replays")
+	store, err := NewLocalStorage(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	for _, key := range []string{"../fora.rofl", "a/../../fora.rofl"} {
+		if err := store.Put(ctx, key, strings.NewReader("x"), 1, ""); err == nil {
+			t.Errorf("Put(%q) aceito", key)
+		}
+		if _, err := store.Get(ctx, key); err == nil || errors.Is(err, ErrNotFound) {
+			t.Errorf("Get(%q) = %v, esperado chave inválida", key, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(root), "fora.rofl")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("arquivo gravado fora da raiz")
+	}
+}
+
+func TestLocalStoragePresignedURL(t *testing.T) {
+	store, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.PresignedURL(context.Background(), "replays/ab12.rofl", time.Minute, ""); !errors.Is(err, ErrPresignNotSupported) {
+		t.Errorf("PresignedURL = %v, esperado ErrPresignNotSupported", err)
+	}
+}
@@ -0,0 +1,272 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeS3Object struct {
+	data        []byte
+	contentType string
+	etag        string
+	modified    time.Time
+}
+
+// fakeS3 implementa o suficiente da API do S3 (path-style) para o S3Storage:
+// HEAD/PUT de bucket e PUT/GET/HEAD/DELETE de objeto
+type fakeS3 struct {
+	mu      sync.Mutex
+	buckets map[string]map[string]fakeS3Object
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	fake := &fakeS3{buckets: map[string]map[string]fakeS3Object{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	bucketName, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	bucket, bucketExists := f.buckets[bucketName]
+
+	if key == "" {
+		switch {
+		case r.Method == http.MethodPut:
+			f.buckets[bucketName] = map[string]fakeS3Object{}
+		case r.Method == http.MethodHead && !bucketExists:
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method != http.MethodHead:
+			w.WriteHeader(http.StatusNotImplemented)
+		}
+		return
+	}
+	if !bucketExists {
+		writeS3Error(w, r, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		data, err := readS3Body(r)
+		if err != nil {
+			writeS3Error(w, r, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		sum := md5.Sum(data)
+		object := fakeS3Object{
+			data:        data,
+			contentType: r.Header.Get("Content-Type"),
+			etag:        `"` + hex.EncodeToString(sum[:]) + `"`,
+			modified:    time.Now().UTC().Truncate(time.Second),
+		}
+		bucket[key] = object
+		w.Header().Set("ETag", object.etag)
+	case http.MethodGet, http.MethodHead:
+		object, ok := bucket[key]
+		if !ok {
+			writeS3Error(w, r, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("ETag", object.etag)
+		w.Header().Set("Content-Type", object.contentType)
+		http.ServeContent(w, r, key, object.modified, bytes.NewReader(object.data))
+	case http.MethodDelete:
+		delete(bucket, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func (f *fakeS3) object(bucket, key string) (fakeS3Object, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	object, ok := f.buckets[bucket][key]
+	return object, ok
+}
+
+// readS3Body lê o corpo do PUT, decodificando a assinatura em streaming
+// (aws-chunked) que o cliente usa em conexões sem TLS
+func readS3Body(r *http.Request) ([]byte, error) {
+	if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return io.ReadAll(r.Body)
+	}
+
+	reader := bufio.NewReader(r.Body)
+	var data bytes.Buffer
+	for {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		sizeHex, _, _ := strings.Cut(strings.TrimSpace(header), ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return data.Bytes(), nil
+		}
+		if _, err := io.CopyN(&data, reader, size); err != nil {
+			return nil, err
+		}
+		if _, err := reader.Discard(2); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func writeS3Error(w http.ResponseWriter, r *http.Request, status int, code string) {
+	if r.Method == http.MethodHead {
+		w.WriteHeader(status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>%s</Code><Message>%s</Message><Resource>%s</Resource></Error>`, code, code, r.URL.Path)
+}
+
+func newTestS3Storage(t *testing.T, server *httptest.Server) *S3Storage {
+	t.Helper()
+	store, err := NewS3Storage(S3Options{
+		Endpoint:  strings.TrimPrefix(server.URL, "http://"),
+		AccessKey: "wardscore",
+		SecretKey: "wardscore-secret",
+		Bucket:    "replays",
+		Region:    "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("NewS3Storage: %v", err)
+	}
+	return store
+}
+
+func TestS3Storage(t *testing.T) {
+	fake, server := newFakeS3(t)
+	store := newTestS3Storage(t, server)
+	ctx := context.Background()
+
+	fake.mu.Lock()
+	_, created := fake.buckets["replays"]
+	fake.mu.Unlock()
+	if !created {
+		t.Fatalf("bucket não criado")
+	}
+
+	content := []byte(strings.Repeat("rofl", 1024))
+	if err := store.Put(ctx, "replays/ab12.rofl", bytes.NewReader(content), int64(len(content)), "application/octet-stream"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	stored, ok := fake.object("replays", "replays/ab12.rofl")
+	if !ok || !bytes.Equal(stored.data, content) || stored.contentType != "application/octet-stream" {
+		t.Fatalf("objeto gravado = %d bytes (%s), esperado %d", len(stored.data), stored.contentType, len(content))
+	}
+
+	info, err := store.Stat(ctx, "replays/ab12.rofl")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.Key != "replays/ab12.rofl" || info.Size != int64(len(content)) || info.ETag != strings.Trim(stored.etag, `"`) || !info.LastModified.Equal(stored.modified) {
+		t.Errorf("Stat = %+v", info)
+	}
+
+	object, err := store.Get(ctx, "replays/ab12.rofl")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if _, err := object.Seek(4, io.SeekStart); err != nil {
+		t.Errorf("Seek: %v", err)
+	}
+	data, err := io.ReadAll(object)
+	object.Close()
+	if err != nil || !bytes.Equal(data, content[4:]) {
+		t.Errorf("Get = %d bytes (%v), esperado %d", len(data), err, len(content)-4)
+	}
+
+	if err := store.Delete(ctx, "replays/ab12.rofl"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, ok := fake.object("replays", "replays/ab12.rofl"); ok {
+		t.Errorf("objeto não removido")
+	}
+	if err := store.Delete(ctx, "replays/ab12.rofl"); err != nil {
+		t.Errorf("Delete de objeto inexistente = %v, esperado nil", err)
+	}
+}
+
+func TestS3StorageNotFound(t *testing.T) {
+	_, server := newFakeS3(t)
+	store := newTestS3Storage(t, server)
+	ctx := context.Background()
+
+	if _, err := store.Get(ctx, "replays/inexistente.rofl"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get = %v, esperado ErrNotFound", err)
+	}
+	if _, err := store.Stat(ctx, "replays/inexistente.rofl"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Stat = %v, esperado ErrNotFound", err)
+	}
+}
+
+func TestS3StoragePresignedURL(t *testing.T) {
+	_, server := newFakeS3(t)
+	store := newTestS3Storage(t, server)
+	content := "conteúdo"
+	store.Put(context.Background(), "replays/ab12.rofl", strings.NewReader(content), int64(len(content)), "application/octet-stream")
+
+	rawURL, err := store.PresignedURL(context.Background(), "replays/ab12.rofl", 15*time.Minute, `attachment; filename="partida.rofl"`)
+	if err != nil {
+		t.Fatalf("PresignedURL: %v", err)
+	}
+	presigned, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("URL inválida: %v", err)
+	}
+	query := presigned.Query()
+	if presigned.Host != strings.TrimPrefix(server.URL, "http://") || presigned.Path != "/replays/replays/ab12.rofl" {
+		t.Errorf("URL = %s", rawURL)
+	}
+	if query.Get("X-Amz-Signature") == "" || query.Get("X-Amz-Expires") != "900" {
+		t.Errorf("URL sem assinatura ou validade de 900 s: %s", rawURL)
+	}
+	if got := query.Get("response-content-disposition"); got != `attachment; filename="partida.rofl"` {
+		t.Errorf("response-content-disposition = %q", got)
+	}
+
+	// A URL baixa o objeto sem credenciais
+	resp, err := http.Get(rawURL)
+	if err != nil {
+		t.Fatalf("GET da URL pré-assinada: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != content {
+		t.Errorf("GET da URL pré-assinada = %d %q", resp.StatusCode, body)
+	}
+}
+
+func TestNewS3StorageRequiresEndpointAndBucket(t *testing.T) {
+	if _, err := NewS3Storage(S3Options{Bucket: "replays"}); err == nil {
+		t.Errorf("S3 sem endpoint aceito")
+	}
+	if _, err := NewS3Storage(S3Options{Endpoint: "localhost:9000"}); err == nil {
+		t.Errorf("S3 sem bucket aceito")
+	}
+}
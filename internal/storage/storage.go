@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"time"
+	"wardscore-api/internal/config"
+)
+
+var (
+	ErrNotFound            = errors.New("objeto não encontrado")
+	ErrPresignNotSupported = errors.New("backend não suporta URL pré-assinada")
+)
+
+// ObjectInfo descreve um objeto armazenado
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	ETag         string
+	LastModified time.Time
+}
+
+// Object é o conteúdo de um objeto com suporte a seek (necessário para Range)
+type Object interface {
+	io.ReadSeekCloser
+}
+
+// Storage é o backend onde os arquivos de replay são guardados
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (Object, error)
+	Delete(ctx context.Context, key string) error
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	// PresignedURL gera URL temporária de download; contentDisposition (opcional)
+	// é devolvido pelo backend no header Content-Disposition
+	PresignedURL(ctx context.Context, key string, expiry time.Duration, contentDisposition string) (string, error)
+}
+
+// Store é o backend configurado para a aplicação
+var Store Storage
+
+// Connect inicializa o backend definido em STORAGE_BACKEND
+func Connect() {
+	var err error
+
+	switch config.AppConfig.StorageBackend {
+	case "s3":
+		Store, err = NewS3Storage(S3Options{
+			Endpoint:  config.AppConfig.S3Endpoint,
+			AccessKey: config.AppConfig.S3AccessKey,
+			SecretKey: config.AppConfig.S3SecretKey,
+			Bucket:    config.AppConfig.S3Bucket,
+			Region:    config.AppConfig.S3Region,
+			UseSSL:    config.AppConfig.S3UseSSL,
+		})
+	case "local", "":
+		Store, err = NewLocalStorage(config.AppConfig.ReplayStorageDir)
+	default:
+		err = errors.New("STORAGE_BACKEND desconhecido: " + config.AppConfig.StorageBackend)
+	}
+
+	if err != nil {
+		log.Fatal("❌ Falha ao inicializar storage:", err)
+	}
+
+	log.Printf("✅ Storage de replays inicializado (%s)", config.AppConfig.StorageBackend)
+}
@@ -0,0 +1,232 @@
+// Package timeline lê a timeline de wards de uma partida: posição, tipo,
+// dono e tempo de vida de cada ward.
+//
+// O .rofl guarda as posições apenas nos chunks criptografados do jogo, que o
+// pacote rofl não decodifica, e a API de partidas da Riot não informa a
+// posição das wards. A timeline é produzida fora da API (por um extrator de
+// replays), enviada em PUT /api/v1/replays/:id/timeline e gravada no storage
+// ao lado do arquivo do replay, em KeyFor(replay.FilePath), no formato:
+//
+//	{
+//	  "wards": [
+//	    {"puuid": "...", "team": "100", "type": "CONTROL_WARD",
+//	     "x": 9870, "y": 4410, "placed_at": 312000, "removed_at": 401000}
+//	  ]
+//	}
+//
+// A timeline também traz os objetivos épicos da partida, para a análise de
+// setup de visão. Cada objetivo é um nascimento ("spawn") ou um abate
+// ("kill", com o time que abateu). Timelines enviadas sem objetivos são
+// completadas com os abates da API de partidas da Riot (match-v5):
+//
+//	"objectives": [
+//	  {"type": "DRAGON", "event": "kill", "team": "200", "at": 618000}
+//	]
+//
+// Coordenadas seguem o sistema do Summoner's Rift: x de 0 a MapWidth e y de 0
+// a MapHeight, com a origem no canto da base azul. Tempos em milissegundos
+// desde o início da partida; removed_at 0 indica que a ward durou até expirar
+// ou até o fim do jogo.
+package timeline
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+	"wardscore-api/internal/storage"
+)
+
+// Dimensões do Summoner's Rift no sistema de coordenadas do jogo
+const (
+	MapWidth  = 14870
+	MapHeight = 14980
+)
+
+// Tipos de ward
+const (
+	WardYellowTrinket = "YELLOW_TRINKET"
+	WardSight         = "SIGHT_WARD"
+	WardControl       = "CONTROL_WARD"
+	WardBlueTrinket   = "BLUE_TRINKET"
+)
+
+// Objetivos épicos
+const (
+	ObjectiveDragon = "DRAGON"
+	ObjectiveBaron  = "BARON_NASHOR"
+	ObjectiveHerald = "RIFTHERALD"
+)
+
+// Eventos de objetivo
+const (
+	ObjectiveSpawn = "spawn"
+	ObjectiveKill  = "kill"
+)
+
+// Limite defensivo para o JSON da timeline
+const maxTimelineLength = 16 * 1024 * 1024
+
+// ErrUnavailable indica que a partida não tem timeline de wards
+var ErrUnavailable = errors.New("timeline de wards indisponível")
+
+// InvalidError indica timeline com formato ou valores inválidos
+type InvalidError struct {
+	Reason string
+}
+
+func (e *InvalidError) Error() string {
+	return "timeline inválida: " + e.Reason
+}
+
+// Ward é uma ward colocada na partida
+type Ward struct {
+	PUUID     string `json:"puuid"`
+	Team      string `json:"team"`
+	Type      string `json:"type"`
+	X         int    `json:"x"`
+	Y         int    `json:"y"`
+	PlacedAt  int64  `json:"placed_at"`
+	RemovedAt int64  `json:"removed_at,omitempty"`
+}
+
+// Duração das wards que expiram sozinhas; control wards e a trinket azul
+// ficam até serem destruídas
+var wardDurations = map[string]time.Duration{
+	WardYellowTrinket: 90 * time.Second,
+	WardSight:         150 * time.Second,
+}
+
+// Lifetime é quanto tempo a ward ficou no mapa, em ms. Sem removed_at, a ward
+// dura até expirar (wards com duração fixa) ou até gameEnd (ms), o que vier
+// primeiro.
+func (w Ward) Lifetime(gameEnd int64) int64 {
+	end := w.RemovedAt
+	if end == 0 {
+		end = gameEnd
+		if duration, ok := wardDurations[w.Type]; ok && w.PlacedAt+duration.Milliseconds() < end {
+			end = w.PlacedAt + duration.Milliseconds()
+		}
+	}
+	if end < w.PlacedAt {
+		return 0
+	}
+	return end - w.PlacedAt
+}
+
+// Objective é o nascimento ou o abate de um objetivo épico
+type Objective struct {
+	Type  string `json:"type"`
+	Event string `json:"event"`
+	Team  string `json:"team,omitempty"`
+	At    int64  `json:"at"`
+}
+
+// Timeline são as wards, de todos os jogadores, e os objetivos da partida
+type Timeline struct {
+	Wards      []Ward      `json:"wards"`
+	Objectives []Objective `json:"objectives,omitempty"`
+}
+
+// KeyFor retorna a chave da timeline do replay armazenado em replayKey
+func KeyFor(replayKey string) string {
+	return strings.TrimSuffix(replayKey, path.Ext(replayKey)) + ".timeline.json"
+}
+
+// Load lê a timeline do replay armazenado em replayKey
+func Load(ctx context.Context, store storage.Storage, replayKey string) (*Timeline, error) {
+	object, err := store.Get(ctx, KeyFor(replayKey))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, ErrUnavailable
+		}
+		return nil, fmt.Errorf("falha ao abrir timeline: %w", err)
+	}
+	defer object.Close()
+
+	return Parse(object)
+}
+
+// Save grava a timeline do replay armazenado em replayKey, substituindo a
+// anterior
+func Save(ctx context.Context, store storage.Storage, replayKey string, timeline *Timeline) error {
+	data, err := json.Marshal(timeline)
+	if err != nil {
+		return err
+	}
+	if err := store.Put(ctx, KeyFor(replayKey), bytes.NewReader(data), int64(len(data)), "application/json"); err != nil {
+		return fmt.Errorf("falha ao gravar timeline: %w", err)
+	}
+	return nil
+}
+
+// Parse lê e valida uma timeline em JSON
+func Parse(r io.Reader) (*Timeline, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxTimelineLength+1))
+	if err != nil {
+		return nil, fmt.Errorf("falha ao ler timeline: %w", err)
+	}
+	if len(data) > maxTimelineLength {
+		return nil, &InvalidError{Reason: "arquivo maior que o limite"}
+	}
+
+	var timeline Timeline
+	if err := json.Unmarshal(data, &timeline); err != nil {
+		return nil, &InvalidError{Reason: err.Error()}
+	}
+
+	for i, ward := range timeline.Wards {
+		if ward.X < 0 || ward.X > MapWidth || ward.Y < 0 || ward.Y > MapHeight {
+			return nil, &InvalidError{Reason: fmt.Sprintf("ward %d fora do mapa (%d, %d)", i, ward.X, ward.Y)}
+		}
+		if ward.PlacedAt < 0 || (ward.RemovedAt != 0 && ward.RemovedAt < ward.PlacedAt) {
+			return nil, &InvalidError{Reason: fmt.Sprintf("ward %d com tempos inválidos", i)}
+		}
+	}
+
+	for i, objective := range timeline.Objectives {
+		switch objective.Type {
+		case ObjectiveDragon, ObjectiveBaron, ObjectiveHerald:
+		default:
+			return nil, &InvalidError{Reason: fmt.Sprintf("objetivo %d com tipo desconhecido: %s", i, objective.Type)}
+		}
+		if objective.Event != ObjectiveSpawn && objective.Event != ObjectiveKill {
+			return nil, &InvalidError{Reason: fmt.Sprintf("objetivo %d com evento desconhecido: %s", i, objective.Event)}
+		}
+		if objective.At < 0 {
+			return nil, &InvalidError{Reason: fmt.Sprintf("objetivo %d com tempo inválido", i)}
+		}
+	}
+
+	return &timeline, nil
+}
+
+// WardsOf retorna as wards colocadas pelo jogador (nunca nil)
+func (t *Timeline) WardsOf(puuid string) []Ward {
+	wards := []Ward{}
+	if puuid == "" {
+		return wards
+	}
+	for _, ward := range t.Wards {
+		if ward.PUUID == puuid {
+			wards = append(wards, ward)
+		}
+	}
+	return wards
+}
+
+// CountBefore conta as wards colocadas antes do instante informado da partida
+func CountBefore(wards []Ward, at time.Duration) int {
+	count := 0
+	for _, ward := range wards {
+		if ward.PlacedAt < at.Milliseconds() {
+			count++
+		}
+	}
+	return count
+}
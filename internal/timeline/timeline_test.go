@@ -0,0 +1,87 @@
+package timeline
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"wardscore-api/internal/storage"
+)
+
+func TestKeyFor(t *testing.T) {
+	tests := []struct {
+		replayKey string
+		want      string
+	}{
+		{"replays/ab12.rofl", "replays/ab12.timeline.json"},
+		{"replays/ab12", "replays/ab12.timeline.json"},
+	}
+
+	for _, tt := range tests {
+		if got := KeyFor(tt.replayKey); got != tt.want {
+			t.Errorf("KeyFor(%q) = %q, esperado %q", tt.replayKey, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		invalid bool
+	}{
+		{"vazia", `{"wards": []}`, false},
+		{"ward e objetivo", `{"wards": [{"puuid": "p1", "team": "100", "type": "CONTROL_WARD", "x": 9870, "y": 4410, "placed_at": 312000, "removed_at": 401000}],
+			"objectives": [{"type": "DRAGON", "event": "kill", "team": "200", "at": 618000}]}`, false},
+		{"JSON inválido", `{"wards": [`, true},
+		{"ward fora do mapa", `{"wards": [{"x": 15000, "y": 100}]}`, true},
+		{"ward removida antes de ser colocada", `{"wards": [{"x": 100, "y": 100, "placed_at": 5000, "removed_at": 4000}]}`, true},
+		{"objetivo desconhecido", `{"objectives": [{"type": "ATAKHAN", "event": "kill", "at": 1000}]}`, true},
+		{"evento desconhecido", `{"objectives": [{"type": "DRAGON", "event": "steal", "at": 1000}]}`, true},
+		{"objetivo com tempo negativo", `{"objectives": [{"type": "BARON_NASHOR", "event": "spawn", "at": -1}]}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.input))
+			var invalidErr *InvalidError
+			if tt.invalid && !errors.As(err, &invalidErr) {
+				t.Errorf("erro = %v, esperado *InvalidError", err)
+			}
+			if !tt.invalid && err != nil {
+				t.Errorf("erro inesperado: %v", err)
+			}
+		})
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	store, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if _, err := Load(ctx, store, "replays/ab12.rofl"); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("Load sem timeline = %v, esperado ErrUnavailable", err)
+	}
+
+	saved := &Timeline{
+		Wards:      []Ward{{PUUID: "p1", Team: "100", Type: WardControl, X: 9870, Y: 4410, PlacedAt: 312000}},
+		Objectives: []Objective{{Type: ObjectiveDragon, Event: ObjectiveKill, Team: "200", At: 618000}},
+	}
+	if err := Save(ctx, store, "replays/ab12.rofl", saved); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := Load(ctx, store, "replays/ab12.rofl")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(loaded.Wards) != 1 || loaded.Wards[0] != saved.Wards[0] {
+		t.Errorf("wards = %+v, esperado %+v", loaded.Wards, saved.Wards)
+	}
+	if len(loaded.Objectives) != 1 || loaded.Objectives[0] != saved.Objectives[0] {
+		t.Errorf("objetivos = %+v, esperado %+v", loaded.Objectives, saved.Objectives)
+	}
+}
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrTokenExpired = errors.New("token expirado")
+	ErrTokenInvalid = errors.New("token inválido")
+)
+
+// AccessClaims são as claims do access token emitido pela API
+type AccessClaims struct {
+	UserID    uint   `json:"user_id"`
+	SessionID string `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+// GenerateJWT gera um access token assinado (HS256) para o usuário, vinculado à sessão
+func GenerateJWT(userID uint, sessionID, secret string, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+
+	claims := AccessClaims{
+		UserID:    userID,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.FormatUint(uint64(userID), 10),
+			Issuer:    "wardscore-api",
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString([]byte(secret))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return signed, expiresAt, nil
+}
+
+// ValidateJWT valida assinatura e expiração do token e retorna suas claims
+func ValidateJWT(tokenString, secret string) (*AccessClaims, error) {
+	claims := &AccessClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrTokenInvalid
+		}
+		return []byte(secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer("wardscore-api"))
+
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrTokenExpired
+		}
+		return nil, ErrTokenInvalid
+	}
+
+	if !token.Valid || claims.UserID == 0 || claims.SessionID == "" {
+		return nil, ErrTokenInvalid
+	}
+
+	return claims, nil
+}
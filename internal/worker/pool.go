@@ -0,0 +1,346 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+	"wardscore-api/internal/config"
+	"wardscore-api/internal/models"
+	"wardscore-api/internal/services"
+)
+
+const (
+	webhookBatchSize    = 20
+	webhookPollInterval = 5 * time.Second
+)
+
+// Pool executa os jobs da fila de processamento de replays com N workers e
+// faz as entregas de webhooks pendentes.
+// Pode rodar dentro da API (WORKER_ENABLED) ou no binário cmd/worker.
+type Pool struct {
+	jobService         *services.JobService
+	analysisService    *services.AnalysisService
+	replayService      *services.ReplayService
+	progressService    *services.ProgressService
+	webhookService     *services.WebhookService
+	achievementService *services.AchievementService
+
+	concurrency int
+	stop        chan struct{}
+	wg          sync.WaitGroup
+}
+
+func NewPool(jobService *services.JobService, analysisService *services.AnalysisService, replayService *services.ReplayService) *Pool {
+	concurrency := config.AppConfig.WorkerConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	return &Pool{
+		jobService:         jobService,
+		analysisService:    analysisService,
+		replayService:      replayService,
+		progressService:    services.NewProgressService(),
+		webhookService:     services.NewWebhookService(),
+		achievementService: services.NewAchievementService(),
+		concurrency:        concurrency,
+		stop:               make(chan struct{}),
+	}
+}
+
+// Start inicia os workers e a rotina de manutenção da fila
+func (p *Pool) Start() {
+	for i := 0; i < p.concurrency; i++ {
+		p.wg.Add(1)
+		go p.work(i + 1)
+	}
+
+	p.wg.Add(1)
+	go p.sweep()
+
+	p.wg.Add(1)
+	go p.deliverWebhooks()
+
+	log.Printf("⚙️ Pool de workers iniciado (%d workers)", p.concurrency)
+}
+
+// Shutdown para de pegar jobs novos e espera os jobs em andamento terminarem.
+// Se ctx expirar antes, os jobs restantes voltam para a fila quando o lease vencer.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	close(p.stop)
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Println("✅ Workers finalizados")
+		return nil
+	case <-ctx.Done():
+		return errors.New("tempo esgotado aguardando jobs em andamento")
+	}
+}
+
+func (p *Pool) work(workerID int) {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		default:
+		}
+
+		job, err := p.jobService.Dequeue(context.Background())
+		if err != nil {
+			log.Printf("⚠️ Worker %d: falha ao buscar job: %v", workerID, err)
+		}
+		if job == nil {
+			p.wait(config.AppConfig.JobPollInterval)
+			continue
+		}
+
+		p.run(workerID, job)
+	}
+}
+
+// run processa o job renovando o lease até terminar
+func (p *Pool) run(workerID int, job *services.Job) {
+	replay, err := p.replayService.StartAttempt(job.ReplayID)
+	if err != nil {
+		if errors.Is(err, services.ErrReplayNotFound) {
+			p.jobService.Fail(job, err)
+			return
+		}
+		p.retryJob(workerID, job, err, time.Now().Add(services.RetryDelay(job.Attempts)))
+		return
+	}
+
+	// Tentativas anteriores interrompidas (worker morto) também contam
+	if replay.Attempts > config.AppConfig.JobMaxAttempts {
+		p.fail(workerID, job, replay, errors.New("tentativas de processamento esgotadas"), true)
+		return
+	}
+
+	replay.MarkAsProcessing()
+	p.publish(replay, job, "iniciando", 0)
+
+	heartbeatDone := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(config.AppConfig.JobLease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-heartbeatDone:
+				return
+			case <-ticker.C:
+				p.jobService.Heartbeat(job)
+			}
+		}
+	}()
+
+	analysis, err := p.analysisService.ProcessReplay(job.ReplayID, func(stage string, percent int) {
+		p.publish(replay, job, stage, percent)
+	})
+	close(heartbeatDone)
+
+	if errors.Is(err, services.ErrReplayAlreadyProcessed) {
+		err = nil
+	}
+
+	if err != nil {
+		p.fail(workerID, job, replay, err, services.IsPermanentError(err))
+		return
+	}
+
+	replay.MarkAsCompleted()
+	completed := p.progressService.FromReplay(replay)
+	completed.JobID = job.ID
+	if analysis != nil {
+		completed.AnalysisID = analysis.ID
+	}
+	p.progressService.Publish(completed)
+
+	if err := p.jobService.Complete(job, analysis); err != nil {
+		log.Printf("⚠️ Worker %d: falha ao concluir job %s: %v", workerID, job.ID, err)
+		return
+	}
+	log.Printf("✅ Worker %d: replay %d processado (job %s)", workerID, job.ReplayID, job.ID)
+
+	if analysis != nil {
+		p.dispatch(replay.UserID, models.EventAnalysisCompleted, map[string]interface{}{
+			"replay_id":       replay.ID,
+			"match_id":        replay.MatchID,
+			"analysis_id":     analysis.ID,
+			"scoring_version": analysis.ScoringVersion,
+			"ward_score":      analysis.WardScore,
+			"rank":            analysis.Rank,
+		})
+
+		change, err := p.analysisService.GetRankChange(analysis)
+		if err != nil {
+			log.Printf("⚠️ Worker %d: falha ao comparar rank da análise %d: %v", workerID, analysis.ID, err)
+		} else if change != nil {
+			p.dispatch(replay.UserID, models.EventRankingTierChanged, map[string]interface{}{
+				"replay_id": replay.ID,
+				"match_id":  replay.MatchID,
+				"previous":  change.Previous,
+				"current":   change.Current,
+			})
+		}
+
+		unlocked, err := p.achievementService.EvaluateForAnalysis(analysis)
+		if err != nil {
+			log.Printf("⚠️ Worker %d: falha ao avaliar conquistas da análise %d: %v", workerID, analysis.ID, err)
+		}
+		for _, achievement := range unlocked {
+			p.dispatch(replay.UserID, models.EventAchievementUnlocked, map[string]interface{}{
+				"replay_id":      replay.ID,
+				"match_id":       replay.MatchID,
+				"achievement_id": achievement.AchievementID,
+				"title":          achievement.Achievement.Title,
+				"description":    achievement.Achievement.Description,
+				"category":       achievement.Achievement.Category,
+				"rarity":         achievement.Achievement.Rarity,
+				"icon":           achievement.Achievement.Icon,
+				"unlocked_at":    achievement.UnlockedAt,
+			})
+		}
+	}
+}
+
+// fail registra a falha no replay e agenda nova tentativa ou encerra o job
+// (dead letter)
+func (p *Pool) fail(workerID int, job *services.Job, replay *models.Replay, cause error, permanent bool) {
+	retryAt, err := p.replayService.RecordFailure(replay, cause, permanent)
+	if err != nil {
+		log.Printf("⚠️ Worker %d: falha ao registrar erro do replay %d: %v", workerID, replay.ID, err)
+	}
+	p.publish(replay, job, "", 0)
+
+	if retryAt != nil {
+		p.retryJob(workerID, job, cause, *retryAt)
+		return
+	}
+
+	log.Printf("☠️ Worker %d: replay %d foi para dead letter após %d tentativas: %v", workerID, replay.ID, replay.Attempts, cause)
+	if err := p.jobService.Fail(job, cause); err != nil {
+		log.Printf("⚠️ Worker %d: falha ao registrar erro do job %s: %v", workerID, job.ID, err)
+	}
+
+	p.dispatch(replay.UserID, models.EventReplayFailed, map[string]interface{}{
+		"replay_id": replay.ID,
+		"match_id":  replay.MatchID,
+		"attempts":  replay.Attempts,
+		"error":     replay.LastError,
+	})
+}
+
+func (p *Pool) retryJob(workerID int, job *services.Job, cause error, retryAt time.Time) {
+	log.Printf("🔁 Worker %d: job %s (replay %d) falhou, nova tentativa às %s: %v", workerID, job.ID, job.ReplayID, retryAt.Format(time.RFC3339), cause)
+	if err := p.jobService.Retry(job, cause, retryAt); err != nil {
+		log.Printf("⚠️ Worker %d: falha ao agendar nova tentativa do job %s: %v", workerID, job.ID, err)
+	}
+}
+
+// publish envia o andamento do replay para os inscritos (SSE)
+func (p *Pool) publish(replay *models.Replay, job *services.Job, stage string, percent int) {
+	progress := p.progressService.FromReplay(replay)
+	progress.JobID = job.ID
+	if replay.Status == models.StatusProcessing {
+		progress.Stage = stage
+		progress.Progress = percent
+	}
+
+	if err := p.progressService.Publish(progress); err != nil {
+		log.Printf("⚠️ Falha ao publicar progresso do replay %d: %v", replay.ID, err)
+	}
+}
+
+// dispatch registra o evento para os webhooks do usuário
+func (p *Pool) dispatch(userID uint, event models.WebhookEvent, data map[string]interface{}) {
+	if err := p.webhookService.Dispatch(userID, event, data); err != nil {
+		log.Printf("⚠️ Falha ao registrar webhook %s do usuário %d: %v", event, userID, err)
+	}
+}
+
+// deliverWebhooks entrega os webhooks pendentes (novos e novas tentativas).
+// As entregas de um lote são feitas em paralelo para que um destino lento
+// não segure os demais.
+func (p *Pool) deliverWebhooks() {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		default:
+		}
+
+		deliveries, err := p.webhookService.ClaimDue(webhookBatchSize)
+		if err != nil {
+			log.Println("⚠️ Falha ao buscar entregas de webhooks:", err)
+		}
+
+		var batch sync.WaitGroup
+		for i := range deliveries {
+			batch.Add(1)
+			go func(delivery *models.WebhookDelivery) {
+				defer batch.Done()
+				if err := p.webhookService.Deliver(delivery); err != nil {
+					log.Printf("⚠️ Falha ao registrar entrega %d do webhook %d: %v", delivery.ID, delivery.WebhookID, err)
+				}
+			}(&deliveries[i])
+		}
+		batch.Wait()
+
+		if len(deliveries) == webhookBatchSize {
+			continue
+		}
+		if !p.wait(webhookPollInterval) {
+			return
+		}
+	}
+}
+
+// sweep devolve para a fila jobs de workers que morreram e enfileira replays
+// pendentes que ficaram sem job
+func (p *Pool) sweep() {
+	defer p.wg.Done()
+
+	for {
+		if requeued, err := p.jobService.RequeueExpired(); err != nil {
+			log.Println("⚠️ Falha ao reenfileirar jobs expirados:", err)
+		} else if requeued > 0 {
+			log.Printf("🔁 %d jobs com lease expirado voltaram para a fila", requeued)
+		}
+
+		if enqueued, err := p.jobService.EnqueuePending(p.replayService); err != nil {
+			log.Println("⚠️ Falha ao enfileirar replays pendentes:", err)
+		} else if enqueued > 0 {
+			log.Printf("📥 %d replays pendentes enfileirados", enqueued)
+		}
+
+		if !p.wait(config.AppConfig.JobSweepInterval) {
+			return
+		}
+	}
+}
+
+// wait dorme pelo intervalo; retorna false se o pool foi parado
+func (p *Pool) wait(interval time.Duration) bool {
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	select {
+	case <-p.stop:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
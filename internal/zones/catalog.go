@@ -0,0 +1,180 @@
+// Package zones define as zonas do Summoner's Rift (rotas, rio, selvas,
+// bases, covis de objetivos e arbustos) como polígonos nomeados e a posição
+// dos covis, classifica as wards da timeline em zonas e resume a visão de
+// cada zona numa partida.
+//
+// As zonas ficam em catálogos versionados: quando o mapa muda, um novo
+// catálogo é adicionado a Catalogs com o primeiro patch em que vale, e as
+// partidas são classificadas pelo catálogo do patch em que foram jogadas.
+package zones
+
+import (
+	"strconv"
+	"strings"
+	"wardscore-api/internal/timeline"
+)
+
+// Tipos de zona
+const (
+	KindBase      = "base"
+	KindLane      = "lane"
+	KindRiver     = "river"
+	KindJungle    = "jungle"
+	KindObjective = "objective"
+	KindBrush     = "brush"
+)
+
+// Lados do mapa; zonas neutras não têm lado
+const (
+	SideBlue = "blue"
+	SideRed  = "red"
+)
+
+// Zone é uma região nomeada do mapa
+type Zone struct {
+	Name    string  `json:"name"`
+	Kind    string  `json:"kind"`
+	Side    string  `json:"side,omitempty"`
+	Polygon Polygon `json:"polygon"`
+}
+
+// Pit é o covil de um objetivo épico (timeline.ObjectiveDragon, ...): o
+// centro e o raio considerado "em volta do covil" no setup de visão
+type Pit struct {
+	Objective   string  `json:"objective"`
+	Zone        string  `json:"zone"`
+	Center      Point   `json:"center"`
+	SetupRadius float64 `json:"setup_radius"`
+}
+
+// Catalog é uma versão das zonas do mapa. A ordem das zonas é a precedência
+// na classificação: zonas menores (covis, arbustos) vêm antes das regiões
+// que as contêm.
+type Catalog struct {
+	Version  int    `json:"version"`
+	MinPatch string `json:"min_patch"`
+	Zones    []Zone `json:"zones"`
+	Pits     []Pit  `json:"pits"`
+}
+
+// UnknownZone é a zona das posições fora de todos os polígonos
+const UnknownZone = "unknown"
+
+// Catalogs são os catálogos do mapa, da versão mais antiga para a mais nova
+var Catalogs = []*Catalog{riftV1()}
+
+// Current retorna o catálogo mais recente
+func Current() *Catalog {
+	return Catalogs[len(Catalogs)-1]
+}
+
+// Get retorna o catálogo da versão informada
+func Get(version int) (*Catalog, bool) {
+	for _, catalog := range Catalogs {
+		if catalog.Version == version {
+			return catalog, true
+		}
+	}
+	return nil, false
+}
+
+// ForGameVersion retorna o catálogo em vigor no patch da partida
+// ("14.3.565.1234"). Versões ilegíveis usam o catálogo mais recente.
+func ForGameVersion(gameVersion string) *Catalog {
+	major, minor, ok := parsePatch(gameVersion)
+	if !ok {
+		return Current()
+	}
+
+	selected := Catalogs[0]
+	for _, catalog := range Catalogs {
+		minMajor, minMinor, _ := parsePatch(catalog.MinPatch)
+		if major > minMajor || (major == minMajor && minor >= minMinor) {
+			selected = catalog
+		}
+	}
+	return selected
+}
+
+// Classify retorna a zona da posição, ou nil fora de todas as zonas
+func (c *Catalog) Classify(x, y float64) *Zone {
+	point := Point{X: x, Y: y}
+	for i := range c.Zones {
+		if c.Zones[i].Polygon.Contains(point) {
+			return &c.Zones[i]
+		}
+	}
+	return nil
+}
+
+// Pit retorna o covil do objetivo, ou nil se o catálogo não o conhece
+func (c *Catalog) Pit(objective string) *Pit {
+	for i := range c.Pits {
+		if c.Pits[i].Objective == objective {
+			return &c.Pits[i]
+		}
+	}
+	return nil
+}
+
+func parsePatch(version string) (major, minor int, ok bool) {
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err = strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
+// riftV1 é o Summoner's Rift desde a atualização visual de 2014 (patch 4.20).
+//
+// Os polígonos do lado azul seguem as bordas aproximadas do mapa: base no
+// canto inferior esquerdo, rota do topo ao longo das bordas esquerda e
+// superior, rota do meio na diagonal entre as bases e rio na diagonal
+// oposta (x + y entre 13625 e 16225). As zonas do lado vermelho são o
+// espelho das azuis.
+func riftV1() *Catalog {
+	blueBase := Polygon{{0, 0}, {4200, 0}, {4200, 2200}, {2200, 4200}, {0, 4200}}
+	topLane := Polygon{{0, 4200}, {2200, 4200}, {2200, 12780}, {10670, 12780}, {10670, 14980}, {0, 14980}}
+	midLane := Polygon{{3700, 2700}, {12170, 11280}, {11170, 12280}, {2700, 3700}}
+	river := Polygon{{2200, 11425}, {2200, 12780}, {3445, 12780}, {12670, 3555}, {12670, 2200}, {11425, 2200}}
+	blueTopJungle := Polygon{{2200, 4200}, {2700, 3700}, {6290, 7336}, {2200, 11425}}
+	blueBotJungle := Polygon{{3700, 2700}, {4200, 2200}, {11425, 2200}, {7290, 6336}}
+	blueTriBush := rect(2300, 9200, 3300, 10200)
+	dragon := Point{9866, 4414}
+	baron := Point{5007, 10471}
+
+	return &Catalog{
+		Version:  1,
+		MinPatch: "4.20",
+		Zones: []Zone{
+			{Name: "dragon_pit", Kind: KindObjective, Polygon: octagon(dragon.X, dragon.Y, 750)},
+			{Name: "baron_pit", Kind: KindObjective, Polygon: octagon(baron.X, baron.Y, 750)},
+			{Name: "blue_tri_bush", Kind: KindBrush, Side: SideBlue, Polygon: blueTriBush},
+			{Name: "red_tri_bush", Kind: KindBrush, Side: SideRed, Polygon: blueTriBush.Mirror()},
+			{Name: "blue_base", Kind: KindBase, Side: SideBlue, Polygon: blueBase},
+			{Name: "red_base", Kind: KindBase, Side: SideRed, Polygon: blueBase.Mirror()},
+			{Name: "mid_lane", Kind: KindLane, Polygon: midLane},
+			{Name: "top_lane", Kind: KindLane, Polygon: topLane},
+			{Name: "bot_lane", Kind: KindLane, Polygon: topLane.Mirror()},
+			{Name: "river", Kind: KindRiver, Polygon: river},
+			{Name: "blue_top_jungle", Kind: KindJungle, Side: SideBlue, Polygon: blueTopJungle},
+			{Name: "blue_bot_jungle", Kind: KindJungle, Side: SideBlue, Polygon: blueBotJungle},
+			{Name: "red_top_jungle", Kind: KindJungle, Side: SideRed, Polygon: blueBotJungle.Mirror()},
+			{Name: "red_bot_jungle", Kind: KindJungle, Side: SideRed, Polygon: blueTopJungle.Mirror()},
+		},
+		// O arauto nasce no covil do barão
+		Pits: []Pit{
+			{Objective: timeline.ObjectiveDragon, Zone: "dragon_pit", Center: dragon, SetupRadius: 2500},
+			{Objective: timeline.ObjectiveBaron, Zone: "baron_pit", Center: baron, SetupRadius: 2500},
+			{Objective: timeline.ObjectiveHerald, Zone: "baron_pit", Center: baron, SetupRadius: 2500},
+		},
+	}
+}
@@ -0,0 +1,52 @@
+package zones
+
+import "wardscore-api/internal/timeline"
+
+// Point é uma posição no sistema de coordenadas do Summoner's Rift
+// (timeline.MapWidth × timeline.MapHeight, origem na base azul)
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Polygon é um polígono simples, com os vértices em ordem
+type Polygon []Point
+
+// Contains indica se o ponto está dentro do polígono (ray casting; pontos
+// na borda podem cair em qualquer um dos lados)
+func (p Polygon) Contains(point Point) bool {
+	inside := false
+	for i, j := 0, len(p)-1; i < len(p); j, i = i, i+1 {
+		a, b := p[i], p[j]
+		if (a.Y > point.Y) != (b.Y > point.Y) &&
+			point.X < (b.X-a.X)*(point.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Mirror reflete o polígono pelo centro do mapa. O Summoner's Rift é
+// simétrico em relação ao centro, então as zonas do lado vermelho são o
+// espelho das do lado azul.
+func (p Polygon) Mirror() Polygon {
+	mirrored := make(Polygon, len(p))
+	for i, point := range p {
+		mirrored[i] = Point{X: timeline.MapWidth - point.X, Y: timeline.MapHeight - point.Y}
+	}
+	return mirrored
+}
+
+// rect cria um retângulo a partir de dois cantos opostos
+func rect(x1, y1, x2, y2 float64) Polygon {
+	return Polygon{{x1, y1}, {x2, y1}, {x2, y2}, {x1, y2}}
+}
+
+// octagon aproxima um círculo de centro e raio informados
+func octagon(cx, cy, r float64) Polygon {
+	d := r * 0.7071
+	return Polygon{
+		{cx + r, cy}, {cx + d, cy + d}, {cx, cy + r}, {cx - d, cy + d},
+		{cx - r, cy}, {cx - d, cy - d}, {cx, cy - r}, {cx + d, cy - d},
+	}
+}
@@ -0,0 +1,104 @@
+package zones
+
+import (
+	"math"
+	"wardscore-api/internal/timeline"
+)
+
+// Relação da zona com o time do jogador
+const (
+	RelationAlly    = "ally"
+	RelationEnemy   = "enemy"
+	RelationNeutral = "neutral"
+)
+
+// ZoneStats resume as wards do jogador numa zona
+type ZoneStats struct {
+	Zone                 string  `json:"zone"`
+	Kind                 string  `json:"kind,omitempty"`
+	Side                 string  `json:"side,omitempty"`
+	Relation             string  `json:"relation"`
+	Wards                int     `json:"wards"`
+	ControlWards         int     `json:"control_wards"`
+	TotalLifetimeSeconds float64 `json:"total_lifetime_seconds"`
+	AvgLifetimeSeconds   float64 `json:"avg_lifetime_seconds"`
+}
+
+// Report é a visão do jogador por zona numa partida. Traz todas as zonas do
+// catálogo, inclusive as sem wards, e a zona UnknownZone quando alguma ward
+// cai fora dos polígonos.
+type Report struct {
+	CatalogVersion int         `json:"catalog_version"`
+	Wards          int         `json:"wards"`
+	Zones          []ZoneStats `json:"zones"`
+}
+
+// Report classifica as wards do jogador, que jogou no lado informado, numa
+// partida de gameEnd ms
+func (c *Catalog) Report(wards []timeline.Ward, side string, gameEnd int64) *Report {
+	report := &Report{
+		CatalogVersion: c.Version,
+		Wards:          len(wards),
+		Zones:          make([]ZoneStats, len(c.Zones)),
+	}
+	for i, zone := range c.Zones {
+		report.Zones[i] = ZoneStats{
+			Zone:     zone.Name,
+			Kind:     zone.Kind,
+			Side:     zone.Side,
+			Relation: relation(zone.Side, side),
+		}
+	}
+
+	var unknown *ZoneStats
+	for _, ward := range wards {
+		var stats *ZoneStats
+		if zone := c.Classify(float64(ward.X), float64(ward.Y)); zone != nil {
+			stats = &report.Zones[c.index(zone.Name)]
+		} else {
+			if unknown == nil {
+				unknown = &ZoneStats{Zone: UnknownZone, Relation: RelationNeutral}
+			}
+			stats = unknown
+		}
+
+		stats.Wards++
+		if ward.Type == timeline.WardControl {
+			stats.ControlWards++
+		}
+		stats.TotalLifetimeSeconds += float64(ward.Lifetime(gameEnd)) / 1000
+	}
+	if unknown != nil {
+		report.Zones = append(report.Zones, *unknown)
+	}
+
+	for i := range report.Zones {
+		stats := &report.Zones[i]
+		if stats.Wards > 0 {
+			stats.AvgLifetimeSeconds = math.Round(stats.TotalLifetimeSeconds/float64(stats.Wards)*10) / 10
+		}
+		stats.TotalLifetimeSeconds = math.Round(stats.TotalLifetimeSeconds*10) / 10
+	}
+	return report
+}
+
+func (c *Catalog) index(name string) int {
+	for i := range c.Zones {
+		if c.Zones[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// relation indica se a zona é do time do jogador, do inimigo ou neutra
+func relation(zoneSide, playerSide string) string {
+	switch {
+	case zoneSide == "" || playerSide == "":
+		return RelationNeutral
+	case zoneSide == playerSide:
+		return RelationAlly
+	default:
+		return RelationEnemy
+	}
+}
@@ -0,0 +1,148 @@
+package zones
+
+import (
+	"testing"
+	"wardscore-api/internal/timeline"
+)
+
+func TestClassify(t *testing.T) {
+	catalog := riftV1()
+
+	tests := []struct {
+		name string
+		x, y float64
+		want string
+	}{
+		{"base azul", 1000, 1000, "blue_base"},
+		{"base vermelha", 13870, 13980, "red_base"},
+		{"rota do topo", 1000, 8000, "top_lane"},
+		{"rota do meio", 7435, 7490, "mid_lane"},
+		{"rota de baixo", 8000, 1000, "bot_lane"},
+		{"rio perto do barão", 4000, 10700, "river"},
+		{"rio perto do dragão", 11000, 3200, "river"},
+		{"covil do dragão", 9866, 4414, "dragon_pit"},
+		{"covil do barão", 5007, 10471, "baron_pit"},
+		{"tri-bush azul", 2800, 9700, "blue_tri_bush"},
+		{"tri-bush vermelho", 12070, 5280, "red_tri_bush"},
+		{"selva azul do topo", 3500, 7000, "blue_top_jungle"},
+		{"selva azul de baixo", 7000, 3500, "blue_bot_jungle"},
+		{"selva vermelha do topo", 7870, 11480, "red_top_jungle"},
+		{"selva vermelha de baixo", 11370, 7980, "red_bot_jungle"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zone := catalog.Classify(tt.x, tt.y)
+			if zone == nil {
+				t.Fatalf("(%v, %v) fora de todas as zonas, esperado %s", tt.x, tt.y, tt.want)
+			}
+			if zone.Name != tt.want {
+				t.Errorf("(%v, %v) = %s, esperado %s", tt.x, tt.y, zone.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestPit(t *testing.T) {
+	catalog := riftV1()
+
+	tests := []struct {
+		objective string
+		zone      string
+	}{
+		{timeline.ObjectiveDragon, "dragon_pit"},
+		{timeline.ObjectiveBaron, "baron_pit"},
+		{timeline.ObjectiveHerald, "baron_pit"},
+	}
+
+	for _, tt := range tests {
+		pit := catalog.Pit(tt.objective)
+		if pit == nil {
+			t.Errorf("covil de %s não encontrado", tt.objective)
+			continue
+		}
+		if pit.Zone != tt.zone {
+			t.Errorf("covil de %s = %s, esperado %s", tt.objective, pit.Zone, tt.zone)
+		}
+		if zone := catalog.Classify(pit.Center.X, pit.Center.Y); zone == nil || zone.Name != tt.zone {
+			t.Errorf("centro do covil de %s fora da zona %s", tt.objective, tt.zone)
+		}
+	}
+	if pit := catalog.Pit("ATAKHAN"); pit != nil {
+		t.Errorf("covil de objetivo desconhecido = %+v, esperado nil", pit)
+	}
+}
+
+func TestForGameVersion(t *testing.T) {
+	original := Catalogs
+	defer func() { Catalogs = original }()
+
+	v1 := riftV1()
+	v2 := &Catalog{Version: 2, MinPatch: "14.1"}
+	Catalogs = []*Catalog{v1, v2}
+
+	tests := []struct {
+		gameVersion string
+		want        int
+	}{
+		{"4.20.123", 1},
+		{"13.24.550.1234", 1},
+		{"14.1.2", 2},
+		{"14.3.565.1234", 2},
+		{"15.1", 2},
+		// Patches anteriores ao primeiro catálogo usam o mais antigo
+		{"3.15", 1},
+		// Versões ilegíveis usam o mais recente
+		{"", 2},
+		{"desconhecida", 2},
+		{"14", 2},
+	}
+
+	for _, tt := range tests {
+		if got := ForGameVersion(tt.gameVersion); got.Version != tt.want {
+			t.Errorf("ForGameVersion(%q) = versão %d, esperado %d", tt.gameVersion, got.Version, tt.want)
+		}
+	}
+
+	if catalog, ok := Get(1); !ok || catalog != v1 {
+		t.Errorf("Get(1) = %v, %v", catalog, ok)
+	}
+	if _, ok := Get(3); ok {
+		t.Errorf("Get(3) encontrou catálogo inexistente")
+	}
+	if Current() != v2 {
+		t.Errorf("Current() = versão %d, esperado 2", Current().Version)
+	}
+}
+
+func TestReport(t *testing.T) {
+	catalog := riftV1()
+	wards := []timeline.Ward{
+		{Type: timeline.WardControl, X: 9866, Y: 4414, PlacedAt: 600000, RemovedAt: 660000},
+		{Type: timeline.WardYellowTrinket, X: 9900, Y: 4400, PlacedAt: 700000},
+		{Type: timeline.WardSight, X: 11370, Y: 7980, PlacedAt: 800000},
+	}
+
+	report := catalog.Report(wards, SideBlue, 1800000)
+	if report.CatalogVersion != 1 || report.Wards != 3 {
+		t.Fatalf("relatório = versão %d com %d wards", report.CatalogVersion, report.Wards)
+	}
+
+	stats := map[string]ZoneStats{}
+	for _, zone := range report.Zones {
+		stats[zone.Zone] = zone
+	}
+	if got := stats["dragon_pit"]; got.Wards != 2 || got.ControlWards != 1 || got.Relation != RelationNeutral {
+		t.Errorf("dragon_pit = %+v", got)
+	}
+	// 60 s da control ward removida e 90 s da trinket que expirou
+	if got := stats["dragon_pit"].TotalLifetimeSeconds; got != 150 {
+		t.Errorf("tempo de vida no dragon_pit = %v, esperado 150", got)
+	}
+	if got := stats["red_bot_jungle"]; got.Wards != 1 || got.Relation != RelationEnemy {
+		t.Errorf("red_bot_jungle = %+v", got)
+	}
+	if got := stats["blue_top_jungle"]; got.Wards != 0 || got.Relation != RelationAlly {
+		t.Errorf("blue_top_jungle = %+v", got)
+	}
+}